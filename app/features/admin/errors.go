@@ -0,0 +1,11 @@
+package admin
+
+const (
+	MsgUserNotFound     = "That user no longer exists"
+	MsgInvalidRole      = "That role does not exist"
+	MsgCannotChangeSelf = "You cannot change your own account here"
+	MsgUserUpdateFailed = "The user could not be updated, please try again"
+	MsgUserDisabled     = "The user was disabled and signed out everywhere"
+	MsgUserEnabled      = "The user was enabled"
+	MsgRoleChanged      = "The user's role was changed"
+)
@@ -0,0 +1,204 @@
+package admin
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+
+	"northstar/app/features/admin/pages"
+	"northstar/app/features/auth/gen/authdb"
+	"northstar/app/middleware"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/starfederation/datastar-go/datastar"
+)
+
+const usersPerPage = 20
+
+// roles are the roles admins can give users.
+var roles = []string{middleware.RoleUser, middleware.RoleAdmin}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+type Handlers struct {
+	db      *sql.DB
+	queries *authdb.Queries
+	users   *middleware.UserCache
+}
+
+func NewHandlers(db *sql.DB, users *middleware.UserCache) *Handlers {
+	return &Handlers{
+		db:      db,
+		queries: authdb.New(db),
+		users:   users,
+	}
+}
+
+// usersSignals are the search and page the admin is looking at, sent along
+// with every request from the page.
+type usersSignals struct {
+	Search string `json:"search"`
+	Page   int    `json:"page"`
+}
+
+// usersView returns the page of users matching search. Pages past the last
+// one show the last one.
+func (h *Handlers) usersView(r *http.Request, signals usersSignals, message string) (pages.UsersView, error) {
+	search := strings.TrimSpace(signals.Search)
+	pattern := "%" + likeEscaper.Replace(search) + "%"
+
+	total, err := h.queries.CountUsers(r.Context(), pattern)
+	if err != nil {
+		return pages.UsersView{}, fmt.Errorf("failed to count users: %w", err)
+	}
+	pageCount := max(1, int((total+usersPerPage-1)/usersPerPage))
+	page := min(max(signals.Page, 1), pageCount)
+
+	users, err := h.queries.ListUsers(r.Context(), authdb.ListUsersParams{
+		Search: pattern,
+		Limit:  usersPerPage,
+		Offset: int64((page - 1) * usersPerPage),
+	})
+	if err != nil {
+		return pages.UsersView{}, fmt.Errorf("failed to list users: %w", err)
+	}
+
+	return pages.UsersView{
+		Users:         users,
+		Roles:         roles,
+		Search:        search,
+		Page:          page,
+		PageCount:     pageCount,
+		Total:         total,
+		CurrentUserID: middleware.GetUserIDFromContext(r.Context()),
+		Message:       message,
+	}, nil
+}
+
+func (h *Handlers) AdminPage(w http.ResponseWriter, r *http.Request) {
+	view, err := h.usersView(r, usersSignals{Page: 1}, "")
+	if err != nil {
+		slog.Error("Failed to load users", "error", err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	if err := pages.AdminPage(view).Render(r.Context(), w); err != nil {
+		slog.Error("Failed to render admin page", "error", err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+	}
+}
+
+// Users sends the users matching the search and page in the signals.
+func (h *Handlers) Users(w http.ResponseWriter, r *http.Request) {
+	h.patchUsers(w, r, "")
+}
+
+func (h *Handlers) DisableUser(w http.ResponseWriter, r *http.Request) {
+	h.updateUser(w, r, MsgUserDisabled, func(ctx context.Context, userID string) (int64, error) {
+		return h.setDisabled(ctx, userID, true)
+	})
+}
+
+func (h *Handlers) EnableUser(w http.ResponseWriter, r *http.Request) {
+	h.updateUser(w, r, MsgUserEnabled, func(ctx context.Context, userID string) (int64, error) {
+		return h.setDisabled(ctx, userID, false)
+	})
+}
+
+func (h *Handlers) SetRole(w http.ResponseWriter, r *http.Request) {
+	role := chi.URLParam(r, "role")
+	if !slices.Contains(roles, role) {
+		h.patchUsers(w, r, MsgInvalidRole)
+		return
+	}
+
+	h.updateUser(w, r, MsgRoleChanged, func(ctx context.Context, userID string) (int64, error) {
+		return h.queries.SetUserRole(ctx, authdb.SetUserRoleParams{
+			Role: role,
+			ID:   userID,
+		})
+	})
+}
+
+// updateUser applies update to the user in the URL and sends the users back
+// with message. Admins cannot change themselves, so they cannot lock
+// themselves out.
+func (h *Handlers) updateUser(w http.ResponseWriter, r *http.Request, message string, update func(ctx context.Context, userID string) (int64, error)) {
+	userID := chi.URLParam(r, "id")
+	if userID == middleware.GetUserIDFromContext(r.Context()) {
+		h.patchUsers(w, r, MsgCannotChangeSelf)
+		return
+	}
+
+	rows, err := update(r.Context(), userID)
+	switch {
+	case err != nil:
+		slog.Error("Failed to update user", "user_id", userID, "error", err)
+		message = MsgUserUpdateFailed
+	case rows == 0:
+		message = MsgUserNotFound
+	default:
+		h.users.Invalidate(userID)
+		slog.Info("User updated by admin", "user_id", userID, "admin_id", middleware.GetUserIDFromContext(r.Context()), "path", r.URL.Path)
+	}
+
+	h.patchUsers(w, r, message)
+}
+
+// setDisabled disables or enables the user. Either way their sessions end,
+// so a disabled user is signed out everywhere at once.
+func (h *Handlers) setDisabled(ctx context.Context, userID string, disabled bool) (int64, error) {
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+	queries := h.queries.WithTx(tx)
+
+	rows, err := queries.SetUserDisabled(ctx, authdb.SetUserDisabledParams{
+		Disabled: disabled,
+		ID:       userID,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to set disabled: %w", err)
+	}
+	if err := queries.DeleteUserSessions(ctx, sql.NullString{String: userID, Valid: true}); err != nil {
+		return 0, fmt.Errorf("failed to delete sessions: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return rows, nil
+}
+
+func (h *Handlers) patchUsers(w http.ResponseWriter, r *http.Request, message string) {
+	var signals usersSignals
+	if err := datastar.ReadSignals(r, &signals); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	view, err := h.usersView(r, signals, message)
+	if err != nil {
+		slog.Error("Failed to load users", "error", err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	sse := datastar.NewSSE(w, r)
+	if err := sse.PatchElementTempl(pages.UsersTable(view)); err != nil {
+		slog.Error("Failed to patch elements", "error", err)
+		return
+	}
+	if view.Page != signals.Page {
+		if err := sse.MarshalAndPatchSignals(map[string]any{"page": view.Page}); err != nil {
+			slog.Error("Failed to patch signals", "error", err)
+		}
+	}
+}
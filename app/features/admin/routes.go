@@ -0,0 +1,27 @@
+package admin
+
+import (
+	"database/sql"
+
+	"northstar/app/middleware"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// SetupRoutes mounts the admin area on router, which only admins can open.
+func SetupRoutes(router chi.Router, db *sql.DB, users *middleware.UserCache) error {
+	handlers := NewHandlers(db, users)
+
+	router.Route("/admin", func(adminRouter chi.Router) {
+		adminRouter.Use(middleware.RequireRole(middleware.RoleAdmin))
+		adminRouter.Get("/", handlers.AdminPage)
+		adminRouter.Get("/users", handlers.Users)
+		adminRouter.Route("/users/{id}", func(userRouter chi.Router) {
+			userRouter.Post("/disable", handlers.DisableUser)
+			userRouter.Post("/enable", handlers.EnableUser)
+			userRouter.Post("/role/{role}", handlers.SetRole)
+		})
+	})
+
+	return nil
+}
@@ -0,0 +1,263 @@
+package auth
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"northstar/app/features/auth/gen/authdb"
+	"northstar/app/features/auth/pages"
+	"northstar/app/features/common/utils"
+	"northstar/app/middleware"
+
+	"github.com/a-h/templ"
+	"github.com/starfederation/datastar-go/datastar"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func patchTempl(w http.ResponseWriter, r *http.Request, components ...templ.Component) {
+	sse := datastar.NewSSE(w, r)
+	for _, c := range components {
+		if err := sse.PatchElementTempl(c); err != nil {
+			slog.Error("Failed to patch elements", "error", err)
+			return
+		}
+	}
+}
+
+// checkPassword returns why password cannot be accepted as the user's
+// current one, or "" when it is. Wrong passwords count as failed sign-ins, so
+// a session left open cannot be used to guess it.
+func (h *authHandlers) checkPassword(ctx context.Context, user *authdb.User, password string) string {
+	now := time.Now()
+	key := accountLimitKey(user.Email)
+	lockedUntil, err := h.limiter.lockedUntil(ctx, now, key)
+	if err != nil {
+		slog.Error("Error checking login attempts", "error", err)
+	} else if !lockedUntil.IsZero() {
+		return MsgTooManyLoginAttempts
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)); err != nil {
+		if err := h.limiter.fail(ctx, now, key, maxAccountFailures); err != nil {
+			slog.Error("Error recording failed login", "error", err)
+		}
+		return MsgWrongPassword
+	}
+	return ""
+}
+
+// validateProfile checks new account details the way validateSignup does,
+// except that users may keep their own username and email.
+func (h *authHandlers) validateProfile(ctx context.Context, user *authdb.User, username, email string) (ValidationErrors, error) {
+	var validationErr ValidationErrors
+
+	if username == "" {
+		validationErr.Username = MsgMissingUsername
+	} else if username != user.Username {
+		userExists, err := h.repository.checkIfUserExistsByUsername(ctx, username)
+		if err != nil {
+			return validationErr, err
+		}
+		if userExists {
+			validationErr.Username = MsgUsernameAlreadyExists
+		}
+	}
+
+	if email == "" {
+		validationErr.Email = MsgMissingEmail
+	} else if email != user.Email {
+		emailExists, err := h.repository.checkIfUserExistsByEmail(ctx, email)
+		if err != nil {
+			return validationErr, err
+		}
+		if emailExists {
+			validationErr.Email = MsgEmailAlreadyExists
+		}
+	}
+
+	return validationErr, nil
+}
+
+// handleUpdateProfile changes the username and email address. A new address
+// has to be verified again.
+func (h *authHandlers) handleUpdateProfile(w http.ResponseWriter, r *http.Request) {
+	user, _ := middleware.GetUserFromContext(r.Context())
+
+	if err := r.ParseForm(); err != nil {
+		slog.Error("Error parsing form data", "error", err)
+		patchTempl(w, r, pages.ProfileDetails(&user, MsgInvalidFormData))
+		return
+	}
+
+	username := r.FormValue("username")
+	email := r.FormValue("email")
+
+	validationErr, err := h.validateProfile(r.Context(), &user, username, email)
+	if err != nil {
+		slog.Error("Error during profile validation", "user_id", user.ID, "error", err)
+		patchTempl(w, r, pages.ProfileDetails(&user, MsgProfileUpdateFailed))
+		return
+	}
+	if validationErr.HasErrors() {
+		patchTempl(w, r,
+			pages.UsernameError(validationErr.Username),
+			pages.EmailError(validationErr.Email),
+		)
+		return
+	}
+
+	if err := h.repository.queries.UpdateUser(r.Context(), authdb.UpdateUserParams{
+		Username: username,
+		Email:    email,
+		ID:       user.ID,
+	}); err != nil {
+		slog.Error("Error updating user", "user_id", user.ID, "error", err)
+		patchTempl(w, r, pages.ProfileDetails(&user, MsgProfileUpdateFailed))
+		return
+	}
+	h.repository.users.Invalidate(user.ID)
+	updated, err := h.repository.queries.GetUser(r.Context(), user.ID)
+	if err != nil {
+		slog.Error("Error getting user", "user_id", user.ID, "error", err)
+		patchTempl(w, r, pages.ProfileDetails(&user, MsgProfileUpdateFailed))
+		return
+	}
+	slog.Info("Profile updated", "user_id", user.ID)
+
+	message := MsgProfileUpdated
+	if updated.Email != user.Email {
+		message = MsgProfileUpdatedVerify
+		if err := h.sendVerificationEmail(r, &updated); err != nil {
+			// the user can ask for another link from the verification page
+			slog.Error("Error sending verification email", "user_id", user.ID, "error", err)
+		}
+	}
+
+	patchTempl(w, r, pages.ProfileDetails(&updated, message))
+}
+
+// handleChangePassword sets a new password, signing the user out of every
+// other session.
+func (h *authHandlers) handleChangePassword(w http.ResponseWriter, r *http.Request) {
+	user, _ := middleware.GetUserFromContext(r.Context())
+
+	if err := r.ParseForm(); err != nil {
+		slog.Error("Error parsing form data", "error", err)
+		patchTempl(w, r, pages.ChangePassword(true, MsgInvalidFormData))
+		return
+	}
+
+	password := r.FormValue("password")
+	confirm := r.FormValue("confirm_password")
+
+	var validationErr ValidationErrors
+	if len(password) < 6 {
+		validationErr.Password = MsgPasswordTooShort
+	}
+	if confirm != password {
+		validationErr.ConfirmPassword = MsgPasswordMismatch
+	}
+	currentErr := h.checkPassword(r.Context(), &user, r.FormValue("current_password"))
+	if currentErr != "" || validationErr.HasErrors() {
+		patchTempl(w, r,
+			pages.CurrentPasswordError(currentErr),
+			pages.PasswordError(validationErr.Password),
+			pages.ConfirmPasswordError(validationErr.ConfirmPassword),
+		)
+		return
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		slog.Error("Error hashing password", "error", err)
+		patchTempl(w, r, pages.ChangePassword(true, MsgPasswordChangeFailed))
+		return
+	}
+
+	updated, err := h.repository.changePassword(r.Context(), user.ID, h.currentSessionID(r), string(hashedPassword))
+	if err != nil {
+		slog.Error("Error changing password", "user_id", user.ID, "error", err)
+		patchTempl(w, r, pages.ChangePassword(true, MsgPasswordChangeFailed))
+		return
+	}
+	slog.Info("Password changed", "user_id", user.ID)
+
+	// the new password ended this session too, so it is brought up to date
+	if err := h.createSession(w, r, &updated); err != nil {
+		slog.Error("Error creating session after password change", "error", err)
+		patchTempl(w, r, pages.ChangePassword(true, MsgPasswordResetLoginFailed))
+		return
+	}
+
+	sessions, err := h.userSessions(r, user.ID)
+	if err != nil {
+		slog.Error("Failed to list sessions", "user_id", user.ID, "error", err)
+	}
+	patchTempl(w, r,
+		pages.ChangePassword(true, MsgPasswordChanged),
+		pages.ProfileSessions(sessions, ""),
+	)
+}
+
+// handleSendSetPasswordLink emails a password reset link to users who have
+// no password, such as those who signed up through a sign in provider.
+func (h *authHandlers) handleSendSetPasswordLink(w http.ResponseWriter, r *http.Request) {
+	user, _ := middleware.GetUserFromContext(r.Context())
+
+	message := MsgSetPasswordLinkSent
+	if err := h.sendPasswordResetEmail(r, &user); err != nil {
+		slog.Error("Failed to send password reset email", "user_id", user.ID, "error", err)
+		message = MsgVerificationFailed
+	}
+
+	patchTempl(w, r, pages.ChangePassword(user.PasswordHash != "", message))
+}
+
+// handleDeleteAccount deletes the user and everything kept for them, then
+// sends them to the home page signed out.
+func (h *authHandlers) handleDeleteAccount(w http.ResponseWriter, r *http.Request) {
+	user, _ := middleware.GetUserFromContext(r.Context())
+
+	if err := r.ParseForm(); err != nil {
+		slog.Error("Error parsing form data", "error", err)
+		patchTempl(w, r, pages.DeleteAccountError(MsgInvalidFormData))
+		return
+	}
+
+	if msg := h.checkPassword(r.Context(), &user, r.FormValue("password")); msg != "" {
+		patchTempl(w, r, pages.DeleteAccountError(msg))
+		return
+	}
+
+	if err := h.repository.deleteUser(r.Context(), user.ID); err != nil {
+		slog.Error("Error deleting user", "user_id", user.ID, "error", err)
+		patchTempl(w, r, pages.DeleteAccountError(MsgAccountDeleteFailed))
+		return
+	}
+
+	// the account is gone, so finish even if the browser stops waiting
+	ctx := context.WithoutCancel(r.Context())
+	for _, userData := range h.userData {
+		if err := userData.DeleteUserData(ctx, user.ID); err != nil {
+			slog.Error("Error deleting user data", "user_id", user.ID, "error", err)
+		}
+	}
+	if err := h.limiter.reset(ctx, accountLimitKey(user.Email)); err != nil {
+		slog.Error("Error resetting login attempts", "error", err)
+	}
+	slog.Info("Account deleted", "user_id", user.ID, "ip", utils.ClientIP(r))
+
+	session, _ := h.store.Get(r, "auth-session")
+	session.Values["user_id"] = nil
+	session.Options.MaxAge = -1
+	if err := session.Save(r, w); err != nil {
+		slog.Error("Failed to save session", "error", err)
+	}
+
+	sse := datastar.NewSSE(w, r)
+	if err := sse.ExecuteScript("window.location.href = '/'"); err != nil {
+		slog.Error("Failed to execute script", "error", err)
+	}
+}
@@ -1,19 +1,64 @@
 package auth
 
 const (
-	MsgInvalidCredentials        = "Invalid email or password"
-	MsgUserNotFound              = "User not found"
-	MsgUserAlreadyExists         = "User already exists"
-	MsgUsernameAlreadyExists     = "An account with this username already exists"
-	MsgEmailAlreadyExists        = "An account with this email already exists"
-	MsgMissingFields             = "All fields are required"
-	MsgMissingCredentials        = "Email and password are required"
-	MsgPasswordTooShort          = "Password must be at least 6 characters long"
-	MsgInvalidFormData           = "Invalid form data"
-	MsgInvalidMethod             = "Invalid request method"
-	MsgSessionFailed             = "Session management failed"
-	MsgLoginFailed               = "Login failed, please try again"
-	MsgSignupFailed              = "Account creation failed, please try again"
-	MsgLogoutFailed              = "Logout failed, please try again"
-	MsgAccountCreatedLoginFailed = "Account created but login failed, please try logging in manually"
+	MsgInvalidCredentials         = "Invalid email or password"
+	MsgUserNotFound               = "User not found"
+	MsgUserAlreadyExists          = "User already exists"
+	MsgUsernameAlreadyExists      = "An account with this username already exists"
+	MsgEmailAlreadyExists         = "An account with this email already exists"
+	MsgMissingFields              = "All fields are required"
+	MsgMissingCredentials         = "Email and password are required"
+	MsgPasswordTooShort           = "Password must be at least 6 characters long"
+	MsgInvalidFormData            = "Invalid form data"
+	MsgInvalidMethod              = "Invalid request method"
+	MsgSessionFailed              = "Session management failed"
+	MsgLoginFailed                = "Login failed, please try again"
+	MsgSignupFailed               = "Account creation failed, please try again"
+	MsgLogoutFailed               = "Logout failed, please try again"
+	MsgAccountCreatedLoginFailed  = "Account created but login failed, please try logging in manually"
+	MsgVerificationSent           = "We sent you a new link"
+	MsgVerificationFailed         = "The link could not be sent, please try again"
+	MsgEmailAlreadyVerified       = "Your email address is already verified"
+	MsgEmailVerified              = "Thanks, your email address is verified"
+	MsgInvalidVerificationLink    = "This verification link is invalid or was sent to a different address"
+	MsgExpiredVerificationLink    = "This verification link has expired"
+	MsgMissingEmail               = "Email is required"
+	MsgMissingUsername            = "Username is required"
+	MsgPasswordMismatch           = "Passwords do not match"
+	MsgPasswordResetFailed        = "Password reset failed, please try again"
+	MsgInvalidPasswordResetLink   = "This reset link is invalid, has expired or was already used"
+	MsgPasswordResetLoginFailed   = "Password changed but login failed, please try logging in manually"
+	MsgSessionRevoked             = "The session was signed out"
+	MsgOtherSessionsRevoked       = "All other sessions were signed out"
+	MsgSessionNotFound            = "That session no longer exists"
+	MsgCannotRevokeCurrentSession = "Use Logout to sign out of this session"
+	MsgSessionRevokeFailed        = "The session could not be signed out, please try again"
+	MsgTooManyLoginAttempts       = "Too many failed sign-in attempts, please try again in a few minutes"
+	MsgInvalidTwoFactorCode       = "That code is not valid"
+	MsgTwoFactorExpired           = "Your sign-in expired, please sign in again"
+	MsgTwoFactorFailed            = "Two-factor authentication could not be updated, please try again"
+	MsgTwoFactorAlreadyEnabled    = "Two-factor authentication is already on"
+	MsgTwoFactorEnabled           = "Two-factor authentication is on"
+	MsgTwoFactorDisabled          = "Two-factor authentication is off"
+	MsgRecoveryCodesReplaced      = "Your old recovery codes no longer work"
+	MsgOIDCUnavailable            = "The sign in provider cannot be reached, please try again later"
+	MsgOIDCFailed                 = "Signing in with the provider failed, please try again"
+	MsgOIDCExpired                = "Your sign in expired, please try again"
+	MsgOIDCNoEmail                = "The provider did not share a verified email address"
+	MsgOIDCEmailTaken             = "An account with this email exists but has not verified it; sign in with its password and verify the address first"
+	MsgPasskeyAdded               = "Your passkey was added"
+	MsgPasskeyRemoved             = "The passkey was removed"
+	MsgPasskeyNotFound            = "That passkey no longer exists"
+	MsgPasskeyFailed              = "Something went wrong with your passkey, please try again"
+	MsgPasskeyExpired             = "The passkey request expired, please try again"
+	MsgPasskeyRejected            = "That passkey was not accepted"
+	MsgProfileUpdated             = "Your profile was updated"
+	MsgProfileUpdatedVerify       = "Your profile was updated, check your inbox to verify your new email address"
+	MsgProfileUpdateFailed        = "Your profile could not be updated, please try again"
+	MsgWrongPassword              = "That is not your current password"
+	MsgPasswordChanged            = "Your password was changed and your other sessions were signed out"
+	MsgPasswordChangeFailed       = "Your password could not be changed, please try again"
+	MsgSetPasswordLinkSent        = "We sent you a link to choose a password"
+	MsgAccountDeleteFailed        = "Your account could not be deleted, please try again"
+	MsgAccountDisabled            = "This account has been disabled"
 )
@@ -6,12 +6,107 @@ package authdb
 
 import (
 	"database/sql"
+	"time"
 )
 
+type PasswordResetToken struct {
+	TokenHash string
+	UserID    string
+	ExpiresAt time.Time
+	UsedAt    sql.NullTime
+	CreatedAt sql.NullTime
+}
+
+type RecoveryCode struct {
+	UserID    string
+	CodeHash  string
+	UsedAt    sql.NullTime
+	CreatedAt sql.NullTime
+}
+
+type Session struct {
+	ID         string
+	Name       string
+	UserID     sql.NullString
+	Data       []byte
+	UserAgent  string
+	IpAddress  string
+	CreatedAt  sql.NullTime
+	LastSeenAt time.Time
+	ExpiresAt  time.Time
+}
+
+type SharedList struct {
+	ID        string
+	Name      string
+	OwnerID   string
+	CreatedAt sql.NullTime
+}
+
+type SharedListInvite struct {
+	Token     string
+	ListID    string
+	Role      string
+	CreatedBy string
+	ExpiresAt time.Time
+	CreatedAt sql.NullTime
+}
+
+type SharedListMember struct {
+	ListID    string
+	UserID    string
+	Role      string
+	CreatedAt sql.NullTime
+}
+
+type TodoList struct {
+	ID        string
+	Data      string
+	CreatedAt sql.NullTime
+	UpdatedAt sql.NullTime
+	Revision  int64
+}
+
+type TodoListHistory struct {
+	ListID    string
+	Revision  int64
+	Data      string
+	CreatedAt sql.NullTime
+}
+
 type User struct {
-	ID           string
-	Username     string
-	Email        string
-	PasswordHash string
+	ID             string
+	Username       string
+	Email          string
+	PasswordHash   string
+	CreatedAt      sql.NullTime
+	EmailVerified  bool
+	SessionVersion int64
+	Role           string
+	Disabled       bool
+}
+
+type UserIdentity struct {
+	Issuer    string
+	Subject   string
+	UserID    string
+	Email     string
+	CreatedAt sql.NullTime
+}
+
+type UserTotp struct {
+	UserID       string
+	Secret       string
+	Confirmed    bool
+	LastUsedStep int64
 	CreatedAt    sql.NullTime
 }
+
+type WebauthnCredential struct {
+	ID         string
+	UserID     string
+	Name       string
+	Data       string
+	CreatedAt  sql.NullTime
+	LastUsedAt sql.NullTime
+}
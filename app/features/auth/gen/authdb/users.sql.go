@@ -31,10 +31,22 @@ func (q *Queries) CheckIfUserExistsByUsername(ctx context.Context, username stri
 	return column_1, err
 }
 
+const countUsers = `-- name: CountUsers :one
+SELECT COUNT(*) FROM users
+WHERE username LIKE ?1 ESCAPE '\' OR email LIKE ?1 ESCAPE '\'
+`
+
+func (q *Queries) CountUsers(ctx context.Context, search string) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countUsers, search)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createUser = `-- name: CreateUser :one
 INSERT INTO users (id, username, email, password_hash) 
 VALUES (?, ?, ?, ?) 
-RETURNING id, username, email, password_hash, created_at
+RETURNING id, username, email, password_hash, created_at, email_verified, session_version, role, disabled
 `
 
 type CreateUserParams struct {
@@ -58,6 +70,10 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		&i.Email,
 		&i.PasswordHash,
 		&i.CreatedAt,
+		&i.EmailVerified,
+		&i.SessionVersion,
+		&i.Role,
+		&i.Disabled,
 	)
 	return i, err
 }
@@ -72,7 +88,7 @@ func (q *Queries) DeleteUser(ctx context.Context, id string) error {
 }
 
 const getUser = `-- name: GetUser :one
-SELECT id, username, email, password_hash, created_at FROM users WHERE id = ? LIMIT 1
+SELECT id, username, email, password_hash, created_at, email_verified, session_version, role, disabled FROM users WHERE id = ? LIMIT 1
 `
 
 func (q *Queries) GetUser(ctx context.Context, id string) (User, error) {
@@ -84,12 +100,16 @@ func (q *Queries) GetUser(ctx context.Context, id string) (User, error) {
 		&i.Email,
 		&i.PasswordHash,
 		&i.CreatedAt,
+		&i.EmailVerified,
+		&i.SessionVersion,
+		&i.Role,
+		&i.Disabled,
 	)
 	return i, err
 }
 
 const getUserByEmail = `-- name: GetUserByEmail :one
-SELECT id, username, email, password_hash, created_at FROM users WHERE email = ? LIMIT 1
+SELECT id, username, email, password_hash, created_at, email_verified, session_version, role, disabled FROM users WHERE email = ? LIMIT 1
 `
 
 func (q *Queries) GetUserByEmail(ctx context.Context, email string) (User, error) {
@@ -101,16 +121,29 @@ func (q *Queries) GetUserByEmail(ctx context.Context, email string) (User, error
 		&i.Email,
 		&i.PasswordHash,
 		&i.CreatedAt,
+		&i.EmailVerified,
+		&i.SessionVersion,
+		&i.Role,
+		&i.Disabled,
 	)
 	return i, err
 }
 
 const listUsers = `-- name: ListUsers :many
-SELECT id, username, email, password_hash, created_at FROM users ORDER BY username
+SELECT id, username, email, password_hash, created_at, email_verified, session_version, role, disabled FROM users
+WHERE username LIKE ?1 ESCAPE '\' OR email LIKE ?1 ESCAPE '\'
+ORDER BY username
+LIMIT ?2 OFFSET ?3
 `
 
-func (q *Queries) ListUsers(ctx context.Context) ([]User, error) {
-	rows, err := q.db.QueryContext(ctx, listUsers)
+type ListUsersParams struct {
+	Search string
+	Limit  int64
+	Offset int64
+}
+
+func (q *Queries) ListUsers(ctx context.Context, arg ListUsersParams) ([]User, error) {
+	rows, err := q.db.QueryContext(ctx, listUsers, arg.Search, arg.Limit, arg.Offset)
 	if err != nil {
 		return nil, err
 	}
@@ -124,6 +157,10 @@ func (q *Queries) ListUsers(ctx context.Context) ([]User, error) {
 			&i.Email,
 			&i.PasswordHash,
 			&i.CreatedAt,
+			&i.EmailVerified,
+			&i.SessionVersion,
+			&i.Role,
+			&i.Disabled,
 		); err != nil {
 			return nil, err
 		}
@@ -138,8 +175,43 @@ func (q *Queries) ListUsers(ctx context.Context) ([]User, error) {
 	return items, nil
 }
 
+const setUserDisabled = `-- name: SetUserDisabled :execrows
+UPDATE users SET disabled = ?, session_version = session_version + 1 WHERE id = ?
+`
+
+type SetUserDisabledParams struct {
+	Disabled bool
+	ID       string
+}
+
+func (q *Queries) SetUserDisabled(ctx context.Context, arg SetUserDisabledParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, setUserDisabled, arg.Disabled, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const setUserRole = `-- name: SetUserRole :execrows
+UPDATE users SET role = ? WHERE id = ?
+`
+
+type SetUserRoleParams struct {
+	Role string
+	ID   string
+}
+
+func (q *Queries) SetUserRole(ctx context.Context, arg SetUserRoleParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, setUserRole, arg.Role, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const updateUser = `-- name: UpdateUser :exec
-UPDATE users SET username = ?, email = ? WHERE id = ?
+UPDATE users SET username = ?1, email = ?2, email_verified = email_verified AND email = ?2
+WHERE id = ?3
 `
 
 type UpdateUserParams struct {
@@ -152,3 +224,34 @@ func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) error {
 	_, err := q.db.ExecContext(ctx, updateUser, arg.Username, arg.Email, arg.ID)
 	return err
 }
+
+const updateUserPassword = `-- name: UpdateUserPassword :exec
+UPDATE users SET password_hash = ?, session_version = session_version + 1 WHERE id = ?
+`
+
+type UpdateUserPasswordParams struct {
+	PasswordHash string
+	ID           string
+}
+
+func (q *Queries) UpdateUserPassword(ctx context.Context, arg UpdateUserPasswordParams) error {
+	_, err := q.db.ExecContext(ctx, updateUserPassword, arg.PasswordHash, arg.ID)
+	return err
+}
+
+const verifyUserEmail = `-- name: VerifyUserEmail :execrows
+UPDATE users SET email_verified = TRUE WHERE id = ? AND email = ?
+`
+
+type VerifyUserEmailParams struct {
+	ID    string
+	Email string
+}
+
+func (q *Queries) VerifyUserEmail(ctx context.Context, arg VerifyUserEmailParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, verifyUserEmail, arg.ID, arg.Email)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -2,15 +2,22 @@ package auth
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"net/http"
+	"slices"
+	"strings"
+	"time"
 
 	"northstar/app/features/auth/gen/authdb"
 	"northstar/app/features/auth/pages"
 	"northstar/app/features/common/utils"
 	"northstar/app/middleware"
+	"northstar/config"
+	"northstar/mail"
 
+	"github.com/go-webauthn/webauthn/webauthn"
 	"github.com/google/uuid"
 	"github.com/gorilla/sessions"
 	"github.com/starfederation/datastar-go/datastar"
@@ -18,18 +25,26 @@ import (
 )
 
 type ValidationErrors struct {
-	Username string
-	Email    string
-	Password string
+	Username        string
+	Email           string
+	Password        string
+	ConfirmPassword string
 }
 
 func (v ValidationErrors) HasErrors() bool {
-	return v.Username != "" || v.Email != "" || v.Password != ""
+	return v.Username != "" || v.Email != "" || v.Password != "" || v.ConfirmPassword != ""
 }
 
 type authHandlers struct {
 	repository *authRepository
 	store      sessions.Store
+	mailer     mail.Mailer
+	tokens     tokenSigner
+	limiter    *loginLimiter
+	secrets    secretBox
+	oidc       *oidcProvider
+	webauthn   *webauthn.WebAuthn
+	userData   []UserDataDeleter
 }
 
 func (h *authHandlers) sendGenericError(w http.ResponseWriter, r *http.Request, message string) {
@@ -99,13 +114,34 @@ func (h *authHandlers) sendLoginErrors(w http.ResponseWriter, r *http.Request, e
 	}
 }
 
-func (h *authHandlers) createSession(w http.ResponseWriter, r *http.Request, userID string) error {
+// errUserDisabled is returned by createSession for users an admin disabled.
+var errUserDisabled = errors.New("user is disabled")
+
+// createSession signs user in on a new session.
+func (h *authHandlers) createSession(w http.ResponseWriter, r *http.Request, user *authdb.User) error {
+	if user.Disabled {
+		return errUserDisabled
+	}
+
 	session, err := h.store.Get(r, "auth-session")
 	if err != nil {
 		return fmt.Errorf("getting session: %w", err)
 	}
 
-	session.Values["user_id"] = userID
+	// Signing in always starts a new session, so an ID planted in the browser
+	// beforehand never gets signed in. Only the sign in itself carries over;
+	// half finished two-factor, OpenID Connect and passkey flows are dropped.
+	if session.ID != "" {
+		if err := h.repository.queries.DeleteSession(r.Context(), session.ID); err != nil {
+			return fmt.Errorf("deleting session: %w", err)
+		}
+	}
+	session.ID = ""
+	session.IsNew = true
+	session.Values = map[any]any{
+		"user_id":                    user.ID,
+		middleware.SessionVersionKey: user.SessionVersion,
+	}
 	if err := session.Save(r, w); err != nil {
 		return fmt.Errorf("saving session: %w", err)
 	}
@@ -113,15 +149,44 @@ func (h *authHandlers) createSession(w http.ResponseWriter, r *http.Request, use
 	return nil
 }
 
+// promoteAdmin makes user an admin when config.Global.AdminEmails lists their
+// verified address. It is only called when the address is first verified, so
+// admins who were demoted later stay demoted.
+func (h *authHandlers) promoteAdmin(ctx context.Context, user *authdb.User) error {
+	if user.Role == middleware.RoleAdmin || !user.EmailVerified ||
+		!slices.Contains(config.Global.AdminEmails, strings.ToLower(user.Email)) {
+		return nil
+	}
+	if _, err := h.repository.queries.SetUserRole(ctx, authdb.SetUserRoleParams{
+		Role: middleware.RoleAdmin,
+		ID:   user.ID,
+	}); err != nil {
+		return fmt.Errorf("setting role: %w", err)
+	}
+	h.repository.users.Invalidate(user.ID)
+	user.Role = middleware.RoleAdmin
+	slog.Info("User promoted to admin", "user_id", user.ID)
+	return nil
+}
+
+// oidcProviderName returns the name of the OpenID Connect provider users can
+// sign in with, or "" when there is none.
+func (h *authHandlers) oidcProviderName() string {
+	if h.oidc == nil {
+		return ""
+	}
+	return h.oidc.Name
+}
+
 func (h *authHandlers) handleLoginPage(w http.ResponseWriter, r *http.Request) {
-	if err := pages.LoginPage().Render(r.Context(), w); err != nil {
+	if err := pages.LoginPage(h.oidcProviderName()).Render(r.Context(), w); err != nil {
 		slog.Error("Failed to render login page", "error", err)
 		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
 	}
 }
 
 func (h *authHandlers) handleSignupPage(w http.ResponseWriter, r *http.Request) {
-	if err := pages.SignupPage().Render(r.Context(), w); err != nil {
+	if err := pages.SignupPage(h.oidcProviderName()).Render(r.Context(), w); err != nil {
 		slog.Error("Failed to render signup page", "error", err)
 		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
 	}
@@ -147,6 +212,19 @@ func (h *authHandlers) handleLogin(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// check the limits before bcrypt, so locked out guesses cost nothing. If
+	// the limiter is unavailable, sign-ins are let through rather than blocked.
+	now := time.Now()
+	accountKey, ipKey := accountLimitKey(email), ipLimitKey(utils.ClientIP(r))
+	lockedUntil, err := h.limiter.lockedUntil(r.Context(), now, accountKey, ipKey)
+	if err != nil {
+		slog.Error("Error checking login attempts", "error", err)
+	} else if !lockedUntil.IsZero() {
+		slog.Warn("Login attempt while locked out", "email", email, "ip", utils.ClientIP(r), "until", lockedUntil)
+		h.sendGenericError(w, r, MsgTooManyLoginAttempts)
+		return
+	}
+
 	user, validationErr, err := h.validateLogin(r.Context(), email, password)
 	if err != nil {
 		slog.Error("Error during login validation", "email", email, "error", err)
@@ -155,11 +233,45 @@ func (h *authHandlers) handleLogin(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if validationErr.HasErrors() {
+		if err := errors.Join(
+			h.limiter.fail(r.Context(), now, accountKey, maxAccountFailures),
+			h.limiter.fail(r.Context(), now, ipKey, maxIPFailures),
+		); err != nil {
+			slog.Error("Error recording failed login", "error", err)
+		}
 		h.sendLoginErrors(w, r, validationErr)
 		return
 	}
+	if user.Disabled {
+		h.sendGenericError(w, r, MsgAccountDisabled)
+		return
+	}
+
+	_, enrolled, err := h.enrolledTOTP(r.Context(), user.ID)
+	if err != nil {
+		slog.Error("Error checking two-factor enrollment", "user_id", user.ID, "error", err)
+		h.sendGenericError(w, r, MsgLoginFailed)
+		return
+	}
+	if enrolled {
+		if err := h.startTwoFactor(w, r, user); err != nil {
+			slog.Error("Error starting two-factor login", "error", err)
+			h.sendGenericError(w, r, MsgLoginFailed)
+		}
+		return
+	}
 
-	if err := h.createSession(w, r, user.ID); err != nil {
+	h.completeLogin(w, r, user)
+}
+
+// completeLogin logs user in once every step of logging in has passed, and
+// forgets the failed attempts against their account.
+func (h *authHandlers) completeLogin(w http.ResponseWriter, r *http.Request, user *authdb.User) {
+	if err := h.limiter.reset(r.Context(), accountLimitKey(user.Email)); err != nil {
+		slog.Error("Error resetting login attempts", "error", err)
+	}
+
+	if err := h.createSession(w, r, user); err != nil {
 		slog.Error("Error creating session", "error", err)
 		h.sendGenericError(w, r, MsgLoginFailed)
 		return
@@ -218,14 +330,23 @@ func (h *authHandlers) handleSignup(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.createSession(w, r, user.ID); err != nil {
+	if err := h.sendVerificationEmail(r, &user); err != nil {
+		// the user can ask for another link from the verification page
+		slog.Error("Error sending verification email", "user_id", user.ID, "error", err)
+	}
+
+	if err := h.createSession(w, r, &user); err != nil {
 		slog.Error("Error creating session after signup", "error", err)
 		h.sendGenericError(w, r, MsgAccountCreatedLoginFailed)
 		return
 	}
 
+	next := "/"
+	if config.Global.RequireVerifiedEmail {
+		next = "/verify-email"
+	}
 	sse := datastar.NewSSE(w, r)
-	if err := sse.ExecuteScript("window.location.href = '/'"); err != nil {
+	if err := sse.ExecuteScript(fmt.Sprintf("window.location.href = '%s'", next)); err != nil {
 		slog.Error("Failed to execute script", "error", err)
 	}
 }
@@ -311,7 +432,28 @@ func (h *authHandlers) handleProfilePage(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	if err := pages.ProfilePage(&user).Render(r.Context(), w); err != nil {
+	sessions, err := h.userSessions(r, user.ID)
+	if err != nil {
+		slog.Error("Failed to fetch sessions for profile page", "error", err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	twoFactor, err := h.twoFactorStatus(r.Context(), &user)
+	if err != nil {
+		slog.Error("Failed to fetch two-factor status for profile page", "error", err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	passkeys, err := h.userPasskeys(r.Context(), user.ID)
+	if err != nil {
+		slog.Error("Failed to fetch passkeys for profile page", "error", err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	if err := pages.ProfilePage(&user, sessions, twoFactor, passkeys).Render(r.Context(), w); err != nil {
 		slog.Error("Failed to render profile page", "error", err)
 		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
 		return
@@ -0,0 +1,326 @@
+package auth
+
+import (
+	"context"
+	"crypto/subtle"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"northstar/app/features/auth/gen/authdb"
+	"northstar/app/features/auth/pages"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"github.com/google/uuid"
+	"golang.org/x/oauth2"
+)
+
+var (
+	ErrOIDCNoEmail    = errors.New("identity has no verified email")
+	ErrOIDCEmailTaken = errors.New("email belongs to an unverified account")
+)
+
+// oidcFlowTTL is how long a user has to sign in at the provider.
+const oidcFlowTTL = 10 * time.Minute
+
+// Session values for a sign in waiting for the provider to redirect back.
+const (
+	oidcStateKey    = "oidc_state"
+	oidcNonceKey    = "oidc_nonce"
+	oidcVerifierKey = "oidc_verifier"
+	oidcUntilKey    = "oidc_until"
+)
+
+// oidcProvider signs users in with an OpenID Connect provider using the
+// authorization code flow with PKCE. The provider's configuration is fetched
+// on first use, so the app starts even while the provider is unreachable.
+type oidcProvider struct {
+	Name         string
+	issuer       string
+	clientID     string
+	clientSecret string
+
+	mu       sync.Mutex
+	provider *oidc.Provider
+}
+
+func (p *oidcProvider) discover(ctx context.Context) (*oidc.Provider, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.provider == nil {
+		// the provider keeps ctx to fetch signing keys later, so it must
+		// outlive the request
+		provider, err := oidc.NewProvider(context.WithoutCancel(ctx), p.issuer)
+		if err != nil {
+			return nil, fmt.Errorf("discovering oidc provider: %w", err)
+		}
+		p.provider = provider
+	}
+	return p.provider, nil
+}
+
+func (p *oidcProvider) oauth2Config(provider *oidc.Provider) *oauth2.Config {
+	return &oauth2.Config{
+		ClientID:     p.clientID,
+		ClientSecret: p.clientSecret,
+		Endpoint:     provider.Endpoint(),
+		RedirectURL:  absoluteURL("/login/oidc/callback"),
+		Scopes:       []string{oidc.ScopeOpenID, "email", "profile"},
+	}
+}
+
+// oidcClaims are the claims of an ID token used to link or create a user.
+type oidcClaims struct {
+	Email             string `json:"email"`
+	EmailVerified     bool   `json:"email_verified"`
+	PreferredUsername string `json:"preferred_username"`
+	Name              string `json:"name"`
+}
+
+// handleOIDCLogin sends the user to the provider to sign in.
+func (h *authHandlers) handleOIDCLogin(w http.ResponseWriter, r *http.Request) {
+	provider, err := h.oidc.discover(r.Context())
+	if err != nil {
+		slog.Error("Failed to reach oidc provider", "error", err)
+		h.renderOIDCError(w, r, http.StatusBadGateway, MsgOIDCUnavailable)
+		return
+	}
+
+	session, err := h.store.Get(r, "auth-session")
+	if err != nil {
+		slog.Error("Error getting session", "error", err)
+		h.renderOIDCError(w, r, http.StatusInternalServerError, MsgLoginFailed)
+		return
+	}
+	state, nonce, verifier := oauth2.GenerateVerifier(), oauth2.GenerateVerifier(), oauth2.GenerateVerifier()
+	session.Values[oidcStateKey] = state
+	session.Values[oidcNonceKey] = nonce
+	session.Values[oidcVerifierKey] = verifier
+	session.Values[oidcUntilKey] = time.Now().Add(oidcFlowTTL).Unix()
+	if err := session.Save(r, w); err != nil {
+		slog.Error("Error saving session", "error", err)
+		h.renderOIDCError(w, r, http.StatusInternalServerError, MsgLoginFailed)
+		return
+	}
+
+	authURL := h.oidc.oauth2Config(provider).AuthCodeURL(state, oidc.Nonce(nonce), oauth2.S256ChallengeOption(verifier))
+	http.Redirect(w, r, authURL, http.StatusSeeOther)
+}
+
+// handleOIDCCallback finishes signing in once the provider redirects back,
+// linking the identity to a user or creating one.
+func (h *authHandlers) handleOIDCCallback(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	session, err := h.store.Get(r, "auth-session")
+	if err != nil {
+		slog.Error("Error getting session", "error", err)
+		h.renderOIDCError(w, r, http.StatusInternalServerError, MsgLoginFailed)
+		return
+	}
+	state, _ := session.Values[oidcStateKey].(string)
+	nonce, _ := session.Values[oidcNonceKey].(string)
+	verifier, _ := session.Values[oidcVerifierKey].(string)
+	until, _ := session.Values[oidcUntilKey].(int64)
+	for _, key := range []string{oidcStateKey, oidcNonceKey, oidcVerifierKey, oidcUntilKey} {
+		delete(session.Values, key)
+	}
+	// a state is only good for one callback, however this one ends
+	if state != "" {
+		if err := session.Save(r, w); err != nil {
+			slog.Error("Error saving session", "error", err)
+			h.renderOIDCError(w, r, http.StatusInternalServerError, MsgLoginFailed)
+			return
+		}
+	}
+
+	if msg := r.FormValue("error"); msg != "" {
+		slog.Info("OIDC provider refused sign in", "error", msg, "description", r.FormValue("error_description"))
+		h.renderOIDCError(w, r, http.StatusBadRequest, MsgOIDCFailed)
+		return
+	}
+	if state == "" || time.Now().Unix() > until || subtle.ConstantTimeCompare([]byte(state), []byte(r.FormValue("state"))) != 1 {
+		h.renderOIDCError(w, r, http.StatusBadRequest, MsgOIDCExpired)
+		return
+	}
+
+	provider, err := h.oidc.discover(ctx)
+	if err != nil {
+		slog.Error("Failed to reach oidc provider", "error", err)
+		h.renderOIDCError(w, r, http.StatusBadGateway, MsgOIDCUnavailable)
+		return
+	}
+	token, err := h.oidc.oauth2Config(provider).Exchange(ctx, r.FormValue("code"), oauth2.VerifierOption(verifier))
+	if err != nil {
+		slog.Error("Failed to exchange oidc code", "error", err)
+		h.renderOIDCError(w, r, http.StatusBadGateway, MsgOIDCFailed)
+		return
+	}
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok {
+		slog.Error("OIDC token response has no id_token")
+		h.renderOIDCError(w, r, http.StatusBadGateway, MsgOIDCFailed)
+		return
+	}
+	idToken, err := provider.Verifier(&oidc.Config{ClientID: h.oidc.clientID}).Verify(ctx, rawIDToken)
+	if err != nil || subtle.ConstantTimeCompare([]byte(idToken.Nonce), []byte(nonce)) != 1 {
+		slog.Error("Invalid oidc id token", "error", err)
+		h.renderOIDCError(w, r, http.StatusBadRequest, MsgOIDCFailed)
+		return
+	}
+	var claims oidcClaims
+	if err := idToken.Claims(&claims); err != nil {
+		slog.Error("Failed to decode oidc claims", "error", err)
+		h.renderOIDCError(w, r, http.StatusBadRequest, MsgOIDCFailed)
+		return
+	}
+
+	user, err := h.oidcUser(ctx, idToken.Issuer, idToken.Subject, claims)
+	switch {
+	case errors.Is(err, ErrOIDCNoEmail):
+		h.renderOIDCError(w, r, http.StatusBadRequest, MsgOIDCNoEmail)
+		return
+	case errors.Is(err, ErrOIDCEmailTaken):
+		h.renderOIDCError(w, r, http.StatusConflict, MsgOIDCEmailTaken)
+		return
+	case err != nil:
+		slog.Error("Failed to link oidc identity", "issuer", idToken.Issuer, "error", err)
+		h.renderOIDCError(w, r, http.StatusInternalServerError, MsgLoginFailed)
+		return
+	}
+	if user.Disabled {
+		h.renderOIDCError(w, r, http.StatusForbidden, MsgAccountDisabled)
+		return
+	}
+
+	_, enrolled, err := h.enrolledTOTP(ctx, user.ID)
+	if err != nil {
+		slog.Error("Error checking two-factor enrollment", "user_id", user.ID, "error", err)
+		h.renderOIDCError(w, r, http.StatusInternalServerError, MsgLoginFailed)
+		return
+	}
+	if enrolled {
+		if err := h.beginTwoFactor(w, r, &user); err != nil {
+			slog.Error("Error starting two-factor login", "error", err)
+			h.renderOIDCError(w, r, http.StatusInternalServerError, MsgLoginFailed)
+			return
+		}
+		http.Redirect(w, r, "/login/2fa", http.StatusSeeOther)
+		return
+	}
+
+	if err := h.createSession(w, r, &user); err != nil {
+		slog.Error("Error creating session", "error", err)
+		h.renderOIDCError(w, r, http.StatusInternalServerError, MsgLoginFailed)
+		return
+	}
+	http.Redirect(w, r, "/", http.StatusSeeOther)
+}
+
+// oidcUser returns the user linked to the identity. An identity seen for the
+// first time is linked to the user with the same email address, as long as
+// both sides verified it, and otherwise gets a new user.
+func (h *authHandlers) oidcUser(ctx context.Context, issuer, subject string, claims oidcClaims) (authdb.User, error) {
+	queries := h.repository.queries
+	identity, err := queries.GetUserIdentity(ctx, authdb.GetUserIdentityParams{
+		Issuer:  issuer,
+		Subject: subject,
+	})
+	if err == nil {
+		return queries.GetUser(ctx, identity.UserID)
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return authdb.User{}, fmt.Errorf("getting identity: %w", err)
+	}
+
+	if claims.Email == "" || !claims.EmailVerified {
+		return authdb.User{}, ErrOIDCNoEmail
+	}
+	newIdentity := authdb.CreateUserIdentityParams{
+		Issuer:  issuer,
+		Subject: subject,
+		Email:   claims.Email,
+	}
+
+	user, err := h.repository.getUserByEmail(ctx, claims.Email)
+	if err == nil {
+		// whoever signed up with the address without verifying it may not
+		// own it
+		if !user.EmailVerified {
+			return authdb.User{}, ErrOIDCEmailTaken
+		}
+		newIdentity.UserID = user.ID
+		if err := queries.CreateUserIdentity(ctx, newIdentity); err != nil {
+			return authdb.User{}, fmt.Errorf("linking identity: %w", err)
+		}
+		slog.Info("Linked oidc identity to user", "user_id", user.ID, "issuer", issuer)
+		return user, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return authdb.User{}, fmt.Errorf("getting user: %w", err)
+	}
+
+	username, err := h.availableUsername(ctx, claims)
+	if err != nil {
+		return authdb.User{}, err
+	}
+	// the user has no password until they reset it
+	user, err = h.repository.createOIDCUser(ctx, authdb.CreateUserParams{
+		ID:       uuid.New().String(),
+		Username: username,
+		Email:    claims.Email,
+	}, newIdentity)
+	if err != nil {
+		return authdb.User{}, err
+	}
+	slog.Info("Created user from oidc identity", "user_id", user.ID, "issuer", issuer)
+	// the provider verified the address, so this is the one chance to promote
+	if err := h.promoteAdmin(ctx, &user); err != nil {
+		slog.Error("Error promoting admin", "user_id", user.ID, "error", err)
+	}
+	return user, nil
+}
+
+var usernameInvalidChars = regexp.MustCompile(`[^a-zA-Z0-9_.-]+`)
+
+// availableUsername derives a username from the identity that is not taken
+// yet, numbering it if need be.
+func (h *authHandlers) availableUsername(ctx context.Context, claims oidcClaims) (string, error) {
+	base := claims.PreferredUsername
+	if base == "" {
+		base, _, _ = strings.Cut(claims.Email, "@")
+	}
+	base = usernameInvalidChars.ReplaceAllString(base, "")
+	if base == "" {
+		base = "user"
+	}
+
+	for i := 1; i <= 100; i++ {
+		username := base
+		if i > 1 {
+			username = base + strconv.Itoa(i)
+		}
+		taken, err := h.repository.checkIfUserExistsByUsername(ctx, username)
+		if err != nil {
+			return "", fmt.Errorf("checking username: %w", err)
+		}
+		if !taken {
+			return username, nil
+		}
+	}
+	return base + "-" + uuid.New().String()[:8], nil
+}
+
+func (h *authHandlers) renderOIDCError(w http.ResponseWriter, r *http.Request, status int, message string) {
+	w.WriteHeader(status)
+	if err := pages.OIDCErrorPage(message).Render(r.Context(), w); err != nil {
+		slog.Error("Failed to render oidc error page", "error", err)
+	}
+}
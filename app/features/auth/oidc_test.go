@@ -0,0 +1,334 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"northstar/app/features/auth/gen/authdb"
+	"northstar/config"
+)
+
+// testIssuer is an OpenID Connect provider that signs in whoever asks, as
+// the identity in its fields.
+type testIssuer struct {
+	*httptest.Server
+	key *rsa.PrivateKey
+
+	mu        sync.Mutex
+	subject   string
+	email     string
+	verified  bool
+	nonce     string
+	challenge string
+}
+
+func newTestIssuer(t *testing.T) *testIssuer {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	iss := &testIssuer{key: key, subject: "subject-1", email: "oidc@example.com", verified: true}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		serveJSON(w, http.StatusOK, map[string]any{
+			"issuer":                                iss.URL,
+			"authorization_endpoint":                iss.URL + "/authorize",
+			"token_endpoint":                        iss.URL + "/token",
+			"jwks_uri":                              iss.URL + "/keys",
+			"response_types_supported":              []string{"code"},
+			"subject_types_supported":               []string{"public"},
+			"id_token_signing_alg_values_supported": []string{"RS256"},
+		})
+	})
+	mux.HandleFunc("GET /keys", func(w http.ResponseWriter, r *http.Request) {
+		serveJSON(w, http.StatusOK, map[string]any{"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": "test",
+			"use": "sig",
+			"alg": "RS256",
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+	})
+	mux.HandleFunc("POST /token", func(w http.ResponseWriter, r *http.Request) {
+		iss.mu.Lock()
+		defer iss.mu.Unlock()
+
+		sum := sha256.Sum256([]byte(r.FormValue("code_verifier")))
+		if r.FormValue("code") != "code" || base64.RawURLEncoding.EncodeToString(sum[:]) != iss.challenge {
+			serveJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+			return
+		}
+		now := time.Now()
+		serveJSON(w, http.StatusOK, map[string]any{
+			"access_token": "access",
+			"token_type":   "Bearer",
+			"id_token": iss.sign(t, map[string]any{
+				"iss":            iss.URL,
+				"sub":            iss.subject,
+				"aud":            "client",
+				"iat":            now.Unix(),
+				"exp":            now.Add(time.Hour).Unix(),
+				"nonce":          iss.nonce,
+				"email":          iss.email,
+				"email_verified": iss.verified,
+			}),
+		})
+	})
+	iss.Server = httptest.NewServer(mux)
+	t.Cleanup(iss.Close)
+	return iss
+}
+
+// authorize plays the provider's sign in page for the URL the app redirected
+// to, returning the query the provider redirects back with.
+func (iss *testIssuer) authorize(t *testing.T, authURL string) url.Values {
+	t.Helper()
+	u, err := url.Parse(authURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	query := u.Query()
+	iss.mu.Lock()
+	iss.nonce, iss.challenge = query.Get("nonce"), query.Get("code_challenge")
+	iss.mu.Unlock()
+	return url.Values{"state": {query.Get("state")}, "code": {"code"}}
+}
+
+func (iss *testIssuer) sign(t *testing.T, claims map[string]any) string {
+	t.Helper()
+	header, err := json.Marshal(map[string]string{"alg": "RS256", "kid": "test", "typ": "JWT"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signed := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	sum := sha256.Sum256([]byte(signed))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, iss.key, crypto.SHA256, sum[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+// newTestOIDCHandlers returns handlers signing in with iss.
+func newTestOIDCHandlers(t *testing.T, iss *testIssuer) *authHandlers {
+	t.Helper()
+	prev := config.Global.BaseURL
+	config.Global.BaseURL = "http://localhost:8080"
+	t.Cleanup(func() { config.Global.BaseURL = prev })
+
+	h := newTestAuthHandlers(t)
+	h.oidc = &oidcProvider{Name: "Test", issuer: iss.URL, clientID: "client", clientSecret: "secret"}
+	return h
+}
+
+// startOIDCLogin begins signing in, returning the session cookie and where
+// the app sent the browser.
+func startOIDCLogin(t *testing.T, h *authHandlers) (*http.Cookie, string) {
+	t.Helper()
+	w := httptest.NewRecorder()
+	h.handleOIDCLogin(w, httptest.NewRequestWithContext(t.Context(), http.MethodGet, "/login/oidc", nil))
+	if w.Code != http.StatusSeeOther {
+		t.Fatalf("login: status %d, want %d", w.Code, http.StatusSeeOther)
+	}
+	return sessionCookie(t, w, nil), w.Header().Get("Location")
+}
+
+// oidcCallback sends the provider's redirect back to the app.
+func oidcCallback(t *testing.T, h *authHandlers, cookie *http.Cookie, query url.Values) (*httptest.ResponseRecorder, *http.Cookie) {
+	t.Helper()
+	r := httptest.NewRequestWithContext(t.Context(), http.MethodGet, "/login/oidc/callback?"+query.Encode(), nil)
+	r.AddCookie(cookie)
+	w := httptest.NewRecorder()
+	h.handleOIDCCallback(w, r)
+	return w, sessionCookie(t, w, cookie)
+}
+
+// sessionCookie returns the auth-session cookie w set last, as browsers keep
+// it, or prev when it set none.
+func sessionCookie(t *testing.T, w *httptest.ResponseRecorder, prev *http.Cookie) *http.Cookie {
+	t.Helper()
+	cookie := prev
+	for _, set := range w.Result().Cookies() {
+		if set.Name == "auth-session" {
+			cookie = set
+		}
+	}
+	if cookie == nil {
+		t.Fatal("no session cookie set")
+	}
+	return cookie
+}
+
+// sessionValues loads the session the cookie points at.
+func sessionValues(t *testing.T, h *authHandlers, cookie *http.Cookie) map[any]any {
+	t.Helper()
+	r := httptest.NewRequestWithContext(t.Context(), http.MethodGet, "/", nil)
+	r.AddCookie(cookie)
+	session, err := h.store.Get(r, "auth-session")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return session.Values
+}
+
+func TestOIDCCallbackCreatesUser(t *testing.T) {
+	iss := newTestIssuer(t)
+	h := newTestOIDCHandlers(t, iss)
+
+	cookie, authURL := startOIDCLogin(t, h)
+	if !strings.HasPrefix(authURL, iss.URL+"/authorize?") {
+		t.Fatalf("redirected to %q, want the provider", authURL)
+	}
+	w, cookie := oidcCallback(t, h, cookie, iss.authorize(t, authURL))
+	if w.Code != http.StatusSeeOther || w.Header().Get("Location") != "/" {
+		t.Fatalf("callback: status %d to %q, want %d to /\n%s", w.Code, w.Header().Get("Location"), http.StatusSeeOther, w.Body)
+	}
+
+	user, err := h.repository.getUserByEmail(t.Context(), iss.email)
+	if err != nil {
+		t.Fatalf("user not created: %v", err)
+	}
+	if user.Username != "oidc" {
+		t.Errorf("username %q, want %q", user.Username, "oidc")
+	}
+	values := sessionValues(t, h, cookie)
+	if values["user_id"] != user.ID {
+		t.Errorf("session signed in as %v, want %v", values["user_id"], user.ID)
+	}
+	if _, ok := values[oidcStateKey]; ok {
+		t.Error("oidc state left in the session")
+	}
+}
+
+func TestOIDCCallbackLinksVerifiedUser(t *testing.T) {
+	iss := newTestIssuer(t)
+	h := newTestOIDCHandlers(t, iss)
+	existing := createTestUser(t, h, iss.email, "correct horse")
+	if _, err := h.repository.queries.VerifyUserEmail(t.Context(), authdb.VerifyUserEmailParams{
+		ID:    existing.ID,
+		Email: existing.Email,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	cookie, authURL := startOIDCLogin(t, h)
+	w, cookie := oidcCallback(t, h, cookie, iss.authorize(t, authURL))
+	if w.Code != http.StatusSeeOther {
+		t.Fatalf("callback: status %d, want %d\n%s", w.Code, http.StatusSeeOther, w.Body)
+	}
+	if got := sessionValues(t, h, cookie)["user_id"]; got != existing.ID {
+		t.Errorf("session signed in as %v, want the existing user %v", got, existing.ID)
+	}
+}
+
+func TestOIDCCallbackErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		// setup prepares the provider and returns the query to call back with
+		setup  func(t *testing.T, h *authHandlers, iss *testIssuer, authURL string) url.Values
+		status int
+	}{
+		{
+			name: "provider error",
+			setup: func(t *testing.T, h *authHandlers, iss *testIssuer, authURL string) url.Values {
+				query := iss.authorize(t, authURL)
+				query.Set("error", "access_denied")
+				return query
+			},
+			status: http.StatusBadRequest,
+		},
+		{
+			name: "wrong state",
+			setup: func(t *testing.T, h *authHandlers, iss *testIssuer, authURL string) url.Values {
+				query := iss.authorize(t, authURL)
+				query.Set("state", "forged")
+				return query
+			},
+			status: http.StatusBadRequest,
+		},
+		{
+			name: "wrong nonce",
+			setup: func(t *testing.T, h *authHandlers, iss *testIssuer, authURL string) url.Values {
+				query := iss.authorize(t, authURL)
+				iss.nonce = "replayed"
+				return query
+			},
+			status: http.StatusBadRequest,
+		},
+		{
+			name: "failed exchange",
+			setup: func(t *testing.T, h *authHandlers, iss *testIssuer, authURL string) url.Values {
+				query := iss.authorize(t, authURL)
+				query.Set("code", "stolen")
+				return query
+			},
+			status: http.StatusBadGateway,
+		},
+		{
+			name: "unverified email",
+			setup: func(t *testing.T, h *authHandlers, iss *testIssuer, authURL string) url.Values {
+				iss.verified = false
+				return iss.authorize(t, authURL)
+			},
+			status: http.StatusBadRequest,
+		},
+		{
+			name: "email of an unverified account",
+			setup: func(t *testing.T, h *authHandlers, iss *testIssuer, authURL string) url.Values {
+				createTestUser(t, h, iss.email, "correct horse")
+				return iss.authorize(t, authURL)
+			},
+			status: http.StatusConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			iss := newTestIssuer(t)
+			h := newTestOIDCHandlers(t, iss)
+
+			cookie, authURL := startOIDCLogin(t, h)
+			w, cookie := oidcCallback(t, h, cookie, tt.setup(t, h, iss, authURL))
+			if w.Code != tt.status {
+				t.Errorf("status %d, want %d\n%s", w.Code, tt.status, w.Body)
+			}
+
+			values := sessionValues(t, h, cookie)
+			if _, ok := values["user_id"]; ok {
+				t.Error("signed in despite the error")
+			}
+			for _, key := range []string{oidcStateKey, oidcNonceKey, oidcVerifierKey, oidcUntilKey} {
+				if _, ok := values[key]; ok {
+					t.Errorf("%s left in the session", key)
+				}
+			}
+		})
+	}
+}
+
+// serveJSON writes v as the JSON body of a response with status.
+func serveJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	// the maps the issuer serves always encode
+	_ = json.NewEncoder(w).Encode(v)
+}
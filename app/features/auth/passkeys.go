@@ -0,0 +1,333 @@
+package auth
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+
+	"northstar/app/features/auth/gen/authdb"
+	"northstar/app/features/auth/pages"
+	"northstar/app/middleware"
+	"northstar/config"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/starfederation/datastar-go/datastar"
+)
+
+// Session values holding a WebAuthn ceremony between its two requests.
+const (
+	passkeyRegistrationKey = "webauthn_registration"
+	passkeyLoginKey        = "webauthn_login"
+)
+
+// passkeyAddedKey marks a session that just registered a passkey, so the
+// passkey list it fetches next says so.
+const passkeyAddedKey = "passkey_added"
+
+// newWebAuthn configures passkeys for the origin in config.Global.BaseURL.
+// Passkeys only work on that origin.
+func newWebAuthn() (*webauthn.WebAuthn, error) {
+	origin := config.Global.BaseURL
+	u, err := url.Parse(origin)
+	if err != nil {
+		return nil, fmt.Errorf("parsing base url: %w", err)
+	}
+	return webauthn.New(&webauthn.Config{
+		RPID:          u.Hostname(),
+		RPDisplayName: "Northstar",
+		RPOrigins:     []string{origin},
+	})
+}
+
+// passkeyUser is a user along with their passkeys, as the webauthn package
+// wants them.
+type passkeyUser struct {
+	user        authdb.User
+	credentials []webauthn.Credential
+}
+
+func (u *passkeyUser) WebAuthnID() []byte                         { return []byte(u.user.ID) }
+func (u *passkeyUser) WebAuthnName() string                       { return u.user.Email }
+func (u *passkeyUser) WebAuthnDisplayName() string                { return u.user.Username }
+func (u *passkeyUser) WebAuthnCredentials() []webauthn.Credential { return u.credentials }
+
+func (h *authHandlers) loadPasskeyUser(ctx context.Context, user authdb.User) (*passkeyUser, error) {
+	rows, err := h.repository.queries.ListUserWebAuthnCredentials(ctx, user.ID)
+	if err != nil {
+		return nil, fmt.Errorf("listing passkeys: %w", err)
+	}
+	pu := &passkeyUser{user: user}
+	for _, row := range rows {
+		var credential webauthn.Credential
+		if err := json.Unmarshal([]byte(row.Data), &credential); err != nil {
+			return nil, fmt.Errorf("decoding passkey %s: %w", row.ID, err)
+		}
+		pu.credentials = append(pu.credentials, credential)
+	}
+	return pu, nil
+}
+
+func passkeyID(credential *webauthn.Credential) string {
+	return base64.RawURLEncoding.EncodeToString(credential.ID)
+}
+
+// saveCeremony keeps data in the session until the browser finishes the
+// ceremony it started.
+func (h *authHandlers) saveCeremony(w http.ResponseWriter, r *http.Request, key string, data *webauthn.SessionData) error {
+	session, err := h.store.Get(r, "auth-session")
+	if err != nil {
+		return fmt.Errorf("getting session: %w", err)
+	}
+	b, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	session.Values[key] = string(b)
+	return session.Save(r, w)
+}
+
+// takeCeremony returns the data saveCeremony kept, so each ceremony can only
+// be finished once.
+func (h *authHandlers) takeCeremony(w http.ResponseWriter, r *http.Request, key string) (webauthn.SessionData, bool) {
+	var data webauthn.SessionData
+	session, err := h.store.Get(r, "auth-session")
+	if err != nil {
+		return data, false
+	}
+	raw, ok := session.Values[key].(string)
+	if !ok {
+		return data, false
+	}
+	delete(session.Values, key)
+	if err := session.Save(r, w); err != nil {
+		slog.Error("Error saving session", "error", err)
+	}
+	return data, json.Unmarshal([]byte(raw), &data) == nil
+}
+
+// writeJSON answers the requests of the passkey-ceremony web component.
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		slog.Error("Failed to write json", "error", err)
+	}
+}
+
+func writeJSONError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"error": message})
+}
+
+func (h *authHandlers) handleBeginPasskeyRegistration(w http.ResponseWriter, r *http.Request) {
+	user, _ := middleware.GetUserFromContext(r.Context())
+	pu, err := h.loadPasskeyUser(r.Context(), user)
+	if err != nil {
+		slog.Error("Failed to load passkeys", "user_id", user.ID, "error", err)
+		writeJSONError(w, http.StatusInternalServerError, MsgPasskeyFailed)
+		return
+	}
+
+	creation, data, err := h.webauthn.BeginRegistration(pu,
+		webauthn.WithResidentKeyRequirement(protocol.ResidentKeyRequirementRequired),
+		webauthn.WithExclusions(webauthn.Credentials(pu.credentials).CredentialDescriptors()),
+	)
+	if err == nil {
+		err = h.saveCeremony(w, r, passkeyRegistrationKey, data)
+	}
+	if err != nil {
+		slog.Error("Failed to begin passkey registration", "user_id", user.ID, "error", err)
+		writeJSONError(w, http.StatusInternalServerError, MsgPasskeyFailed)
+		return
+	}
+	writeJSON(w, http.StatusOK, creation)
+}
+
+func (h *authHandlers) handleFinishPasskeyRegistration(w http.ResponseWriter, r *http.Request) {
+	user, _ := middleware.GetUserFromContext(r.Context())
+	data, ok := h.takeCeremony(w, r, passkeyRegistrationKey)
+	if !ok {
+		writeJSONError(w, http.StatusBadRequest, MsgPasskeyExpired)
+		return
+	}
+	pu, err := h.loadPasskeyUser(r.Context(), user)
+	if err != nil {
+		slog.Error("Failed to load passkeys", "user_id", user.ID, "error", err)
+		writeJSONError(w, http.StatusInternalServerError, MsgPasskeyFailed)
+		return
+	}
+
+	credential, err := h.webauthn.FinishRegistration(pu, data, r)
+	if err != nil {
+		slog.Info("Passkey registration rejected", "user_id", user.ID, "error", err)
+		writeJSONError(w, http.StatusBadRequest, MsgPasskeyRejected)
+		return
+	}
+	b, err := json.Marshal(credential)
+	if err == nil {
+		err = h.repository.queries.CreateWebAuthnCredential(r.Context(), authdb.CreateWebAuthnCredentialParams{
+			ID:     passkeyID(credential),
+			UserID: user.ID,
+			Name:   describeUserAgent(r.UserAgent()),
+			Data:   string(b),
+		})
+	}
+	if err != nil {
+		slog.Error("Failed to save passkey", "user_id", user.ID, "error", err)
+		writeJSONError(w, http.StatusInternalServerError, MsgPasskeyFailed)
+		return
+	}
+	slog.Info("Passkey registered", "user_id", user.ID)
+
+	if session, err := h.store.Get(r, "auth-session"); err == nil {
+		session.Values[passkeyAddedKey] = true
+		if err := session.Save(r, w); err != nil {
+			slog.Error("Error saving session", "error", err)
+		}
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"message": MsgPasskeyAdded})
+}
+
+// handleBeginPasskeyLogin asks the browser for any passkey of this site, so
+// users do not have to enter their email address first.
+func (h *authHandlers) handleBeginPasskeyLogin(w http.ResponseWriter, r *http.Request) {
+	assertion, data, err := h.webauthn.BeginDiscoverableLogin(
+		webauthn.WithUserVerification(protocol.VerificationRequired),
+	)
+	if err == nil {
+		err = h.saveCeremony(w, r, passkeyLoginKey, data)
+	}
+	if err != nil {
+		slog.Error("Failed to begin passkey login", "error", err)
+		writeJSONError(w, http.StatusInternalServerError, MsgPasskeyFailed)
+		return
+	}
+	writeJSON(w, http.StatusOK, assertion)
+}
+
+// handleFinishPasskeyLogin logs the owner of the passkey in. A passkey
+// verifies the user on their device, so it stands in for both the password
+// and the second factor.
+func (h *authHandlers) handleFinishPasskeyLogin(w http.ResponseWriter, r *http.Request) {
+	data, ok := h.takeCeremony(w, r, passkeyLoginKey)
+	if !ok {
+		writeJSONError(w, http.StatusBadRequest, MsgPasskeyExpired)
+		return
+	}
+
+	found, credential, err := h.webauthn.FinishPasskeyLogin(func(rawID, userHandle []byte) (webauthn.User, error) {
+		user, err := h.repository.queries.GetUser(r.Context(), string(userHandle))
+		if err != nil {
+			return nil, fmt.Errorf("getting user: %w", err)
+		}
+		return h.loadPasskeyUser(r.Context(), user)
+	}, data, r)
+	if err != nil {
+		slog.Info("Passkey login rejected", "error", err)
+		writeJSONError(w, http.StatusUnauthorized, MsgPasskeyRejected)
+		return
+	}
+	user := found.(*passkeyUser).user
+	if user.Disabled {
+		writeJSONError(w, http.StatusForbidden, MsgAccountDisabled)
+		return
+	}
+	if credential.Authenticator.CloneWarning {
+		slog.Warn("Passkey may have been cloned", "user_id", user.ID)
+		writeJSONError(w, http.StatusUnauthorized, MsgPasskeyRejected)
+		return
+	}
+
+	// keep the new signature counter, which is how clones are spotted
+	b, err := json.Marshal(credential)
+	if err == nil {
+		err = h.repository.queries.UpdateWebAuthnCredential(r.Context(), authdb.UpdateWebAuthnCredentialParams{
+			Data: string(b),
+			ID:   passkeyID(credential),
+		})
+	}
+	if err != nil {
+		slog.Error("Failed to update passkey", "user_id", user.ID, "error", err)
+	}
+
+	if err := h.limiter.reset(r.Context(), accountLimitKey(user.Email)); err != nil {
+		slog.Error("Error resetting login attempts", "error", err)
+	}
+	if err := h.createSession(w, r, &user); err != nil {
+		slog.Error("Error creating session", "error", err)
+		writeJSONError(w, http.StatusInternalServerError, MsgLoginFailed)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"redirect": "/"})
+}
+
+func (h *authHandlers) userPasskeys(ctx context.Context, userID string) ([]pages.Passkey, error) {
+	rows, err := h.repository.queries.ListUserWebAuthnCredentials(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("listing passkeys: %w", err)
+	}
+	passkeys := make([]pages.Passkey, 0, len(rows))
+	for _, row := range rows {
+		passkeys = append(passkeys, pages.Passkey{
+			ID:        row.ID,
+			Name:      row.Name,
+			CreatedAt: row.CreatedAt.Time,
+			LastUsed:  row.LastUsedAt.Time,
+		})
+	}
+	return passkeys, nil
+}
+
+func (h *authHandlers) patchPasskeys(w http.ResponseWriter, r *http.Request, userID, message string) {
+	passkeys, err := h.userPasskeys(r.Context(), userID)
+	if err != nil {
+		slog.Error("Failed to list passkeys", "user_id", userID, "error", err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	sse := datastar.NewSSE(w, r)
+	if err := sse.PatchElementTempl(pages.PasskeysSection(passkeys, message)); err != nil {
+		slog.Error("Failed to patch elements", "error", err)
+	}
+}
+
+// handlePasskeys sends the passkey list, saying a passkey was added only right
+// after handleFinishPasskeyRegistration added one.
+func (h *authHandlers) handlePasskeys(w http.ResponseWriter, r *http.Request) {
+	message := ""
+	if session, err := h.store.Get(r, "auth-session"); err == nil {
+		if added, _ := session.Values[passkeyAddedKey].(bool); added {
+			message = MsgPasskeyAdded
+			delete(session.Values, passkeyAddedKey)
+			if err := session.Save(r, w); err != nil {
+				slog.Error("Error saving session", "error", err)
+			}
+		}
+	}
+	h.patchPasskeys(w, r, middleware.GetUserIDFromContext(r.Context()), message)
+}
+
+func (h *authHandlers) handleDeletePasskey(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserIDFromContext(r.Context())
+
+	message := MsgPasskeyRemoved
+	rows, err := h.repository.queries.DeleteUserWebAuthnCredential(r.Context(), authdb.DeleteUserWebAuthnCredentialParams{
+		ID:     chi.URLParam(r, "id"),
+		UserID: userID,
+	})
+	switch {
+	case err != nil:
+		slog.Error("Failed to delete passkey", "user_id", userID, "error", err)
+		message = MsgPasskeyFailed
+	case rows == 0:
+		message = MsgPasskeyNotFound
+	}
+
+	h.patchPasskeys(w, r, userID, message)
+}
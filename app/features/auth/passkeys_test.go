@@ -0,0 +1,47 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"northstar/app/middleware"
+)
+
+func TestPasskeysSaysAddedOnce(t *testing.T) {
+	h := newTestAuthHandlers(t)
+	user := createTestUser(t, h, "a@example.com", "correct horse")
+	ctx := context.WithValue(t.Context(), middleware.UserContextKey, user)
+
+	// a session that just finished registering a passkey
+	r := httptest.NewRequestWithContext(ctx, http.MethodGet, "/", nil)
+	w := httptest.NewRecorder()
+	session, err := h.store.Get(r, "auth-session")
+	if err != nil {
+		t.Fatal(err)
+	}
+	session.Values[passkeyAddedKey] = true
+	if err := session.Save(r, w); err != nil {
+		t.Fatal(err)
+	}
+	cookie := sessionCookie(t, w, nil)
+
+	list := func() string {
+		t.Helper()
+		r := httptest.NewRequestWithContext(ctx, http.MethodGet, "/profile/passkeys", nil)
+		r.AddCookie(cookie)
+		w := httptest.NewRecorder()
+		h.handlePasskeys(w, r)
+		cookie = sessionCookie(t, w, cookie)
+		return w.Body.String()
+	}
+
+	if body := list(); !strings.Contains(body, MsgPasskeyAdded) {
+		t.Errorf("list after adding a passkey does not say so:\n%s", body)
+	}
+	if body := list(); strings.Contains(body, MsgPasskeyAdded) {
+		t.Errorf("list says a passkey was added when none was:\n%s", body)
+	}
+}
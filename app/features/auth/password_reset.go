@@ -0,0 +1,223 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"northstar/app/features/auth/gen/authdb"
+	"northstar/app/features/auth/pages"
+	"northstar/app/features/common/utils"
+	"northstar/mail"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/starfederation/datastar-go/datastar"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// passwordResetTTL is how long the link in a password reset email works.
+const passwordResetTTL = time.Hour
+
+// newPasswordResetToken returns a random token for a reset link along with
+// the hash stored in its place, so a leaked database cannot be used to reset
+// passwords.
+func newPasswordResetToken() (token, hash string, err error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+	token = base64.RawURLEncoding.EncodeToString(b)
+	return token, hashPasswordResetToken(token), nil
+}
+
+func hashPasswordResetToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// sendPasswordResetEmail mails user a single-use link to choose a new password.
+func (h *authHandlers) sendPasswordResetEmail(r *http.Request, user *authdb.User) error {
+	token, hash, err := newPasswordResetToken()
+	if err != nil {
+		return fmt.Errorf("generating reset token: %w", err)
+	}
+	if err := h.repository.queries.CreatePasswordResetToken(r.Context(), authdb.CreatePasswordResetTokenParams{
+		TokenHash: hash,
+		UserID:    user.ID,
+		ExpiresAt: time.Now().Add(passwordResetTTL),
+	}); err != nil {
+		return fmt.Errorf("storing reset token: %w", err)
+	}
+
+	link := absoluteURL("/reset-password/" + token)
+	return h.mailer.Send(r.Context(), mail.Message{
+		To:      user.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf("Hi %s,\n\nFollow this link to choose a new password:\n\n%s\n\nThe link works once and expires in %d minutes. If you did not ask to reset your password, you can ignore this email.\n",
+			user.Username, link, int(passwordResetTTL.Minutes())),
+	})
+}
+
+func (h *authHandlers) handleForgotPasswordPage(w http.ResponseWriter, r *http.Request) {
+	if err := pages.ForgotPasswordPage().Render(r.Context(), w); err != nil {
+		slog.Error("Failed to render forgot password page", "error", err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+	}
+}
+
+// handleForgotPassword answers the same way whether or not an account uses
+// the address, so the form cannot be used to find out who has signed up.
+func (h *authHandlers) handleForgotPassword(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		slog.Error("Error parsing form data", "error", err)
+		h.sendGenericError(w, r, MsgInvalidFormData)
+		return
+	}
+
+	email := r.FormValue("email")
+	if email == "" {
+		h.sendLoginErrors(w, r, ValidationErrors{Email: MsgMissingEmail})
+		return
+	}
+
+	user, err := h.repository.queries.GetUserByEmail(r.Context(), email)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+	case err != nil:
+		slog.Error("Error looking up user for password reset", "error", err)
+		h.sendGenericError(w, r, MsgPasswordResetFailed)
+		return
+	default:
+		if err := h.sendPasswordResetEmail(r, &user); err != nil {
+			slog.Error("Failed to send password reset email", "user_id", user.ID, "error", err)
+			h.sendGenericError(w, r, MsgPasswordResetFailed)
+			return
+		}
+	}
+
+	sse := datastar.NewSSE(w, r)
+	if err := sse.PatchElementTempl(pages.ForgotPasswordSent()); err != nil {
+		slog.Error("Failed to patch elements", "error", err)
+	}
+}
+
+func (h *authHandlers) handleResetPasswordPage(w http.ResponseWriter, r *http.Request) {
+	token := chi.URLParam(r, "token")
+	_, err := h.repository.queries.GetPasswordResetToken(r.Context(), authdb.GetPasswordResetTokenParams{
+		TokenHash: hashPasswordResetToken(token),
+		ExpiresAt: time.Now(),
+	})
+	valid := err == nil
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		slog.Error("Failed to look up password reset token", "error", err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	if !valid {
+		w.WriteHeader(http.StatusBadRequest)
+	}
+	if err := pages.ResetPasswordPage(token, valid).Render(r.Context(), w); err != nil {
+		slog.Error("Failed to render reset password page", "error", err)
+	}
+}
+
+// handleResetPassword sets the new password, which logs the user out of every
+// other session, and signs them in here.
+func (h *authHandlers) handleResetPassword(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		slog.Error("Error parsing form data", "error", err)
+		h.sendGenericError(w, r, MsgInvalidFormData)
+		return
+	}
+
+	password := r.FormValue("password")
+	confirm := r.FormValue("confirm_password")
+
+	var validationErr ValidationErrors
+	if len(password) < 6 {
+		validationErr.Password = MsgPasswordTooShort
+	}
+	if confirm != password {
+		validationErr.ConfirmPassword = MsgPasswordMismatch
+	}
+	if validationErr.HasErrors() {
+		h.sendResetPasswordErrors(w, r, validationErr)
+		return
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		slog.Error("Error hashing password", "error", err)
+		h.sendGenericError(w, r, MsgPasswordResetFailed)
+		return
+	}
+
+	user, err := h.repository.resetPassword(r.Context(), hashPasswordResetToken(chi.URLParam(r, "token")), string(hashedPassword))
+	if errors.Is(err, sql.ErrNoRows) {
+		h.sendGenericError(w, r, MsgInvalidPasswordResetLink)
+		return
+	}
+	if err != nil {
+		slog.Error("Error resetting password", "error", err)
+		h.sendGenericError(w, r, MsgPasswordResetFailed)
+		return
+	}
+	slog.Info("Password reset", "user_id", user.ID)
+	if err := h.limiter.reset(r.Context(), accountLimitKey(user.Email)); err != nil {
+		slog.Error("Error resetting login attempts", "error", err)
+	}
+
+	if user.Disabled {
+		h.sendGenericError(w, r, MsgAccountDisabled)
+		return
+	}
+
+	// a reset link proves access to the inbox, not to the second factor
+	_, enrolled, err := h.enrolledTOTP(r.Context(), user.ID)
+	if err != nil {
+		slog.Error("Error checking two-factor enrollment", "user_id", user.ID, "error", err)
+		h.sendGenericError(w, r, MsgPasswordResetLoginFailed)
+		return
+	}
+	if enrolled {
+		if err := h.startTwoFactor(w, r, &user); err != nil {
+			slog.Error("Error starting two-factor login", "error", err)
+			h.sendGenericError(w, r, MsgPasswordResetLoginFailed)
+		}
+		return
+	}
+
+	if err := h.createSession(w, r, &user); err != nil {
+		slog.Error("Error creating session after password reset", "error", err)
+		h.sendGenericError(w, r, MsgPasswordResetLoginFailed)
+		return
+	}
+
+	sse := datastar.NewSSE(w, r)
+	if err := sse.ExecuteScript("window.location.href = '/'"); err != nil {
+		slog.Error("Failed to execute script", "error", err)
+	}
+}
+
+func (h *authHandlers) sendResetPasswordErrors(w http.ResponseWriter, r *http.Request, errors ValidationErrors) {
+	sse := datastar.NewSSE(w, r)
+
+	var allHTML string
+
+	html, _ := utils.RenderTemplToString(r.Context(), pages.PasswordError(errors.Password))
+	allHTML += html
+	html, _ = utils.RenderTemplToString(r.Context(), pages.ConfirmPasswordError(errors.ConfirmPassword))
+	allHTML += html
+
+	if err := sse.PatchElements(allHTML); err != nil {
+		slog.Error("Failed to patch elements", "error", err)
+	}
+}
@@ -0,0 +1,9 @@
+-- name: GetUserIdentity :one
+SELECT * FROM user_identities WHERE issuer = ? AND subject = ? LIMIT 1;
+
+-- name: CreateUserIdentity :exec
+INSERT INTO user_identities (issuer, subject, user_id, email)
+VALUES (?, ?, ?, ?);
+
+-- name: DeleteUserIdentities :exec
+DELETE FROM user_identities WHERE user_id = ?;
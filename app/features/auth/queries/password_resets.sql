@@ -0,0 +1,16 @@
+-- name: CreatePasswordResetToken :exec
+INSERT INTO password_reset_tokens (token_hash, user_id, expires_at)
+VALUES (?, ?, ?);
+
+-- name: GetPasswordResetToken :one
+SELECT * FROM password_reset_tokens
+WHERE token_hash = ? AND used_at IS NULL AND expires_at > ?
+LIMIT 1;
+
+-- name: UsePasswordResetToken :one
+UPDATE password_reset_tokens SET used_at = CURRENT_TIMESTAMP
+WHERE token_hash = ? AND used_at IS NULL AND expires_at > ?
+RETURNING user_id;
+
+-- name: DeleteUserPasswordResetTokens :exec
+DELETE FROM password_reset_tokens WHERE user_id = ?;
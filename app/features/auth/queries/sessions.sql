@@ -0,0 +1,39 @@
+-- name: GetSession :one
+SELECT * FROM sessions
+WHERE id = ? AND name = ? AND expires_at > ?
+LIMIT 1;
+
+-- name: SaveSession :exec
+INSERT INTO sessions (id, name, user_id, data, user_agent, ip_address, last_seen_at, expires_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT (id) DO UPDATE SET
+    user_id = excluded.user_id,
+    data = excluded.data,
+    user_agent = excluded.user_agent,
+    ip_address = excluded.ip_address,
+    last_seen_at = excluded.last_seen_at,
+    expires_at = excluded.expires_at;
+
+-- name: TouchSession :exec
+UPDATE sessions SET last_seen_at = ?, user_agent = ?, ip_address = ?
+WHERE id = ?;
+
+-- name: ListUserSessions :many
+SELECT * FROM sessions
+WHERE user_id = ? AND expires_at > ?
+ORDER BY last_seen_at DESC;
+
+-- name: DeleteSession :exec
+DELETE FROM sessions WHERE id = ?;
+
+-- name: DeleteUserSession :execrows
+DELETE FROM sessions WHERE id = ? AND user_id = ?;
+
+-- name: DeleteOtherUserSessions :execrows
+DELETE FROM sessions WHERE user_id = ? AND id != ?;
+
+-- name: DeleteUserSessions :exec
+DELETE FROM sessions WHERE user_id = ?;
+
+-- name: DeleteExpiredSessions :execrows
+DELETE FROM sessions WHERE expires_at <= ?;
@@ -0,0 +1,33 @@
+-- name: GetUserTOTP :one
+SELECT * FROM user_totp WHERE user_id = ? LIMIT 1;
+
+-- name: SaveUserTOTP :exec
+INSERT INTO user_totp (user_id, secret)
+VALUES (?, ?)
+ON CONFLICT (user_id) DO UPDATE SET
+    secret = excluded.secret,
+    confirmed = FALSE,
+    last_used_step = 0;
+
+-- name: ConfirmUserTOTP :exec
+UPDATE user_totp SET confirmed = TRUE WHERE user_id = ?;
+
+-- name: UseTOTPStep :execrows
+UPDATE user_totp SET last_used_step = ?
+WHERE user_id = ? AND last_used_step < ?;
+
+-- name: DeleteUserTOTP :exec
+DELETE FROM user_totp WHERE user_id = ?;
+
+-- name: CreateRecoveryCode :exec
+INSERT INTO recovery_codes (user_id, code_hash) VALUES (?, ?);
+
+-- name: CountUnusedRecoveryCodes :one
+SELECT COUNT(*) FROM recovery_codes WHERE user_id = ? AND used_at IS NULL;
+
+-- name: UseRecoveryCode :execrows
+UPDATE recovery_codes SET used_at = CURRENT_TIMESTAMP
+WHERE user_id = ? AND code_hash = ? AND used_at IS NULL;
+
+-- name: DeleteRecoveryCodes :exec
+DELETE FROM recovery_codes WHERE user_id = ?;
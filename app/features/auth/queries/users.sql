@@ -2,7 +2,14 @@
 SELECT * FROM users WHERE id = ? LIMIT 1;
 
 -- name: ListUsers :many
-SELECT * FROM users ORDER BY username;
+SELECT * FROM users
+WHERE username LIKE sqlc.arg(search) ESCAPE '\' OR email LIKE sqlc.arg(search) ESCAPE '\'
+ORDER BY username
+LIMIT sqlc.arg(limit) OFFSET sqlc.arg(offset);
+
+-- name: CountUsers :one
+SELECT COUNT(*) FROM users
+WHERE username LIKE sqlc.arg(search) ESCAPE '\' OR email LIKE sqlc.arg(search) ESCAPE '\';
 
 -- name: CreateUser :one
 INSERT INTO users (id, username, email, password_hash) 
@@ -13,7 +20,8 @@ RETURNING *;
 SELECT * FROM users WHERE email = ? LIMIT 1;
 
 -- name: UpdateUser :exec
-UPDATE users SET username = ?, email = ? WHERE id = ?;
+UPDATE users SET username = ?1, email = ?2, email_verified = email_verified AND email = ?2
+WHERE id = ?3;
 
 -- name: DeleteUser :exec
 DELETE FROM users WHERE id = ?;
@@ -22,4 +30,16 @@ DELETE FROM users WHERE id = ?;
 SELECT EXISTS(SELECT 1 FROM users WHERE email = ?);
 
 -- name: CheckIfUserExistsByUsername :one
-SELECT EXISTS(SELECT 1 FROM users WHERE username = ?);
\ No newline at end of file
+SELECT EXISTS(SELECT 1 FROM users WHERE username = ?);
+
+-- name: VerifyUserEmail :execrows
+UPDATE users SET email_verified = TRUE WHERE id = ? AND email = ?;
+
+-- name: UpdateUserPassword :exec
+UPDATE users SET password_hash = ?, session_version = session_version + 1 WHERE id = ?;
+
+-- name: SetUserRole :execrows
+UPDATE users SET role = ? WHERE id = ?;
+
+-- name: SetUserDisabled :execrows
+UPDATE users SET disabled = ?, session_version = session_version + 1 WHERE id = ?;
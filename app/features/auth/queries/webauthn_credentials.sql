@@ -0,0 +1,16 @@
+-- name: CreateWebAuthnCredential :exec
+INSERT INTO webauthn_credentials (id, user_id, name, data)
+VALUES (?, ?, ?, ?);
+
+-- name: ListUserWebAuthnCredentials :many
+SELECT * FROM webauthn_credentials WHERE user_id = ? ORDER BY created_at;
+
+-- name: UpdateWebAuthnCredential :exec
+UPDATE webauthn_credentials SET data = ?, last_used_at = CURRENT_TIMESTAMP
+WHERE id = ?;
+
+-- name: DeleteUserWebAuthnCredential :execrows
+DELETE FROM webauthn_credentials WHERE id = ? AND user_id = ?;
+
+-- name: DeleteUserWebAuthnCredentials :exec
+DELETE FROM webauthn_credentials WHERE user_id = ?;
@@ -0,0 +1,147 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"northstar/app/features/index/services"
+
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+// Failed sign-ins are counted over a sliding window, per account and per IP
+// address. Reaching the limit for either locks it out for loginLockout.
+const (
+	loginWindow        = 15 * time.Minute
+	loginLockout       = 15 * time.Minute
+	maxAccountFailures = 5
+	maxIPFailures      = 20
+)
+
+// maxLimiterAttempts bounds how often fail retries after losing a race with
+// another request for the same key.
+const maxLimiterAttempts = 5
+
+// loginAttempts is what the limiter keeps for each account or IP address.
+type loginAttempts struct {
+	Failures    []time.Time `json:"failures,omitempty"`
+	LockedUntil time.Time   `json:"lockedUntil,omitzero"`
+}
+
+// loginLimiter keeps failed sign-ins in a NATS KV bucket, so lockouts
+// survive restarts.
+type loginLimiter struct {
+	kv jetstream.KeyValue
+}
+
+func newLoginLimiter(ctx context.Context, js jetstream.JetStream) (*loginLimiter, error) {
+	kv, err := js.CreateOrUpdateKeyValue(ctx, jetstream.KeyValueConfig{
+		Bucket:      "auth-login-attempts",
+		Description: "Failed sign-ins per account and IP address",
+		TTL:         loginWindow + loginLockout,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error creating key value: %w", err)
+	}
+	return &loginLimiter{kv: kv}, nil
+}
+
+// accountLimitKey and ipLimitKey hash what they are given, since emails and
+// IPv6 addresses contain characters KV keys cannot.
+func accountLimitKey(email string) string {
+	return "account." + limitKeyHash(strings.ToLower(strings.TrimSpace(email)))
+}
+
+func ipLimitKey(ip string) string {
+	return "ip." + limitKeyHash(ip)
+}
+
+func limitKeyHash(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:16])
+}
+
+// lockedUntil returns when the latest lockout among keys ends, or the zero
+// time when none of them is locked out.
+func (l *loginLimiter) lockedUntil(ctx context.Context, now time.Time, keys ...string) (time.Time, error) {
+	var until time.Time
+	for _, key := range keys {
+		attempts, _, err := l.get(ctx, key)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if attempts.LockedUntil.After(now) && attempts.LockedUntil.After(until) {
+			until = attempts.LockedUntil
+		}
+	}
+	return until, nil
+}
+
+// fail records a failed sign-in against key, locking it out once it reaches
+// limit failures within loginWindow.
+func (l *loginLimiter) fail(ctx context.Context, now time.Time, key string, limit int) error {
+	for range maxLimiterAttempts {
+		attempts, revision, err := l.get(ctx, key)
+		if err != nil {
+			return err
+		}
+
+		failures := attempts.Failures[:0]
+		for _, at := range attempts.Failures {
+			if now.Sub(at) < loginWindow {
+				failures = append(failures, at)
+			}
+		}
+		attempts.Failures = append(failures, now)
+		if len(attempts.Failures) >= limit {
+			attempts.LockedUntil = now.Add(loginLockout)
+			attempts.Failures = nil
+		}
+
+		b, err := json.Marshal(attempts)
+		if err != nil {
+			return err
+		}
+		if revision == 0 {
+			_, err = l.kv.Create(ctx, key, b)
+		} else {
+			_, err = l.kv.Update(ctx, key, b, revision)
+		}
+		if err == nil {
+			return nil
+		}
+		if !errors.Is(err, jetstream.ErrKeyExists) && !services.IsWrongLastSequence(err) {
+			return fmt.Errorf("failed to record login attempt: %w", err)
+		}
+	}
+	return fmt.Errorf("failed to record login attempt after %d attempts", maxLimiterAttempts)
+}
+
+// reset forgets the failures recorded against key.
+func (l *loginLimiter) reset(ctx context.Context, key string) error {
+	if err := l.kv.Purge(ctx, key); err != nil && !errors.Is(err, jetstream.ErrKeyNotFound) {
+		return fmt.Errorf("failed to reset login attempts: %w", err)
+	}
+	return nil
+}
+
+func (l *loginLimiter) get(ctx context.Context, key string) (loginAttempts, uint64, error) {
+	var attempts loginAttempts
+	entry, err := l.kv.Get(ctx, key)
+	if errors.Is(err, jetstream.ErrKeyNotFound) {
+		return attempts, 0, nil
+	}
+	if err != nil {
+		return attempts, 0, fmt.Errorf("failed to get login attempts: %w", err)
+	}
+	if err := json.Unmarshal(entry.Value(), &attempts); err != nil {
+		return attempts, 0, fmt.Errorf("failed to decode login attempts: %w", err)
+	}
+	return attempts, entry.Revision(), nil
+}
@@ -0,0 +1,191 @@
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"northstar/app/features/auth/gen/authdb"
+	"northstar/app/middleware"
+	"northstar/db"
+	"northstar/nats"
+	"northstar/sessionstore"
+
+	"github.com/nats-io/nats.go/jetstream"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// newTestAuthHandlers returns handlers backed by a database and NATS server
+// in a temporary directory.
+func newTestAuthHandlers(t *testing.T) *authHandlers {
+	t.Helper()
+	t.Chdir(t.TempDir())
+
+	database, err := db.InitDatabase()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { database.Close() })
+
+	ns, err := nats.SetupNATS(t.Context())
+	if err != nil {
+		t.Fatal(err)
+	}
+	nc, err := ns.Client()
+	if err != nil {
+		t.Fatal(err)
+	}
+	js, err := jetstream.New(nc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	limiter, err := newLoginLimiter(t.Context(), js)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &authHandlers{
+		repository: &authRepository{
+			db:      database,
+			queries: authdb.New(database),
+			users:   middleware.NewUserCache(database, time.Minute),
+		},
+		store:   sessionstore.New(database, []byte("test")),
+		limiter: limiter,
+	}
+}
+
+// createTestUser adds a user signing in with email and password.
+func createTestUser(t *testing.T, h *authHandlers, email, password string) authdb.User {
+	t.Helper()
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	name, _, _ := strings.Cut(email, "@")
+	user, err := h.repository.createUser(t.Context(), authdb.CreateUserParams{
+		ID:           "user-" + name,
+		Username:     name,
+		Email:        email,
+		PasswordHash: string(hash),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return user
+}
+
+func TestLoginLimiterLocksOut(t *testing.T) {
+	h := newTestAuthHandlers(t)
+	ctx, key := t.Context(), accountLimitKey("a@example.com")
+	now := time.Now()
+
+	for i := range maxAccountFailures {
+		until, err := h.limiter.lockedUntil(ctx, now, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !until.IsZero() {
+			t.Fatalf("locked out after %d failures", i)
+		}
+		if err := h.limiter.fail(ctx, now, key, maxAccountFailures); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	until, err := h.limiter.lockedUntil(ctx, now, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := now.Add(loginLockout); !until.Equal(want) {
+		t.Errorf("locked until %v, want %v", until, want)
+	}
+
+	// the lockout ends on its own
+	until, err = h.limiter.lockedUntil(ctx, now.Add(loginLockout), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !until.IsZero() {
+		t.Errorf("still locked out until %v once the lockout ended", until)
+	}
+}
+
+func TestLoginLimiterWindow(t *testing.T) {
+	h := newTestAuthHandlers(t)
+	ctx, key := t.Context(), ipLimitKey("192.0.2.1")
+	start := time.Now()
+
+	for range maxIPFailures - 1 {
+		if err := h.limiter.fail(ctx, start, key, maxIPFailures); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// the earlier failures have left the window, so this one starts over
+	later := start.Add(loginWindow)
+	if err := h.limiter.fail(ctx, later, key, maxIPFailures); err != nil {
+		t.Fatal(err)
+	}
+	until, err := h.limiter.lockedUntil(ctx, later, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !until.IsZero() {
+		t.Fatalf("locked out by failures outside the window")
+	}
+	attempts, _, err := h.limiter.get(ctx, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(attempts.Failures) != 1 {
+		t.Errorf("%d failures kept, want 1", len(attempts.Failures))
+	}
+}
+
+func TestLoginResetsFailures(t *testing.T) {
+	h := newTestAuthHandlers(t)
+	const email, password = "a@example.com", "correct horse"
+	createTestUser(t, h, email, password)
+
+	login := func(password string) {
+		t.Helper()
+		form := url.Values{"email": {email}, "password": {password}}
+		r := httptest.NewRequestWithContext(t.Context(), http.MethodPost, "/login", strings.NewReader(form.Encode()))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		h.handleLogin(httptest.NewRecorder(), r)
+	}
+	failures := func() int {
+		t.Helper()
+		attempts, _, err := h.limiter.get(t.Context(), accountLimitKey(email))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return len(attempts.Failures)
+	}
+
+	for range maxAccountFailures - 1 {
+		login("wrong")
+	}
+	if got := failures(); got != maxAccountFailures-1 {
+		t.Fatalf("%d failures recorded, want %d", got, maxAccountFailures-1)
+	}
+
+	login(password)
+	if got := failures(); got != 0 {
+		t.Errorf("%d failures left after signing in, want 0", got)
+	}
+
+	// a fresh run of failures is needed to lock the account again
+	login("wrong")
+	until, err := h.limiter.lockedUntil(t.Context(), time.Now(), accountLimitKey(email))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !until.IsZero() {
+		t.Errorf("locked out until %v after one failure", until)
+	}
+}
@@ -2,12 +2,19 @@ package auth
 
 import (
 	"context"
+	"database/sql"
+	"fmt"
+	"time"
 
 	"northstar/app/features/auth/gen/authdb"
+	"northstar/app/middleware"
 )
 
 type authRepository struct {
+	db      *sql.DB
 	queries *authdb.Queries
+	// users must be told about every change to a user
+	users *middleware.UserCache
 }
 
 func (r *authRepository) getUserByEmail(ctx context.Context, email string) (authdb.User, error) {
@@ -26,3 +33,196 @@ func (r *authRepository) checkIfUserExistsByEmail(ctx context.Context, email str
 	exists, err := r.queries.CheckIfUserExistsByEmail(ctx, email)
 	return exists != 0, err
 }
+
+// withTx runs fn with queries bound to a transaction, committing it when fn
+// succeeds.
+func (r *authRepository) withTx(ctx context.Context, fn func(queries *authdb.Queries) error) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := fn(r.queries.WithTx(tx)); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("committing transaction: %w", err)
+	}
+	return nil
+}
+
+// resetPassword uses up the reset token with tokenHash and sets the password
+// of its user, logging them out everywhere. The user's other reset tokens and
+// stored sessions are dropped too. It returns sql.ErrNoRows when the token is
+// unknown, used or expired.
+func (r *authRepository) resetPassword(ctx context.Context, tokenHash, passwordHash string) (authdb.User, error) {
+	var user authdb.User
+	err := r.withTx(ctx, func(queries *authdb.Queries) error {
+		userID, err := queries.UsePasswordResetToken(ctx, authdb.UsePasswordResetTokenParams{
+			TokenHash: tokenHash,
+			ExpiresAt: time.Now(),
+		})
+		if err != nil {
+			return err
+		}
+		if err := queries.UpdateUserPassword(ctx, authdb.UpdateUserPasswordParams{
+			PasswordHash: passwordHash,
+			ID:           userID,
+		}); err != nil {
+			return fmt.Errorf("updating password: %w", err)
+		}
+		if err := queries.DeleteUserPasswordResetTokens(ctx, userID); err != nil {
+			return fmt.Errorf("deleting reset tokens: %w", err)
+		}
+		if err := queries.DeleteUserSessions(ctx, sql.NullString{String: userID, Valid: true}); err != nil {
+			return fmt.Errorf("deleting sessions: %w", err)
+		}
+		user, err = queries.GetUser(ctx, userID)
+		if err != nil {
+			return fmt.Errorf("getting user: %w", err)
+		}
+		return nil
+	})
+	if err == nil {
+		r.users.Invalidate(user.ID)
+	}
+	return user, err
+}
+
+// enableTOTP confirms the user's pending TOTP secret and replaces their
+// recovery codes with codeHashes.
+func (r *authRepository) enableTOTP(ctx context.Context, userID string, codeHashes []string) error {
+	return r.withTx(ctx, func(queries *authdb.Queries) error {
+		if err := queries.ConfirmUserTOTP(ctx, userID); err != nil {
+			return fmt.Errorf("confirming totp: %w", err)
+		}
+		return writeRecoveryCodes(ctx, queries, userID, codeHashes)
+	})
+}
+
+func (r *authRepository) replaceRecoveryCodes(ctx context.Context, userID string, codeHashes []string) error {
+	return r.withTx(ctx, func(queries *authdb.Queries) error {
+		return writeRecoveryCodes(ctx, queries, userID, codeHashes)
+	})
+}
+
+// disableTOTP drops the user's TOTP secret and recovery codes.
+func (r *authRepository) disableTOTP(ctx context.Context, userID string) error {
+	return r.withTx(ctx, func(queries *authdb.Queries) error {
+		if err := queries.DeleteUserTOTP(ctx, userID); err != nil {
+			return fmt.Errorf("deleting totp: %w", err)
+		}
+		if err := queries.DeleteRecoveryCodes(ctx, userID); err != nil {
+			return fmt.Errorf("deleting recovery codes: %w", err)
+		}
+		return nil
+	})
+}
+
+func writeRecoveryCodes(ctx context.Context, queries *authdb.Queries, userID string, codeHashes []string) error {
+	if err := queries.DeleteRecoveryCodes(ctx, userID); err != nil {
+		return fmt.Errorf("deleting recovery codes: %w", err)
+	}
+	for _, hash := range codeHashes {
+		if err := queries.CreateRecoveryCode(ctx, authdb.CreateRecoveryCodeParams{
+			UserID:   userID,
+			CodeHash: hash,
+		}); err != nil {
+			return fmt.Errorf("creating recovery code: %w", err)
+		}
+	}
+	return nil
+}
+
+// createOIDCUser creates a user for an identity seen for the first time. The
+// provider verified the email address, so the user starts out verified.
+func (r *authRepository) createOIDCUser(ctx context.Context, params authdb.CreateUserParams, identity authdb.CreateUserIdentityParams) (authdb.User, error) {
+	var user authdb.User
+	err := r.withTx(ctx, func(queries *authdb.Queries) error {
+		var err error
+		if user, err = queries.CreateUser(ctx, params); err != nil {
+			return fmt.Errorf("creating user: %w", err)
+		}
+		if _, err := queries.VerifyUserEmail(ctx, authdb.VerifyUserEmailParams{
+			ID:    user.ID,
+			Email: user.Email,
+		}); err != nil {
+			return fmt.Errorf("verifying email: %w", err)
+		}
+		user.EmailVerified = true
+
+		identity.UserID = user.ID
+		if err := queries.CreateUserIdentity(ctx, identity); err != nil {
+			return fmt.Errorf("linking identity: %w", err)
+		}
+		return nil
+	})
+	return user, err
+}
+
+// changePassword sets the password of the user and keeps them signed in only
+// in the session with currentSessionID. Outstanding reset links are dropped
+// too.
+func (r *authRepository) changePassword(ctx context.Context, userID, currentSessionID, passwordHash string) (authdb.User, error) {
+	var user authdb.User
+	err := r.withTx(ctx, func(queries *authdb.Queries) error {
+		if err := queries.UpdateUserPassword(ctx, authdb.UpdateUserPasswordParams{
+			PasswordHash: passwordHash,
+			ID:           userID,
+		}); err != nil {
+			return fmt.Errorf("updating password: %w", err)
+		}
+		if err := queries.DeleteUserPasswordResetTokens(ctx, userID); err != nil {
+			return fmt.Errorf("deleting reset tokens: %w", err)
+		}
+		if _, err := queries.DeleteOtherUserSessions(ctx, authdb.DeleteOtherUserSessionsParams{
+			UserID: sql.NullString{String: userID, Valid: true},
+			ID:     currentSessionID,
+		}); err != nil {
+			return fmt.Errorf("deleting sessions: %w", err)
+		}
+		var err error
+		if user, err = queries.GetUser(ctx, userID); err != nil {
+			return fmt.Errorf("getting user: %w", err)
+		}
+		return nil
+	})
+	if err == nil {
+		r.users.Invalidate(userID)
+	}
+	return user, err
+}
+
+// deleteUser deletes the user along with everything the auth feature keeps
+// for them, which signs them out everywhere.
+func (r *authRepository) deleteUser(ctx context.Context, userID string) error {
+	err := r.withTx(ctx, func(queries *authdb.Queries) error {
+		if err := queries.DeleteUserSessions(ctx, sql.NullString{String: userID, Valid: true}); err != nil {
+			return fmt.Errorf("deleting sessions: %w", err)
+		}
+		if err := queries.DeleteUserPasswordResetTokens(ctx, userID); err != nil {
+			return fmt.Errorf("deleting reset tokens: %w", err)
+		}
+		if err := queries.DeleteUserTOTP(ctx, userID); err != nil {
+			return fmt.Errorf("deleting totp: %w", err)
+		}
+		if err := queries.DeleteRecoveryCodes(ctx, userID); err != nil {
+			return fmt.Errorf("deleting recovery codes: %w", err)
+		}
+		if err := queries.DeleteUserIdentities(ctx, userID); err != nil {
+			return fmt.Errorf("deleting identities: %w", err)
+		}
+		if err := queries.DeleteUserWebAuthnCredentials(ctx, userID); err != nil {
+			return fmt.Errorf("deleting passkeys: %w", err)
+		}
+		if err := queries.DeleteUser(ctx, userID); err != nil {
+			return fmt.Errorf("deleting user: %w", err)
+		}
+		return nil
+	})
+	if err == nil {
+		r.users.Invalidate(userID)
+	}
+	return err
+}
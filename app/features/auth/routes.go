@@ -1,27 +1,91 @@
 package auth
 
 import (
+	"context"
 	"database/sql"
+	"fmt"
 
 	"northstar/app/features/auth/gen/authdb"
+	"northstar/app/features/auth/web"
 	"northstar/app/middleware"
+	"northstar/app/static"
+	"northstar/config"
+	"northstar/mail"
 
+	"github.com/delaneyj/toolbelt/embeddednats"
 	"github.com/go-chi/chi/v5"
 	"github.com/gorilla/sessions"
+	"github.com/nats-io/nats.go/jetstream"
 )
 
-func SetupRoutes(router chi.Router, db *sql.DB, store sessions.Store) error {
+// UserDataDeleter deletes what another feature keeps for a user, when the user
+// deletes their account.
+type UserDataDeleter interface {
+	DeleteUserData(ctx context.Context, userID string) error
+}
+
+func SetupRoutes(router chi.Router, db *sql.DB, store sessions.Store, users *middleware.UserCache, mailer mail.Mailer, ns *embeddednats.Server, userData ...UserDataDeleter) error {
+	nc, err := ns.Client()
+	if err != nil {
+		return fmt.Errorf("error creating nats client: %w", err)
+	}
+
+	js, err := jetstream.New(nc)
+	if err != nil {
+		return fmt.Errorf("error creating jetstream client: %w", err)
+	}
+
+	limiter, err := newLoginLimiter(context.Background(), js)
+	if err != nil {
+		return err
+	}
+
+	secrets, err := newSecretBox(config.Global.EncryptionKey)
+	if err != nil {
+		return fmt.Errorf("error setting up encryption: %w", err)
+	}
+
+	webAuthn, err := newWebAuthn()
+	if err != nil {
+		return fmt.Errorf("error setting up passkeys: %w", err)
+	}
+
 	queries := authdb.New(db)
-	authRepository := &authRepository{queries: queries}
+	authRepository := &authRepository{db: db, queries: queries, users: users}
 	authHandlers := &authHandlers{
 		repository: authRepository,
 		store:      store,
+		mailer:     mailer,
+		tokens:     tokenSigner{key: []byte(config.Global.SessionSecret)},
+		limiter:    limiter,
+		secrets:    secrets,
+		webauthn:   webAuthn,
+		userData:   userData,
 	}
 
+	if config.Global.OIDCIssuer != "" {
+		authHandlers.oidc = &oidcProvider{
+			Name:         config.Global.OIDCProviderName,
+			issuer:       config.Global.OIDCIssuer,
+			clientID:     config.Global.OIDCClientID,
+			clientSecret: config.Global.OIDCClientSecret,
+		}
+	}
+
+	router.Handle("/auth/static/*", static.Handler("/auth/static", web.StaticDirectory, "auth"))
+
 	router.Route("/login", func(r chi.Router) {
 		r.Use(middleware.RedirectIfAuthenticated(store))
 		r.Get("/", authHandlers.handleLoginPage)
 		r.Post("/", authHandlers.handleLogin)
+		r.Get("/2fa", authHandlers.handleTwoFactorPage)
+		r.Post("/2fa", authHandlers.handleTwoFactor)
+		r.Post("/passkey/begin", authHandlers.handleBeginPasskeyLogin)
+		r.Post("/passkey/finish", authHandlers.handleFinishPasskeyLogin)
+		if authHandlers.oidc != nil {
+			r.Get("/oidc", authHandlers.handleOIDCLogin)
+			r.Get("/oidc/callback", authHandlers.handleOIDCCallback)
+		}
 	})
 
 	router.Route("/signup", func(r chi.Router) {
@@ -30,14 +94,48 @@ func SetupRoutes(router chi.Router, db *sql.DB, store sessions.Store) error {
 		r.Post("/", authHandlers.handleSignup)
 	})
 
+	router.Route("/forgot-password", func(r chi.Router) {
+		r.Use(middleware.RedirectIfAuthenticated(store))
+		r.Get("/", authHandlers.handleForgotPasswordPage)
+		r.Post("/", authHandlers.handleForgotPassword)
+	})
+
+	router.Route("/reset-password/{token}", func(r chi.Router) {
+		r.Get("/", authHandlers.handleResetPasswordPage)
+		r.Post("/", authHandlers.handleResetPassword)
+	})
+
 	router.Route("/logout", func(r chi.Router) {
-		r.Use(middleware.RequireAuth(store, db))
+		r.Use(middleware.RequireAuthAllowUnverified(store, db))
 		r.Post("/", authHandlers.handleLogout)
 	})
 
+	router.Route("/verify-email", func(r chi.Router) {
+		r.Get("/{token}", authHandlers.handleVerifyEmail)
+		r.Group(func(r chi.Router) {
+			r.Use(middleware.RequireAuthAllowUnverified(store, db))
+			r.Get("/", authHandlers.handleVerifyEmailPage)
+			r.Post("/", authHandlers.handleResendVerification)
+		})
+	})
+
 	router.Route("/profile", func(r chi.Router) {
 		r.Use(middleware.RequireAuth(store, db))
 		r.Get("/", authHandlers.handleProfilePage)
+		r.Post("/", authHandlers.handleUpdateProfile)
+		r.Post("/password", authHandlers.handleChangePassword)
+		r.Post("/password/link", authHandlers.handleSendSetPasswordLink)
+		r.Post("/delete", authHandlers.handleDeleteAccount)
+		r.Post("/sessions/revoke-others", authHandlers.handleRevokeOtherSessions)
+		r.Post("/sessions/{handle}/revoke", authHandlers.handleRevokeSession)
+		r.Post("/2fa/setup", authHandlers.handleTwoFactorSetup)
+		r.Post("/2fa/confirm", authHandlers.handleTwoFactorConfirm)
+		r.Post("/2fa/recovery-codes", authHandlers.handleRegenerateRecoveryCodes)
+		r.Post("/2fa/disable", authHandlers.handleTwoFactorDisable)
+		r.Get("/passkeys", authHandlers.handlePasskeys)
+		r.Post("/passkeys/begin", authHandlers.handleBeginPasskeyRegistration)
+		r.Post("/passkeys/finish", authHandlers.handleFinishPasskeyRegistration)
+		r.Post("/passkeys/{id}/delete", authHandlers.handleDeletePasskey)
 	})
 
 	return nil
@@ -0,0 +1,55 @@
+package auth
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+)
+
+var ErrUndecryptable = errors.New("secret cannot be decrypted")
+
+// secretBox encrypts secrets before they are stored in the database, such as
+// TOTP secrets, with AES-GCM under a key derived from
+// config.Global.EncryptionKey.
+type secretBox struct {
+	aead cipher.AEAD
+}
+
+func newSecretBox(key string) (secretBox, error) {
+	sum := sha256.Sum256([]byte(key))
+	block, err := aes.NewCipher(sum[:])
+	if err != nil {
+		return secretBox{}, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return secretBox{}, err
+	}
+	return secretBox{aead: aead}, nil
+}
+
+// seal encrypts plaintext, returning the nonce and ciphertext base64 encoded.
+func (b secretBox) seal(plaintext string) (string, error) {
+	nonce := make([]byte, b.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := b.aead.Seal(nonce, nonce, []byte(plaintext), nil)
+	return base64.RawStdEncoding.EncodeToString(sealed), nil
+}
+
+func (b secretBox) open(sealed string) (string, error) {
+	raw, err := base64.RawStdEncoding.DecodeString(sealed)
+	if err != nil || len(raw) < b.aead.NonceSize() {
+		return "", ErrUndecryptable
+	}
+	nonce, ciphertext := raw[:b.aead.NonceSize()], raw[b.aead.NonceSize():]
+	plaintext, err := b.aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return "", ErrUndecryptable
+	}
+	return string(plaintext), nil
+}
@@ -0,0 +1,173 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"northstar/app/features/auth/gen/authdb"
+	"northstar/app/features/auth/pages"
+	"northstar/app/middleware"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/starfederation/datastar-go/datastar"
+)
+
+// sessionHandle identifies a session on the profile page without putting its
+// ID, which the session cookie carries, into the page.
+func sessionHandle(id string) string {
+	sum := sha256.Sum256([]byte(id))
+	return hex.EncodeToString(sum[:8])
+}
+
+// currentSessionID returns the ID of the session the request was made with,
+// which is empty when the session store does not keep sessions server side.
+func (h *authHandlers) currentSessionID(r *http.Request) string {
+	session, err := h.store.Get(r, "auth-session")
+	if err != nil {
+		return ""
+	}
+	return session.ID
+}
+
+func (h *authHandlers) userSessions(r *http.Request, userID string) ([]pages.UserSession, error) {
+	rows, err := h.repository.queries.ListUserSessions(r.Context(), authdb.ListUserSessionsParams{
+		UserID:    sql.NullString{String: userID, Valid: true},
+		ExpiresAt: time.Now(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("listing sessions: %w", err)
+	}
+
+	current := h.currentSessionID(r)
+	sessions := make([]pages.UserSession, 0, len(rows))
+	for _, row := range rows {
+		sessions = append(sessions, pages.UserSession{
+			Handle:   sessionHandle(row.ID),
+			Device:   describeUserAgent(row.UserAgent),
+			IP:       row.IpAddress,
+			LastSeen: row.LastSeenAt,
+			Current:  row.ID == current,
+		})
+	}
+	return sessions, nil
+}
+
+// handleRevokeSession signs the user out of one of their other sessions.
+func (h *authHandlers) handleRevokeSession(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserIDFromContext(r.Context())
+	handle := chi.URLParam(r, "handle")
+
+	rows, err := h.repository.queries.ListUserSessions(r.Context(), authdb.ListUserSessionsParams{
+		UserID:    sql.NullString{String: userID, Valid: true},
+		ExpiresAt: time.Now(),
+	})
+	if err != nil {
+		slog.Error("Failed to list sessions", "user_id", userID, "error", err)
+		h.patchSessions(w, r, userID, MsgSessionRevokeFailed)
+		return
+	}
+
+	message := MsgSessionNotFound
+	current := h.currentSessionID(r)
+	for _, row := range rows {
+		if sessionHandle(row.ID) != handle {
+			continue
+		}
+		if row.ID == current {
+			message = MsgCannotRevokeCurrentSession
+			break
+		}
+		if _, err := h.repository.queries.DeleteUserSession(r.Context(), authdb.DeleteUserSessionParams{
+			ID:     row.ID,
+			UserID: row.UserID,
+		}); err != nil {
+			slog.Error("Failed to revoke session", "user_id", userID, "error", err)
+			message = MsgSessionRevokeFailed
+			break
+		}
+		message = MsgSessionRevoked
+		break
+	}
+
+	h.patchSessions(w, r, userID, message)
+}
+
+// handleRevokeOtherSessions signs the user out everywhere but here.
+func (h *authHandlers) handleRevokeOtherSessions(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserIDFromContext(r.Context())
+
+	message := MsgOtherSessionsRevoked
+	if _, err := h.repository.queries.DeleteOtherUserSessions(r.Context(), authdb.DeleteOtherUserSessionsParams{
+		UserID: sql.NullString{String: userID, Valid: true},
+		ID:     h.currentSessionID(r),
+	}); err != nil {
+		slog.Error("Failed to revoke other sessions", "user_id", userID, "error", err)
+		message = MsgSessionRevokeFailed
+	}
+
+	h.patchSessions(w, r, userID, message)
+}
+
+func (h *authHandlers) patchSessions(w http.ResponseWriter, r *http.Request, userID, message string) {
+	sessions, err := h.userSessions(r, userID)
+	if err != nil {
+		slog.Error("Failed to list sessions", "user_id", userID, "error", err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	sse := datastar.NewSSE(w, r)
+	if err := sse.PatchElementTempl(pages.ProfileSessions(sessions, message)); err != nil {
+		slog.Error("Failed to patch elements", "error", err)
+	}
+}
+
+// describeUserAgent turns a User-Agent header into something like "Firefox
+// on Linux". It only knows the common browsers and systems.
+func describeUserAgent(ua string) string {
+	if ua == "" {
+		return "Unknown device"
+	}
+
+	browser := "Unknown browser"
+	for _, b := range []struct{ token, name string }{
+		{"Edg/", "Edge"},
+		{"OPR/", "Opera"},
+		{"Firefox/", "Firefox"},
+		{"Chrome/", "Chrome"},
+		{"Safari/", "Safari"},
+		{"curl/", "curl"},
+	} {
+		if strings.Contains(ua, b.token) {
+			browser = b.name
+			break
+		}
+	}
+
+	system := ""
+	for _, s := range []struct{ token, name string }{
+		{"Android", "Android"},
+		{"iPhone", "iOS"},
+		{"iPad", "iPadOS"},
+		{"Windows", "Windows"},
+		{"Mac OS X", "macOS"},
+		{"CrOS", "ChromeOS"},
+		{"Linux", "Linux"},
+	} {
+		if strings.Contains(ua, s.token) {
+			system = s.name
+			break
+		}
+	}
+
+	if system == "" {
+		return browser
+	}
+	return browser + " on " + system
+}
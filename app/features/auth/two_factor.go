@@ -0,0 +1,473 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"database/sql"
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image/png"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"northstar/app/features/auth/gen/authdb"
+	"northstar/app/features/auth/pages"
+	"northstar/app/middleware"
+
+	"github.com/pquerna/otp"
+	"github.com/pquerna/otp/totp"
+	"github.com/starfederation/datastar-go/datastar"
+)
+
+const (
+	totpIssuer        = "Northstar"
+	totpPeriod        = 30
+	recoveryCodeCount = 10
+	// twoFactorTTL is how long a user has to enter their code after their
+	// password.
+	twoFactorTTL = 5 * time.Minute
+)
+
+// Session values for a login waiting for its second step.
+const (
+	pendingUserIDKey = "pending_user_id"
+	pendingUntilKey  = "pending_until"
+)
+
+// totpKey describes the secret of an account in the format authenticator
+// apps read from QR codes.
+func totpKey(secret, accountName string) (*otp.Key, error) {
+	u := url.URL{
+		Scheme: "otpauth",
+		Host:   "totp",
+		Path:   "/" + totpIssuer + ":" + accountName,
+		RawQuery: url.Values{
+			"secret": {secret},
+			"issuer": {totpIssuer},
+		}.Encode(),
+	}
+	return otp.NewKeyFromURL(u.String())
+}
+
+func totpQRCode(key *otp.Key) (string, error) {
+	img, err := key.Image(200, 200)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return "", err
+	}
+	return "data:image/png;base64," + base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+// checkTOTP reports whether code is the user's current TOTP code, allowing
+// for one period of clock drift either way. Each code only works once.
+func (h *authHandlers) checkTOTP(ctx context.Context, row authdb.UserTotp, code string) (bool, error) {
+	secret, err := h.secrets.open(row.Secret)
+	if err != nil {
+		return false, err
+	}
+
+	now := time.Now()
+	for _, skew := range []int{0, -1, 1} {
+		at := now.Add(time.Duration(skew*totpPeriod) * time.Second)
+		expected, err := totp.GenerateCode(secret, at)
+		if err != nil {
+			return false, err
+		}
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(code)) != 1 {
+			continue
+		}
+		step := at.Unix() / totpPeriod
+		rows, err := h.repository.queries.UseTOTPStep(ctx, authdb.UseTOTPStepParams{
+			LastUsedStep:   step,
+			UserID:         row.UserID,
+			LastUsedStep_2: step,
+		})
+		if err != nil {
+			return false, fmt.Errorf("recording totp step: %w", err)
+		}
+		return rows > 0, nil
+	}
+	return false, nil
+}
+
+// newRecoveryCodes returns recovery codes to show the user once, and the
+// hashes to store in their place.
+func newRecoveryCodes() (codes, hashes []string, err error) {
+	enc := base32.StdEncoding.WithPadding(base32.NoPadding)
+	for range recoveryCodeCount {
+		b := make([]byte, 7)
+		if _, err := rand.Read(b); err != nil {
+			return nil, nil, err
+		}
+		code := strings.ToLower(enc.EncodeToString(b))[:10]
+		codes = append(codes, code[:5]+"-"+code[5:])
+		hashes = append(hashes, hashRecoveryCode(code))
+	}
+	return codes, hashes, nil
+}
+
+// hashRecoveryCode ignores case, spaces and dashes, so codes can be typed
+// back however they were copied down.
+func hashRecoveryCode(code string) string {
+	code = strings.NewReplacer("-", "", " ", "").Replace(strings.ToLower(code))
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
+
+func isTOTPCode(code string) bool {
+	if len(code) != 6 {
+		return false
+	}
+	for _, c := range code {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// checkSecondFactor reports whether code is the user's current TOTP code or
+// one of their unused recovery codes, using it up.
+func (h *authHandlers) checkSecondFactor(ctx context.Context, row authdb.UserTotp, code string) (bool, error) {
+	code = strings.TrimSpace(code)
+	if isTOTPCode(code) {
+		return h.checkTOTP(ctx, row, code)
+	}
+	rows, err := h.repository.queries.UseRecoveryCode(ctx, authdb.UseRecoveryCodeParams{
+		UserID:   row.UserID,
+		CodeHash: hashRecoveryCode(code),
+	})
+	if err != nil {
+		return false, fmt.Errorf("using recovery code: %w", err)
+	}
+	return rows > 0, nil
+}
+
+// enrolledTOTP returns the user's confirmed TOTP enrollment, if they have
+// one.
+func (h *authHandlers) enrolledTOTP(ctx context.Context, userID string) (authdb.UserTotp, bool, error) {
+	row, err := h.repository.queries.GetUserTOTP(ctx, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return row, false, nil
+	}
+	if err != nil {
+		return row, false, fmt.Errorf("getting totp: %w", err)
+	}
+	return row, row.Confirmed, nil
+}
+
+// twoFactorStatus describes the user's enrollment for the profile page.
+func (h *authHandlers) twoFactorStatus(ctx context.Context, user *authdb.User) (pages.TwoFactorStatus, error) {
+	var status pages.TwoFactorStatus
+	row, err := h.repository.queries.GetUserTOTP(ctx, user.ID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return status, nil
+	}
+	if err != nil {
+		return status, fmt.Errorf("getting totp: %w", err)
+	}
+
+	if row.Confirmed {
+		status.Enabled = true
+		status.RecoveryCodesLeft, err = h.repository.queries.CountUnusedRecoveryCodes(ctx, user.ID)
+		if err != nil {
+			return status, fmt.Errorf("counting recovery codes: %w", err)
+		}
+		return status, nil
+	}
+
+	secret, err := h.secrets.open(row.Secret)
+	if err != nil {
+		return status, err
+	}
+	key, err := totpKey(secret, user.Email)
+	if err != nil {
+		return status, err
+	}
+	qrCode, err := totpQRCode(key)
+	if err != nil {
+		return status, err
+	}
+	status.Setup = &pages.TwoFactorSetup{QRCode: qrCode, Secret: secret}
+	return status, nil
+}
+
+// patchTwoFactor replaces the two-factor section of the profile page, showing
+// message and, right after they were generated, recoveryCodes.
+func (h *authHandlers) patchTwoFactor(w http.ResponseWriter, r *http.Request, user *authdb.User, message string, recoveryCodes []string) {
+	status, err := h.twoFactorStatus(r.Context(), user)
+	if err != nil {
+		slog.Error("Failed to get two-factor status", "user_id", user.ID, "error", err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	status.Message = message
+	status.RecoveryCodes = recoveryCodes
+
+	sse := datastar.NewSSE(w, r)
+	if err := sse.PatchElementTempl(pages.TwoFactorSection(status)); err != nil {
+		slog.Error("Failed to patch elements", "error", err)
+	}
+}
+
+// handleTwoFactorSetup starts enrolling an authenticator app, which only
+// takes effect once handleTwoFactorConfirm sees a code from it.
+func (h *authHandlers) handleTwoFactorSetup(w http.ResponseWriter, r *http.Request) {
+	user, _ := middleware.GetUserFromContext(r.Context())
+
+	if _, enrolled, err := h.enrolledTOTP(r.Context(), user.ID); err != nil {
+		slog.Error("Failed to get totp", "user_id", user.ID, "error", err)
+		h.patchTwoFactor(w, r, &user, MsgTwoFactorFailed, nil)
+		return
+	} else if enrolled {
+		h.patchTwoFactor(w, r, &user, MsgTwoFactorAlreadyEnabled, nil)
+		return
+	}
+
+	key, err := totp.Generate(totp.GenerateOpts{Issuer: totpIssuer, AccountName: user.Email})
+	if err != nil {
+		slog.Error("Failed to generate totp secret", "error", err)
+		h.patchTwoFactor(w, r, &user, MsgTwoFactorFailed, nil)
+		return
+	}
+	sealed, err := h.secrets.seal(key.Secret())
+	if err != nil {
+		slog.Error("Failed to encrypt totp secret", "error", err)
+		h.patchTwoFactor(w, r, &user, MsgTwoFactorFailed, nil)
+		return
+	}
+	if err := h.repository.queries.SaveUserTOTP(r.Context(), authdb.SaveUserTOTPParams{
+		UserID: user.ID,
+		Secret: sealed,
+	}); err != nil {
+		slog.Error("Failed to save totp secret", "user_id", user.ID, "error", err)
+		h.patchTwoFactor(w, r, &user, MsgTwoFactorFailed, nil)
+		return
+	}
+
+	h.patchTwoFactor(w, r, &user, "", nil)
+}
+
+func (h *authHandlers) handleTwoFactorConfirm(w http.ResponseWriter, r *http.Request) {
+	user, _ := middleware.GetUserFromContext(r.Context())
+
+	row, err := h.repository.queries.GetUserTOTP(r.Context(), user.ID)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && row.Confirmed) {
+		h.patchTwoFactor(w, r, &user, "", nil)
+		return
+	}
+	if err != nil {
+		slog.Error("Failed to get totp", "user_id", user.ID, "error", err)
+		h.patchTwoFactor(w, r, &user, MsgTwoFactorFailed, nil)
+		return
+	}
+
+	code := strings.TrimSpace(r.FormValue("code"))
+	ok, err := h.checkTOTP(r.Context(), row, code)
+	if err != nil {
+		slog.Error("Failed to check totp code", "user_id", user.ID, "error", err)
+		h.patchTwoFactor(w, r, &user, MsgTwoFactorFailed, nil)
+		return
+	}
+	if !ok {
+		h.patchTwoFactor(w, r, &user, MsgInvalidTwoFactorCode, nil)
+		return
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+	if err == nil {
+		err = h.repository.enableTOTP(r.Context(), user.ID, hashes)
+	}
+	if err != nil {
+		slog.Error("Failed to enable totp", "user_id", user.ID, "error", err)
+		h.patchTwoFactor(w, r, &user, MsgTwoFactorFailed, nil)
+		return
+	}
+	slog.Info("Two-factor authentication enabled", "user_id", user.ID)
+
+	h.patchTwoFactor(w, r, &user, MsgTwoFactorEnabled, codes)
+}
+
+func (h *authHandlers) handleRegenerateRecoveryCodes(w http.ResponseWriter, r *http.Request) {
+	user, _ := middleware.GetUserFromContext(r.Context())
+
+	_, enrolled, err := h.enrolledTOTP(r.Context(), user.ID)
+	if err != nil || !enrolled {
+		if err != nil {
+			slog.Error("Failed to get totp", "user_id", user.ID, "error", err)
+		}
+		h.patchTwoFactor(w, r, &user, MsgTwoFactorFailed, nil)
+		return
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+	if err == nil {
+		err = h.repository.replaceRecoveryCodes(r.Context(), user.ID, hashes)
+	}
+	if err != nil {
+		slog.Error("Failed to replace recovery codes", "user_id", user.ID, "error", err)
+		h.patchTwoFactor(w, r, &user, MsgTwoFactorFailed, nil)
+		return
+	}
+
+	h.patchTwoFactor(w, r, &user, MsgRecoveryCodesReplaced, codes)
+}
+
+// handleTwoFactorDisable turns two-factor authentication off, which takes a
+// code so a session left open somewhere cannot do it alone.
+func (h *authHandlers) handleTwoFactorDisable(w http.ResponseWriter, r *http.Request) {
+	user, _ := middleware.GetUserFromContext(r.Context())
+
+	row, enrolled, err := h.enrolledTOTP(r.Context(), user.ID)
+	if err != nil || !enrolled {
+		if err != nil {
+			slog.Error("Failed to get totp", "user_id", user.ID, "error", err)
+		}
+		h.patchTwoFactor(w, r, &user, MsgTwoFactorFailed, nil)
+		return
+	}
+
+	ok, err := h.checkSecondFactor(r.Context(), row, r.FormValue("code"))
+	if err != nil {
+		slog.Error("Failed to check second factor", "user_id", user.ID, "error", err)
+		h.patchTwoFactor(w, r, &user, MsgTwoFactorFailed, nil)
+		return
+	}
+	if !ok {
+		h.patchTwoFactor(w, r, &user, MsgInvalidTwoFactorCode, nil)
+		return
+	}
+
+	if err := h.repository.disableTOTP(r.Context(), user.ID); err != nil {
+		slog.Error("Failed to disable totp", "user_id", user.ID, "error", err)
+		h.patchTwoFactor(w, r, &user, MsgTwoFactorFailed, nil)
+		return
+	}
+	slog.Info("Two-factor authentication disabled", "user_id", user.ID)
+
+	h.patchTwoFactor(w, r, &user, MsgTwoFactorDisabled, nil)
+}
+
+// startTwoFactor remembers that user got their password right and sends them
+// on to enter their code. They are not logged in until they do.
+func (h *authHandlers) startTwoFactor(w http.ResponseWriter, r *http.Request, user *authdb.User) error {
+	if err := h.beginTwoFactor(w, r, user); err != nil {
+		return err
+	}
+
+	sse := datastar.NewSSE(w, r)
+	if err := sse.ExecuteScript("window.location.href = '/login/2fa'"); err != nil {
+		slog.Error("Failed to execute script", "error", err)
+	}
+	return nil
+}
+
+// beginTwoFactor is startTwoFactor for plain requests, whose callers
+// redirect to /login/2fa themselves.
+func (h *authHandlers) beginTwoFactor(w http.ResponseWriter, r *http.Request, user *authdb.User) error {
+	session, err := h.store.Get(r, "auth-session")
+	if err != nil {
+		return fmt.Errorf("getting session: %w", err)
+	}
+
+	session.Values[pendingUserIDKey] = user.ID
+	session.Values[pendingUntilKey] = time.Now().Add(twoFactorTTL).Unix()
+	if err := session.Save(r, w); err != nil {
+		return fmt.Errorf("saving session: %w", err)
+	}
+	return nil
+}
+
+// pendingTwoFactorUser returns the user who is part way through logging in.
+func (h *authHandlers) pendingTwoFactorUser(r *http.Request) (authdb.User, bool) {
+	session, err := h.store.Get(r, "auth-session")
+	if err != nil {
+		return authdb.User{}, false
+	}
+	userID, _ := session.Values[pendingUserIDKey].(string)
+	until, _ := session.Values[pendingUntilKey].(int64)
+	if userID == "" || time.Now().Unix() > until {
+		return authdb.User{}, false
+	}
+
+	user, err := h.repository.queries.GetUser(r.Context(), userID)
+	if err != nil {
+		return authdb.User{}, false
+	}
+	return user, true
+}
+
+func (h *authHandlers) handleTwoFactorPage(w http.ResponseWriter, r *http.Request) {
+	if _, ok := h.pendingTwoFactorUser(r); !ok {
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+		return
+	}
+
+	if err := pages.TwoFactorPage().Render(r.Context(), w); err != nil {
+		slog.Error("Failed to render two-factor page", "error", err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+	}
+}
+
+// handleTwoFactor finishes logging in with an authenticator or recovery code.
+// Wrong codes count against the same limits as wrong passwords.
+func (h *authHandlers) handleTwoFactor(w http.ResponseWriter, r *http.Request) {
+	user, ok := h.pendingTwoFactorUser(r)
+	if !ok {
+		h.sendGenericError(w, r, MsgTwoFactorExpired)
+		return
+	}
+
+	now := time.Now()
+	accountKey := accountLimitKey(user.Email)
+	lockedUntil, err := h.limiter.lockedUntil(r.Context(), now, accountKey)
+	if err != nil {
+		slog.Error("Error checking login attempts", "error", err)
+	} else if !lockedUntil.IsZero() {
+		h.sendGenericError(w, r, MsgTooManyLoginAttempts)
+		return
+	}
+
+	row, enrolled, err := h.enrolledTOTP(r.Context(), user.ID)
+	if err != nil {
+		slog.Error("Failed to get totp", "user_id", user.ID, "error", err)
+		h.sendGenericError(w, r, MsgLoginFailed)
+		return
+	}
+	if !enrolled {
+		// two-factor authentication was turned off since the password step
+		h.sendGenericError(w, r, MsgTwoFactorExpired)
+		return
+	}
+
+	ok, err = h.checkSecondFactor(r.Context(), row, r.FormValue("code"))
+	if err != nil {
+		slog.Error("Failed to check second factor", "user_id", user.ID, "error", err)
+		h.sendGenericError(w, r, MsgLoginFailed)
+		return
+	}
+	if !ok {
+		if err := h.limiter.fail(r.Context(), now, accountKey, maxAccountFailures); err != nil {
+			slog.Error("Error recording failed login", "error", err)
+		}
+		sse := datastar.NewSSE(w, r)
+		if err := sse.PatchElementTempl(pages.CodeError(MsgInvalidTwoFactorCode)); err != nil {
+			slog.Error("Failed to patch elements", "error", err)
+		}
+		return
+	}
+
+	h.completeLogin(w, r, &user)
+}
@@ -0,0 +1,119 @@
+package auth
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"northstar/app/features/auth/gen/authdb"
+	"northstar/app/features/auth/pages"
+	"northstar/app/middleware"
+	"northstar/config"
+	"northstar/mail"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/starfederation/datastar-go/datastar"
+)
+
+// verificationTTL is how long the link in a verification email works.
+const verificationTTL = 48 * time.Hour
+
+// sendVerificationEmail mails user a link proving they own their address. The
+// token is bound to the address, so changing it voids earlier links.
+func (h *authHandlers) sendVerificationEmail(r *http.Request, user *authdb.User) error {
+	token, err := h.tokens.sign(tokenPurposeVerifyEmail, verificationTTL, user.ID, user.Email)
+	if err != nil {
+		return fmt.Errorf("signing verification token: %w", err)
+	}
+
+	link := absoluteURL("/verify-email/" + token)
+	return h.mailer.Send(r.Context(), mail.Message{
+		To:      user.Email,
+		Subject: "Verify your email address",
+		Body: fmt.Sprintf("Hi %s,\n\nFollow this link to verify your email address:\n\n%s\n\nThe link expires in %d hours. If you did not sign up, you can ignore this email.\n",
+			user.Username, link, int(verificationTTL.Hours())),
+	})
+}
+
+func (h *authHandlers) handleVerifyEmailPage(w http.ResponseWriter, r *http.Request) {
+	user, _ := middleware.GetUserFromContext(r.Context())
+	if err := pages.VerifyEmailPage(&user).Render(r.Context(), w); err != nil {
+		slog.Error("Failed to render verify email page", "error", err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+	}
+}
+
+func (h *authHandlers) handleResendVerification(w http.ResponseWriter, r *http.Request) {
+	user, _ := middleware.GetUserFromContext(r.Context())
+
+	message := MsgVerificationSent
+	if user.EmailVerified {
+		message = MsgEmailAlreadyVerified
+	} else if err := h.sendVerificationEmail(r, &user); err != nil {
+		slog.Error("Failed to send verification email", "user_id", user.ID, "error", err)
+		message = MsgVerificationFailed
+	}
+
+	sse := datastar.NewSSE(w, r)
+	if err := sse.PatchElementTempl(pages.VerifyEmailStatus(message)); err != nil {
+		slog.Error("Failed to patch elements", "error", err)
+	}
+}
+
+// handleVerifyEmail marks the address in the token verified. It does not need
+// a session, since the link may be opened in another browser.
+func (h *authHandlers) handleVerifyEmail(w http.ResponseWriter, r *http.Request) {
+	verified, message := false, MsgInvalidVerificationLink
+
+	fields, err := h.tokens.verify(tokenPurposeVerifyEmail, chi.URLParam(r, "token"))
+	switch {
+	case errors.Is(err, ErrExpiredToken):
+		message = MsgExpiredVerificationLink
+	case err != nil || len(fields) != 2:
+	default:
+		// links can be opened again, but only the first one verifying the
+		// address may make the user an admin
+		user, err := h.repository.queries.GetUser(r.Context(), fields[0])
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			slog.Error("Failed to get user", "user_id", fields[0], "error", err)
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+		rows, err := h.repository.queries.VerifyUserEmail(r.Context(), authdb.VerifyUserEmailParams{
+			ID:    fields[0],
+			Email: fields[1],
+		})
+		if err != nil {
+			slog.Error("Failed to verify email", "user_id", fields[0], "error", err)
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+		if rows > 0 {
+			h.repository.users.Invalidate(fields[0])
+			verified, message = true, MsgEmailVerified
+			if !user.EmailVerified {
+				user.Email, user.EmailVerified = fields[1], true
+				if err := h.promoteAdmin(r.Context(), &user); err != nil {
+					slog.Error("Error promoting admin", "user_id", fields[0], "error", err)
+				}
+			}
+		}
+	}
+
+	if !verified {
+		w.WriteHeader(http.StatusBadRequest)
+	}
+	if err := pages.VerifyEmailResultPage(verified, message).Render(r.Context(), w); err != nil {
+		slog.Error("Failed to render verify email result page", "error", err)
+	}
+}
+
+// absoluteURL turns path into a link that works outside the app, such as in
+// an email. Links are only ever built from config.Global.BaseURL, never from
+// the request, so a forged Host header cannot redirect them.
+func absoluteURL(path string) string {
+	return config.Global.BaseURL + path
+}
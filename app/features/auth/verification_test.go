@@ -0,0 +1,73 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"northstar/app/features/auth/gen/authdb"
+	"northstar/app/middleware"
+	"northstar/config"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func TestVerifyEmailPromotesAdminOnce(t *testing.T) {
+	const email, password = "admin@example.com", "correct horse"
+	prev := config.Global.AdminEmails
+	config.Global.AdminEmails = []string{email}
+	t.Cleanup(func() { config.Global.AdminEmails = prev })
+
+	h := newTestAuthHandlers(t)
+	h.tokens = tokenSigner{key: []byte("test")}
+	user := createTestUser(t, h, email, password)
+	token, err := h.tokens.sign(tokenPurposeVerifyEmail, verificationTTL, user.ID, user.Email)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verify := func() {
+		t.Helper()
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("token", token)
+		r := httptest.NewRequestWithContext(context.WithValue(t.Context(), chi.RouteCtxKey, rctx), http.MethodGet, "/verify-email/"+token, nil)
+		w := httptest.NewRecorder()
+		h.handleVerifyEmail(w, r)
+		if w.Code != http.StatusOK {
+			t.Fatalf("verify: status %d, want %d", w.Code, http.StatusOK)
+		}
+	}
+	role := func() string {
+		t.Helper()
+		user, err := h.repository.queries.GetUser(t.Context(), user.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return user.Role
+	}
+
+	verify()
+	if got := role(); got != middleware.RoleAdmin {
+		t.Fatalf("role %q after verifying, want %q", got, middleware.RoleAdmin)
+	}
+
+	if _, err := h.repository.queries.SetUserRole(t.Context(), authdb.SetUserRoleParams{
+		Role: middleware.RoleUser,
+		ID:   user.ID,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// neither opening the link again nor signing in undoes the demotion
+	verify()
+	form := url.Values{"email": {email}, "password": {password}}
+	r := httptest.NewRequestWithContext(t.Context(), http.MethodPost, "/login", strings.NewReader(form.Encode()))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	h.handleLogin(httptest.NewRecorder(), r)
+	if got := role(); got != middleware.RoleUser {
+		t.Errorf("role %q after demotion, want %q", got, middleware.RoleUser)
+	}
+}
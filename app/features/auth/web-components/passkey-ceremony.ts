@@ -0,0 +1,120 @@
+// Runs a WebAuthn ceremony against the server when its button is clicked:
+// fetches options from `begin`, asks the browser for a passkey and posts the
+// result to `finish`. Fires `passkey-success` with the server's answer, or
+// `passkey-error` with a message.
+
+type Mode = 'register' | 'login'
+
+interface Descriptor {
+  id: string
+  type: string
+  transports?: string[]
+}
+
+const fromBase64URL = (value: string): ArrayBuffer => {
+  const base64 = value.replace(/-/g, '+').replace(/_/g, '/')
+  const padded = base64.padEnd(Math.ceil(base64.length / 4) * 4, '=')
+  return Uint8Array.from(atob(padded), (c) => c.charCodeAt(0)).buffer
+}
+
+const toBase64URL = (buffer: ArrayBuffer): string =>
+  btoa(String.fromCharCode(...new Uint8Array(buffer)))
+    .replace(/\+/g, '-')
+    .replace(/\//g, '_')
+    .replace(/=+$/, '')
+
+const decodeDescriptors = (descriptors?: Descriptor[]) =>
+  descriptors?.map((d) => ({ ...d, id: fromBase64URL(d.id) }))
+
+// layouts.Base puts the CSRF token in a meta tag for requests made outside
+// Datastar.
+const csrfToken = () =>
+  document.querySelector<HTMLMetaElement>('meta[name="csrf-token"]')?.content ?? ''
+
+class PasskeyCeremony extends HTMLElement {
+  private busy = false
+
+  connectedCallback() {
+    this.addEventListener('click', this.onClick)
+  }
+
+  disconnectedCallback() {
+    this.removeEventListener('click', this.onClick)
+  }
+
+  private onClick = async (evt: Event) => {
+    if (this.busy || !(evt.target as Element).closest('button')) return
+    evt.preventDefault()
+
+    this.busy = true
+    try {
+      const mode = this.getAttribute('mode') as Mode
+      const detail = mode === 'register' ? await this.register() : await this.login()
+      this.dispatchEvent(new CustomEvent('passkey-success', { detail, bubbles: true }))
+    } catch (err) {
+      const message =
+        err instanceof DOMException && err.name === 'NotAllowedError'
+          ? 'The passkey request was cancelled'
+          : err instanceof Error
+            ? err.message
+            : String(err)
+      this.dispatchEvent(new CustomEvent('passkey-error', { detail: { message }, bubbles: true }))
+    } finally {
+      this.busy = false
+    }
+  }
+
+  private async post(url: string | null, body?: unknown) {
+    if (!url) throw new Error('passkey-ceremony is missing an endpoint')
+    const res = await fetch(url, {
+      method: 'POST',
+      headers: { 'Content-Type': 'application/json', 'X-CSRF-Token': csrfToken() },
+      body: body === undefined ? undefined : JSON.stringify(body),
+    })
+    const json = await res.json()
+    if (!res.ok) throw new Error(json.error ?? res.statusText)
+    return json
+  }
+
+  private async register() {
+    const { publicKey } = await this.post(this.getAttribute('begin'))
+    publicKey.challenge = fromBase64URL(publicKey.challenge)
+    publicKey.user.id = fromBase64URL(publicKey.user.id)
+    publicKey.excludeCredentials = decodeDescriptors(publicKey.excludeCredentials)
+
+    const credential = (await navigator.credentials.create({ publicKey })) as PublicKeyCredential
+    const response = credential.response as AuthenticatorAttestationResponse
+    return this.post(this.getAttribute('finish'), {
+      id: credential.id,
+      rawId: toBase64URL(credential.rawId),
+      type: credential.type,
+      response: {
+        clientDataJSON: toBase64URL(response.clientDataJSON),
+        attestationObject: toBase64URL(response.attestationObject),
+        transports: response.getTransports?.() ?? [],
+      },
+    })
+  }
+
+  private async login() {
+    const { publicKey } = await this.post(this.getAttribute('begin'))
+    publicKey.challenge = fromBase64URL(publicKey.challenge)
+    publicKey.allowCredentials = decodeDescriptors(publicKey.allowCredentials)
+
+    const credential = (await navigator.credentials.get({ publicKey })) as PublicKeyCredential
+    const response = credential.response as AuthenticatorAssertionResponse
+    return this.post(this.getAttribute('finish'), {
+      id: credential.id,
+      rawId: toBase64URL(credential.rawId),
+      type: credential.type,
+      response: {
+        clientDataJSON: toBase64URL(response.clientDataJSON),
+        authenticatorData: toBase64URL(response.authenticatorData),
+        signature: toBase64URL(response.signature),
+        userHandle: response.userHandle ? toBase64URL(response.userHandle) : null,
+      },
+    })
+  }
+}
+
+customElements.define('passkey-ceremony', PasskeyCeremony)
@@ -0,0 +1,18 @@
+package web
+
+import (
+	"embed"
+
+	"github.com/benbjohnson/hashfs"
+)
+
+//go:embed static
+var StaticDirectory embed.FS
+
+var (
+	StaticSys = hashfs.NewFS(StaticDirectory)
+)
+
+func StaticPath(path string) string {
+	return "/auth/" + StaticSys.HashName("static/"+path)
+}
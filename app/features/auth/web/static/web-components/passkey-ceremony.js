@@ -0,0 +1,2 @@
+var i=r=>{let a=r.replace(/-/g,"+").replace(/_/g,"/"),e=a.padEnd(Math.ceil(a.length/4)*4,"=");return Uint8Array.from(atob(e),t=>t.charCodeAt(0)).buffer},n=r=>btoa(String.fromCharCode(...new Uint8Array(r))).replace(/\+/g,"-").replace(/\//g,"_").replace(/=+$/,""),l=r=>r?.map(a=>({...a,id:i(a.id)})),d=()=>document.querySelector('meta[name="csrf-token"]')?.content??"",o=class extends HTMLElement{constructor(){super(...arguments);this.busy=!1;this.onClick=async e=>{if(!(this.busy||!e.target.closest("button"))){e.preventDefault(),this.busy=!0;try{let s=this.getAttribute("mode")==="register"?await this.register():await this.login();this.dispatchEvent(new CustomEvent("passkey-success",{detail:s,bubbles:!0}))}catch(t){let s=t instanceof DOMException&&t.name==="NotAllowedError"?"The passkey request was cancelled":t instanceof Error?t.message:String(t);this.dispatchEvent(new CustomEvent("passkey-error",{detail:{message:s},bubbles:!0}))}finally{this.busy=!1}}}}connectedCallback(){this.addEventListener("click",this.onClick)}disconnectedCallback(){this.removeEventListener("click",this.onClick)}async post(e,t){if(!e)throw new Error("passkey-ceremony is missing an endpoint");let s=await fetch(e,{method:"POST",headers:{"Content-Type":"application/json","X-CSRF-Token":d()},body:t===void 0?void 0:JSON.stringify(t)}),c=await s.json();if(!s.ok)throw new Error(c.error??s.statusText);return c}async register(){let{publicKey:e}=await this.post(this.getAttribute("begin"));e.challenge=i(e.challenge),e.user.id=i(e.user.id),e.excludeCredentials=l(e.excludeCredentials);let t=await navigator.credentials.create({publicKey:e}),s=t.response;return this.post(this.getAttribute("finish"),{id:t.id,rawId:n(t.rawId),type:t.type,response:{clientDataJSON:n(s.clientDataJSON),attestationObject:n(s.attestationObject),transports:s.getTransports?.()??[]}})}async login(){let{publicKey:e}=await this.post(this.getAttribute("begin"));e.challenge=i(e.challenge),e.allowCredentials=l(e.allowCredentials);let t=await navigator.credentials.get({publicKey:e}),s=t.response;return this.post(this.getAttribute("finish"),{id:t.id,rawId:n(t.rawId),type:t.type,response:{clientDataJSON:n(s.clientDataJSON),authenticatorData:n(s.authenticatorData),signature:n(s.signature),userHandle:s.userHandle?n(s.userHandle):null}})}};customElements.define("passkey-ceremony",o);
+//# sourceMappingURL=passkey-ceremony.js.map
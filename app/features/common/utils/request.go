@@ -0,0 +1,15 @@
+package utils
+
+import (
+	"net"
+	"net/http"
+)
+
+// ClientIP returns the address the request came from, without its port.
+func ClientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
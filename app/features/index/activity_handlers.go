@@ -0,0 +1,99 @@
+package index
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"slices"
+
+	"northstar/app/features/index/components"
+	"northstar/app/features/index/pages"
+	"northstar/app/features/index/services"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/starfederation/datastar-go/datastar"
+)
+
+func (h *Handlers) ActivityPage(w http.ResponseWriter, r *http.Request) {
+	if err := pages.ActivityPage("Your todos", "/", "/api/todos", false).Render(r.Context(), w); err != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+	}
+}
+
+func (h *Handlers) ListActivityPage(w http.ResponseWriter, r *http.Request) {
+	listID := chi.URLParam(r, "listID")
+	list, err := h.listService.GetList(r.Context(), listID)
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	if err := pages.ActivityPage(list.Name, "/lists/"+listID, "/api/lists/"+listID+"/todos", true).Render(r.Context(), w); err != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+	}
+}
+
+// ActivitySSE sends the recorded activity of the list in one go, then
+// prepends new activity as it is recorded.
+func (h *Handlers) ActivitySSE(w http.ResponseWriter, r *http.Request) {
+	messages, err := h.todoService.WatchActivity(w, r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer messages.Stop()
+
+	ctx := r.Context()
+	stop := context.AfterFunc(ctx, messages.Stop)
+	defer stop()
+
+	sse := datastar.NewSSE(w, r)
+	if err := sse.PatchElementTempl(components.ActivityList(nil)); err != nil {
+		slog.Error("Failed to patch activity", "error", err)
+		return
+	}
+
+	// backlog holds recorded activity until the last of it has arrived, so
+	// it can be rendered at once
+	var backlog []*components.Activity
+	shown := 0
+	for {
+		msg, err := messages.Next()
+		if errors.Is(err, jetstream.ErrMsgIteratorClosed) {
+			return
+		}
+		if err != nil {
+			slog.Error("Failed to read activity", "error", err)
+			return
+		}
+
+		activity, err := services.DecodeActivity(msg.Data())
+		if err != nil {
+			slog.Error("Failed to decode activity", "error", err)
+			continue
+		}
+		meta, err := msg.Metadata()
+		if err != nil {
+			slog.Error("Failed to read activity metadata", "error", err)
+			return
+		}
+
+		if shown > 0 {
+			err = sse.PatchElementTempl(
+				components.ActivityItem(activity),
+				datastar.WithSelectorID("activity-list"),
+				datastar.WithModePrepend(),
+			)
+		} else if backlog = append(backlog, activity); meta.NumPending == 0 {
+			slices.Reverse(backlog)
+			err = sse.PatchElementTempl(components.ActivityList(backlog))
+			shown, backlog = len(backlog), nil
+		}
+		if err != nil {
+			slog.Error("Failed to patch activity", "error", err)
+			return
+		}
+	}
+}
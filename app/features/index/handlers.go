@@ -1,25 +1,38 @@
 package index
 
 import (
-	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
 	"net/http"
+	"path/filepath"
+	"slices"
 	"strconv"
+	"strings"
+	"time"
 
 	"northstar/app/features/index/components"
 	"northstar/app/features/index/pages"
 	"northstar/app/features/index/services"
+	"northstar/app/middleware"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/nats-io/nats.go/jetstream"
 	"github.com/starfederation/datastar-go/datastar"
 )
 
+// maxImportSize caps the size of uploaded import files.
+const maxImportSize = 1 << 20
+
 type Handlers struct {
 	todoService *services.TodoService
+	listService *services.ListService
 }
 
-func NewHandlers(todoService *services.TodoService) *Handlers {
+func NewHandlers(todoService *services.TodoService, listService *services.ListService) *Handlers {
 	return &Handlers{
 		todoService: todoService,
+		listService: listService,
 	}
 }
 
@@ -36,7 +49,38 @@ func (h *Handlers) TodosSSE(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	view, err := h.listView(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// The page reopens this stream whenever the search, filter or todo being
+	// edited changes, so they apply to everything sent below.
+	type Store struct {
+		Query   string                  `json:"query"`
+		Mode    components.TodoViewMode `json:"mode"`
+		Tag     string                  `json:"tag"`
+		Editing string                  `json:"editing"`
+	}
+	store := &Store{}
+	if err := datastar.ReadSignals(r, store); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	view.Search = strings.TrimSpace(store.Query)
+	view.Mode, view.Tag = viewFilter(store.Mode, store.Tag)
+	if !view.ReadOnly {
+		view.EditingID = store.Editing
+	}
+
 	sse := datastar.NewSSE(w, r)
+	if err := sse.PatchElementTempl(components.TodosMVCView(mvc, view)); err != nil {
+		if err := sse.ConsoleError(err); err != nil {
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		}
+		return
+	}
 
 	// Watch for updates
 	ctx := r.Context()
@@ -47,134 +91,283 @@ func (h *Handlers) TodosSSE(w http.ResponseWriter, r *http.Request) {
 	}
 	defer watcher.Stop()
 
+	// Only shared lists announce who else has them open
+	var presenceUpdates <-chan jetstream.KeyValueEntry
+	presenceKey, self := "", ""
+	present := map[string]string{}
+	if chi.URLParam(r, "listID") != "" {
+		user, _ := middleware.GetUserFromContext(ctx)
+		key, leave, err := h.todoService.TrackPresence(ctx, sessionID, user.Username)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		defer leave()
+		presenceKey, self = key, user.Username
+
+		presenceWatcher, err := h.todoService.WatchPresence(ctx, sessionID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		defer presenceWatcher.Stop()
+		presenceUpdates = presenceWatcher.Updates()
+	}
+
 	for {
 		select {
 		case <-ctx.Done():
 			return
 		case entry := <-watcher.Updates():
-			if entry == nil {
+			if entry == nil || entry.Operation() != jetstream.KeyValuePut {
 				continue
 			}
-			if err := json.Unmarshal(entry.Value(), mvc); err != nil {
-				http.Error(w, err.Error(), http.StatusInternalServerError)
+			next, decodeErr := services.DecodeMVC(entry.Value())
+			if decodeErr != nil {
+				http.Error(w, decodeErr.Error(), http.StatusInternalServerError)
 				return
 			}
-			c := components.TodosMVCView(mvc)
-			if err := sse.PatchElementTempl(c); err != nil {
-				if err := sse.ConsoleError(err); err != nil {
-					http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
-				}
-				return
+			diff := services.DiffMVC(mvc, next, view, time.Now().Format(components.TodoDateLayout))
+			mvc = next
+			err = h.patchTodos(sse, diff, mvc, view)
+		case entry := <-presenceUpdates:
+			if entry == nil || entry.Key() == presenceKey {
+				continue
+			}
+			if entry.Operation() == jetstream.KeyValuePut {
+				present[entry.Key()] = string(entry.Value())
+			} else {
+				delete(present, entry.Key())
+			}
+			names := presentNames(present, self)
+			if slices.Equal(names, view.Present) {
+				continue
+			}
+			view.Present = names
+			err = sse.PatchElementTempl(components.TodoPresence(view))
+		}
+
+		if err != nil {
+			if err := sse.ConsoleError(err); err != nil {
+				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
 			}
+			return
 		}
 	}
 }
 
-func (h *Handlers) ResetTodos(w http.ResponseWriter, r *http.Request) {
-	sessionID, mvc, err := h.todoService.GetSessionMVC(w, r)
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
+// patchTodos sends the parts of the view touched by diff, so rows other
+// people are not working on are left alone.
+func (h *Handlers) patchTodos(sse *datastar.ServerSentEventGenerator, diff services.TodoMVCDiff, mvc *components.TodoMVC, view components.TodoListView) error {
+	if diff.Full {
+		return sse.PatchElementTempl(components.TodosMVCView(mvc, view))
 	}
 
-	h.todoService.ResetMVC(mvc)
-	if err := h.todoService.SaveMVC(r.Context(), sessionID, mvc); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
+	today := time.Now().Format(components.TodoDateLayout)
+	for _, id := range diff.Removed {
+		if err := sse.RemoveElementByID("todo" + id); err != nil {
+			return err
+		}
 	}
+	for _, todo := range diff.Changed {
+		if err := sse.PatchElementTempl(components.TodoRow(view, todo, today, true)); err != nil {
+			return err
+		}
+	}
+	for _, todo := range diff.Appended {
+		if err := sse.PatchElementTempl(
+			components.TodoRow(view, todo, today, true),
+			datastar.WithSelectorID("todo-list"),
+			datastar.WithModeAppend(),
+		); err != nil {
+			return err
+		}
+	}
+	if diff.Controls && !view.ReadOnly {
+		if err := sse.PatchElementTempl(components.TodoControls(mvc, view)); err != nil {
+			return err
+		}
+	}
+	if diff.Footer {
+		if err := sse.PatchElementTempl(components.TodoFooter(mvc, view)); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
-func (h *Handlers) CancelEdit(w http.ResponseWriter, r *http.Request) {
-	sessionID, mvc, err := h.todoService.GetSessionMVC(w, r)
-	sse := datastar.NewSSE(w, r)
-	if err != nil {
-		if err := sse.ConsoleError(err); err != nil {
-			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
-		}
-		return
+// viewFilter returns the filter picked by the mode and tag signals of a page,
+// or TodoViewModeAll when they do not make sense.
+func viewFilter(mode components.TodoViewMode, tag string) (components.TodoViewMode, string) {
+	switch {
+	case mode == components.TodoViewModeTag && tag != "":
+		return mode, tag
+	case mode >= components.TodoViewModeAll && mode < components.TodoViewModeLast:
+		return mode, ""
+	default:
+		return components.TodoViewModeAll, ""
 	}
+}
 
-	h.todoService.CancelEditing(mvc)
-	if err := h.todoService.SaveMVC(r.Context(), sessionID, mvc); err != nil {
-		if err := sse.ConsoleError(err); err != nil {
-			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
-		}
+// ChangeView records the filter the page just switched to. The page keeps the
+// filter itself, so this only adds to the activity of the list.
+func (h *Handlers) ChangeView(w http.ResponseWriter, r *http.Request) {
+	type Store struct {
+		Mode components.TodoViewMode `json:"mode"`
+		Tag  string                  `json:"tag"`
+	}
+	store := &Store{}
+	if err := datastar.ReadSignals(r, store); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
-}
 
-func (h *Handlers) SetMode(w http.ResponseWriter, r *http.Request) {
-	sessionID, mvc, err := h.todoService.GetSessionMVC(w, r)
-	if err != nil {
+	mode, tag := viewFilter(store.Mode, store.Tag)
+	if err := h.todoService.RecordView(w, r, mode, tag); err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
+}
 
-	modeStr := chi.URLParam(r, "mode")
-	modeRaw, err := strconv.Atoi(modeStr)
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+func (h *Handlers) ResetTodos(w http.ResponseWriter, r *http.Request) {
+	if err := h.todoService.UpdateMVC(w, r, components.ActivityReset, h.todoService.ResetMVC); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
+}
 
-	mode := components.TodoViewMode(modeRaw)
-	if mode < components.TodoViewModeAll || mode > components.TodoViewModeCompleted {
-		http.Error(w, "invalid mode", http.StatusBadRequest)
+func (h *Handlers) UndoTodos(w http.ResponseWriter, r *http.Request) {
+	if err := h.todoService.Undo(w, r); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
+}
 
-	h.todoService.SetMode(mvc, mode)
-	if err := h.todoService.SaveMVC(r.Context(), sessionID, mvc); err != nil {
+func (h *Handlers) RedoTodos(w http.ResponseWriter, r *http.Request) {
+	if err := h.todoService.Redo(w, r); err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 }
 
 func (h *Handlers) ToggleTodo(w http.ResponseWriter, r *http.Request) {
-	sessionID, mvc, err := h.todoService.GetSessionMVC(w, r)
-	sse := datastar.NewSSE(w, r)
-	if err != nil {
+	id := h.parseID(r)
+	if err := h.todoService.UpdateMVC(w, r, components.ActivityToggle, func(mvc *components.TodoMVC) {
+		h.todoService.ToggleTodo(mvc, id)
+	}); err != nil {
+		sse := datastar.NewSSE(w, r)
 		if err := sse.ConsoleError(err); err != nil {
 			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
 		}
 		return
 	}
+}
 
-	i, err := h.parseIndex(w, r)
-	if err != nil {
-		if err := sse.ConsoleError(err); err != nil {
-			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+func (h *Handlers) SaveEdit(w http.ResponseWriter, r *http.Request) {
+	type Store struct {
+		Input      string `json:"input"`
+		Due        string `json:"due"`
+		Priority   string `json:"priority"`
+		Tags       string `json:"tags"`
+		Recurrence string `json:"recurrence"`
+	}
+	store := &Store{}
+
+	if err := datastar.ReadSignals(r, store); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if store.Input == "" {
+		return
+	}
+
+	edit := components.Todo{
+		Text: store.Input,
+		Due:  store.Due,
+		Tags: services.ParseTags(store.Tags),
+	}
+	if edit.Due != "" {
+		if _, err := time.Parse(components.TodoDateLayout, edit.Due); err != nil {
+			http.Error(w, "invalid due date", http.StatusBadRequest)
+			return
 		}
+	}
+	if store.Priority != "" {
+		priority, err := strconv.Atoi(store.Priority)
+		if err != nil || priority < int(components.TodoPriorityNone) || priority >= int(components.TodoPriorityLast) {
+			http.Error(w, "invalid priority", http.StatusBadRequest)
+			return
+		}
+		edit.Priority = components.TodoPriority(priority)
+	}
+	recurrence, err := services.ParseRecurrence(store.Recurrence)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
+	edit.Recurrence = recurrence
 
-	h.todoService.ToggleTodo(mvc, i)
-	if err := h.todoService.SaveMVC(r.Context(), sessionID, mvc); err != nil {
+	id := h.parseID(r)
+	action := components.ActivityEdit
+	if id == "" {
+		action = components.ActivityAdd
+	}
+	if err := h.todoService.UpdateMVC(w, r, action, func(mvc *components.TodoMVC) {
+		h.todoService.EditTodo(mvc, id, edit)
+	}); err != nil {
 		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	// the page closes the editor, and reopens the stream without it
+	if id != "" {
+		sse := datastar.NewSSE(w, r)
+		if err := sse.MarshalAndPatchSignals(map[string]string{"editing": ""}); err != nil {
+			slog.Error("Failed to patch signals", "error", err)
+		}
 	}
 }
 
-func (h *Handlers) StartEdit(w http.ResponseWriter, r *http.Request) {
-	sessionID, mvc, err := h.todoService.GetSessionMVC(w, r)
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+func (h *Handlers) AddSubtask(w http.ResponseWriter, r *http.Request) {
+	type Store struct {
+		Subtask string `json:"subtask"`
+	}
+	store := &Store{}
+
+	if err := datastar.ReadSignals(r, store); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	i, err := h.parseIndex(w, r)
-	if err != nil {
+	text := strings.TrimSpace(store.Subtask)
+	if text == "" {
 		return
 	}
 
-	h.todoService.StartEditing(mvc, i)
-	if err := h.todoService.SaveMVC(r.Context(), sessionID, mvc); err != nil {
+	id := h.parseID(r)
+	if err := h.todoService.UpdateMVC(w, r, components.ActivitySubtask, func(mvc *components.TodoMVC) {
+		h.todoService.AddSubtask(mvc, id, text)
+	}); err != nil {
 		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
 	}
 }
 
-func (h *Handlers) SaveEdit(w http.ResponseWriter, r *http.Request) {
+func (h *Handlers) DeleteTodo(w http.ResponseWriter, r *http.Request) {
+	id := h.parseID(r)
+	if err := h.todoService.UpdateMVC(w, r, components.ActivityDelete, func(mvc *components.TodoMVC) {
+		h.todoService.DeleteTodo(mvc, id)
+	}); err != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+	}
+}
+
+func (h *Handlers) ReorderTodos(w http.ResponseWriter, r *http.Request) {
 	type Store struct {
-		Input string `json:"input"`
+		Move struct {
+			ID     string `json:"id"`
+			Before string `json:"before"`
+		} `json:"move"`
 	}
 	store := &Store{}
 
@@ -183,51 +376,143 @@ func (h *Handlers) SaveEdit(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if store.Input == "" {
-		return
+	if err := h.todoService.UpdateMVC(w, r, components.ActivityReorder, func(mvc *components.TodoMVC) {
+		h.todoService.MoveTodo(mvc, store.Move.ID, store.Move.Before)
+	}); err != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
 	}
+}
 
-	sessionID, mvc, err := h.todoService.GetSessionMVC(w, r)
+func (h *Handlers) ExportTodos(w http.ResponseWriter, r *http.Request) {
+	format, err := services.ParseTodoFormat(r.URL.Query().Get("format"))
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	i, err := h.parseIndex(w, r)
+	_, mvc, err := h.todoService.GetSessionMVC(w, r)
 	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	h.todoService.EditTodo(mvc, i, store.Input)
-	if err := h.todoService.SaveMVC(r.Context(), sessionID, mvc); err != nil {
-		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+	w.Header().Set("Content-Type", format.ContentType())
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="todos.%s"`, format))
+	if err := services.ExportTodos(w, format, mvc.Todos); err != nil {
+		slog.Error("Failed to export todos", "error", err)
 	}
 }
 
-func (h *Handlers) DeleteTodo(w http.ResponseWriter, r *http.Request) {
-	i, err := h.parseIndex(w, r)
+// ImportTodos reads an uploaded file in the "file" form field. The format is
+// taken from the "format" field, falling back to the file extension, and
+// "mode" chooses whether to "append" (the default) or "replace".
+func (h *Handlers) ImportTodos(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportSize)
+	if err := r.ParseMultipartForm(maxImportSize); err != nil {
+		h.importError(w, r, "Choose a file to import")
+		return
+	}
+
+	file, header, err := r.FormFile("file")
 	if err != nil {
+		h.importError(w, r, "Choose a file to import")
 		return
 	}
+	defer file.Close()
 
-	sessionID, mvc, err := h.todoService.GetSessionMVC(w, r)
+	formatName := r.FormValue("format")
+	if formatName == "" {
+		formatName = filepath.Ext(header.Filename)
+	}
+	format, err := services.ParseTodoFormat(formatName)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		h.importError(w, r, "Only .json, .csv and .md files can be imported")
 		return
 	}
 
-	h.todoService.DeleteTodo(mvc, i)
-	if err := h.todoService.SaveMVC(r.Context(), sessionID, mvc); err != nil {
+	todos, err := services.ImportTodos(file, format)
+	if err != nil {
+		if !errors.Is(err, services.ErrInvalidImport) {
+			slog.Error("Failed to import todos", "error", err)
+		}
+		h.importError(w, r, err.Error())
+		return
+	}
+
+	replace := r.FormValue("mode") == "replace"
+	if err := h.todoService.UpdateMVC(w, r, components.ActivityImport, func(mvc *components.TodoMVC) {
+		h.todoService.AddTodos(mvc, todos, replace)
+	}); err != nil {
 		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
 	}
+	h.importError(w, r, "")
 }
 
-func (h *Handlers) parseIndex(w http.ResponseWriter, r *http.Request) (int, error) {
-	idx := chi.URLParam(r, "idx")
-	i, err := strconv.Atoi(idx)
+func (h *Handlers) importError(w http.ResponseWriter, r *http.Request, message string) {
+	sse := datastar.NewSSE(w, r)
+	if err := sse.PatchElementTempl(components.TodoImportError(message)); err != nil {
+		slog.Error("Failed to patch import error", "error", err)
+	}
+}
+
+// RequireListRole rejects requests from users without at least role on the
+// list addressed by the route.
+func (h *Handlers) RequireListRole(role services.ListRole) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			actual, err := h.todoService.Role(r)
+			if errors.Is(err, services.ErrListNotFound) {
+				http.NotFound(w, r)
+				return
+			}
+			if err != nil {
+				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+				return
+			}
+			if !actual.Allows(role) {
+				http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// listView describes how the list addressed by the route should be rendered
+// for the current user.
+func (h *Handlers) listView(r *http.Request) (components.TodoListView, error) {
+	view := components.TodoListView{BaseURL: "/api/todos", ActivityURL: "/activity"}
+	listID := chi.URLParam(r, "listID")
+	if listID == "" {
+		return view, nil
+	}
+
+	role, err := h.todoService.Role(r)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
-		return 0, err
+		return view, err
+	}
+	view.BaseURL = "/api/lists/" + listID + "/todos"
+	view.ActivityURL = "/lists/" + listID + "/activity"
+	view.ReadOnly = !role.Allows(services.ListRoleEditor)
+	return view, nil
+}
+
+// presentNames returns the sorted, distinct names of the other people with
+// the list open. Other tabs of the current user are left out.
+func presentNames(present map[string]string, self string) []string {
+	names := make([]string, 0, len(present))
+	for _, name := range present {
+		if name != self && !slices.Contains(names, name) {
+			names = append(names, name)
+		}
 	}
-	return i, nil
+	slices.Sort(names)
+	return names
+}
+
+// parseID returns the todo addressed by the route, or "" for the routes that
+// act on the whole list (toggle all, clear completed, add new).
+func (h *Handlers) parseID(r *http.Request) string {
+	return chi.URLParam(r, "id")
 }
@@ -0,0 +1,122 @@
+package index
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"northstar/app/features/index/pages"
+	"northstar/app/features/index/services"
+	"northstar/app/middleware"
+	"northstar/config"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/starfederation/datastar-go/datastar"
+)
+
+func (h *Handlers) ListsPage(w http.ResponseWriter, r *http.Request) {
+	lists, err := h.listService.ListsForUser(r.Context(), middleware.GetUserIDFromContext(r.Context()))
+	if err != nil {
+		slog.Error("Failed to load lists", "error", err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	if err := pages.ListsPage(lists).Render(r.Context(), w); err != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+	}
+}
+
+func (h *Handlers) CreateList(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	name := strings.TrimSpace(r.FormValue("name"))
+	if name == "" {
+		http.Error(w, "list name is required", http.StatusBadRequest)
+		return
+	}
+
+	list, err := h.listService.CreateList(r.Context(), middleware.GetUserIDFromContext(r.Context()), name)
+	if err != nil {
+		slog.Error("Failed to create list", "error", err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	sse := datastar.NewSSE(w, r)
+	if err := sse.Redirect("/lists/" + list.ID); err != nil {
+		slog.Error("Failed to redirect to new list", "error", err)
+	}
+}
+
+func (h *Handlers) ListPage(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	listID := chi.URLParam(r, "listID")
+
+	role, err := h.todoService.Role(r)
+	if errors.Is(err, services.ErrListNotFound) {
+		http.NotFound(w, r)
+		return
+	}
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	list, err := h.listService.GetList(ctx, listID)
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	members, err := h.listService.Members(ctx, listID)
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	if err := pages.ListPage(list, role, members).Render(ctx, w); err != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+	}
+}
+
+func (h *Handlers) CreateInvite(w http.ResponseWriter, r *http.Request) {
+	role := services.ListRole(chi.URLParam(r, "role"))
+	if role != services.ListRoleEditor && role != services.ListRoleViewer {
+		http.Error(w, "invalid role", http.StatusBadRequest)
+		return
+	}
+
+	invite, err := h.listService.CreateInvite(r.Context(), chi.URLParam(r, "listID"), middleware.GetUserIDFromContext(r.Context()), role)
+	if err != nil {
+		slog.Error("Failed to create invite", "error", err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	url := config.Global.BaseURL + "/invites/" + invite.Token
+
+	sse := datastar.NewSSE(w, r)
+	if err := sse.PatchElementTempl(pages.InviteLink(url)); err != nil {
+		slog.Error("Failed to patch invite link", "error", err)
+	}
+}
+
+func (h *Handlers) AcceptInvite(w http.ResponseWriter, r *http.Request) {
+	listID, err := h.listService.AcceptInvite(r.Context(), chi.URLParam(r, "token"), middleware.GetUserIDFromContext(r.Context()))
+	if errors.Is(err, services.ErrInviteInvalid) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		slog.Error("Failed to accept invite", "error", err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	http.Redirect(w, r, "/lists/"+listID, http.StatusSeeOther)
+}
@@ -0,0 +1,52 @@
+-- name: CreateSharedList :one
+INSERT INTO shared_lists (id, name, owner_id)
+VALUES (?, ?, ?)
+RETURNING *;
+
+-- name: GetSharedList :one
+SELECT * FROM shared_lists WHERE id = ? LIMIT 1;
+
+-- name: ListSharedListsForUser :many
+SELECT shared_lists.*, shared_list_members.role
+FROM shared_lists
+JOIN shared_list_members ON shared_list_members.list_id = shared_lists.id
+WHERE shared_list_members.user_id = ?
+ORDER BY shared_lists.name;
+
+-- name: AddSharedListMember :exec
+INSERT INTO shared_list_members (list_id, user_id, role)
+VALUES (?, ?, ?)
+ON CONFLICT (list_id, user_id) DO NOTHING;
+
+-- name: GetSharedListMemberRole :one
+SELECT role FROM shared_list_members WHERE list_id = ? AND user_id = ? LIMIT 1;
+
+-- name: ListSharedListMembers :many
+SELECT users.id, users.username, shared_list_members.role
+FROM shared_list_members
+JOIN users ON users.id = shared_list_members.user_id
+WHERE shared_list_members.list_id = ?
+ORDER BY users.username;
+
+-- name: CreateSharedListInvite :one
+INSERT INTO shared_list_invites (token, list_id, role, created_by, expires_at)
+VALUES (?, ?, ?, ?, ?)
+RETURNING *;
+
+-- name: GetSharedListInvite :one
+SELECT * FROM shared_list_invites WHERE token = ? LIMIT 1;
+
+-- name: ListOwnedSharedListIDs :many
+SELECT id FROM shared_lists WHERE owner_id = ? ORDER BY id;
+
+-- name: DeleteSharedList :exec
+DELETE FROM shared_lists WHERE id = ?;
+
+-- name: DeleteSharedListMembers :exec
+DELETE FROM shared_list_members WHERE list_id = ?;
+
+-- name: DeleteSharedListInvites :exec
+DELETE FROM shared_list_invites WHERE list_id = ?;
+
+-- name: DeleteUserSharedListMemberships :exec
+DELETE FROM shared_list_members WHERE user_id = ?;
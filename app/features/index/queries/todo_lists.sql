@@ -0,0 +1,28 @@
+-- name: GetTodoList :one
+SELECT * FROM todo_lists WHERE id = ? LIMIT 1;
+
+-- name: CreateTodoList :execrows
+INSERT INTO todo_lists (id, data)
+VALUES (?, ?)
+ON CONFLICT (id) DO NOTHING;
+
+-- name: UpdateTodoList :execrows
+UPDATE todo_lists
+SET data = ?, revision = revision + 1, updated_at = CURRENT_TIMESTAMP
+WHERE id = ? AND revision = ?;
+
+-- name: DeleteTodoList :exec
+DELETE FROM todo_lists WHERE id = ?;
+
+-- name: GetTodoListRevision :one
+SELECT * FROM todo_list_history WHERE list_id = ? AND revision = ? LIMIT 1;
+
+-- name: CreateTodoListRevision :exec
+INSERT INTO todo_list_history (list_id, revision, data)
+VALUES (?, ?, ?);
+
+-- name: PruneTodoListHistory :exec
+DELETE FROM todo_list_history WHERE list_id = ? AND revision <= ?;
+
+-- name: DeleteTodoListHistory :exec
+DELETE FROM todo_list_history WHERE list_id = ?;
@@ -1,43 +1,78 @@
 package index
 
 import (
+	"database/sql"
+
 	"northstar/app/features/index/services"
 	"northstar/app/features/index/web"
+	"northstar/app/middleware"
 	"northstar/app/static"
 
-	"github.com/delaneyj/toolbelt/embeddednats"
 	"github.com/go-chi/chi/v5"
 	"github.com/gorilla/sessions"
 )
 
-func SetupRoutes(router chi.Router, store sessions.Store, ns *embeddednats.Server) error {
-	todoService, err := services.NewTodoService(ns, store)
-	if err != nil {
-		return err
-	}
-
-	handlers := NewHandlers(todoService)
+func SetupRoutes(router chi.Router, store sessions.Store, db *sql.DB, todoService *services.TodoService, listService *services.ListService) error {
+	handlers := NewHandlers(todoService, listService)
 
 	router.Handle("/index/static/*", static.Handler("/index/static", web.StaticDirectory, "index"))
 	router.Get("/", handlers.IndexPage)
+	router.Get("/activity", handlers.ActivityPage)
+
+	router.Group(func(authRouter chi.Router) {
+		authRouter.Use(middleware.RequireAuth(store, db))
+		authRouter.Get("/lists", handlers.ListsPage)
+		authRouter.Post("/lists", handlers.CreateList)
+		authRouter.Get("/lists/{listID}", handlers.ListPage)
+		authRouter.With(handlers.RequireListRole(services.ListRoleViewer)).Get("/lists/{listID}/activity", handlers.ListActivityPage)
+		authRouter.Get("/invites/{token}", handlers.AcceptInvite)
+	})
 
 	router.Route("/api", func(apiRouter chi.Router) {
 		apiRouter.Route("/todos", func(todosRouter chi.Router) {
-			todosRouter.Get("/", handlers.TodosSSE)
-			todosRouter.Put("/reset", handlers.ResetTodos)
-			todosRouter.Put("/cancel", handlers.CancelEdit)
-			todosRouter.Put("/mode/{mode}", handlers.SetMode)
-
-			todosRouter.Route("/{idx}", func(todoRouter chi.Router) {
-				todoRouter.Post("/toggle", handlers.ToggleTodo)
-				todoRouter.Route("/edit", func(editRouter chi.Router) {
-					editRouter.Get("/", handlers.StartEdit)
-					editRouter.Put("/", handlers.SaveEdit)
-				})
-				todoRouter.Delete("/", handlers.DeleteTodo)
+			todoRoutes(todosRouter, handlers)
+		})
+
+		apiRouter.Route("/lists/{listID}", func(listRouter chi.Router) {
+			listRouter.Use(middleware.RequireAuth(store, db))
+			listRouter.With(handlers.RequireListRole(services.ListRoleOwner)).Post("/invites/{role}", handlers.CreateInvite)
+			listRouter.Route("/todos", func(todosRouter chi.Router) {
+				todoRoutes(todosRouter, handlers)
 			})
 		})
 	})
 
 	return nil
 }
+
+// todoRoutes mounts the todo API, which serves both the personal list and
+// shared lists. Viewers may only watch and filter; every change needs an
+// editor.
+func todoRoutes(todosRouter chi.Router, handlers *Handlers) {
+	todosRouter.Group(func(viewRouter chi.Router) {
+		viewRouter.Use(handlers.RequireListRole(services.ListRoleViewer))
+		viewRouter.Get("/", handlers.TodosSSE)
+		viewRouter.Get("/export", handlers.ExportTodos)
+		viewRouter.Get("/activity", handlers.ActivitySSE)
+		viewRouter.Put("/view", handlers.ChangeView)
+	})
+
+	todosRouter.Group(func(editRouter chi.Router) {
+		editRouter.Use(handlers.RequireListRole(services.ListRoleEditor))
+		editRouter.Put("/reset", handlers.ResetTodos)
+		editRouter.Put("/undo", handlers.UndoTodos)
+		editRouter.Put("/redo", handlers.RedoTodos)
+		editRouter.Put("/reorder", handlers.ReorderTodos)
+		editRouter.Post("/import", handlers.ImportTodos)
+		editRouter.Post("/toggle", handlers.ToggleTodo)
+		editRouter.Put("/edit", handlers.SaveEdit)
+		editRouter.Delete("/completed", handlers.DeleteTodo)
+
+		editRouter.Route("/{id}", func(todoRouter chi.Router) {
+			todoRouter.Post("/toggle", handlers.ToggleTodo)
+			todoRouter.Put("/subtasks", handlers.AddSubtask)
+			todoRouter.Put("/edit", handlers.SaveEdit)
+			todoRouter.Delete("/", handlers.DeleteTodo)
+		})
+	})
+}
@@ -0,0 +1,170 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"northstar/app/features/index/components"
+	"northstar/app/middleware"
+
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+const (
+	activityStream = "todos-activity"
+	// MaxActivity is how many activities are kept for each list.
+	MaxActivity    = 200
+	activityMaxAge = 30 * 24 * time.Hour
+)
+
+// activitySubject is where the activity of the list at key is published.
+func activitySubject(key string) string {
+	return "activity." + key
+}
+
+// snapshotActivity copies the todos of a list before a change, used to work
+// out what the change did.
+func snapshotActivity(mvc *components.TodoMVC) ([]byte, error) {
+	todos, err := json.Marshal(mvc.Todos)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal todos: %w", err)
+	}
+	return todos, nil
+}
+
+// newActivity describes how mvc changed since prev, or returns nil when the
+// todos did not change.
+func newActivity(action components.ActivityAction, prev []byte, mvc *components.TodoMVC) (*components.Activity, error) {
+	after, err := json.Marshal(mvc.Todos)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal todos: %w", err)
+	}
+	if bytes.Equal(prev, after) {
+		return nil, nil
+	}
+
+	var before []*components.Todo
+	if err := json.Unmarshal(prev, &before); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal todos: %w", err)
+	}
+
+	activity := &components.Activity{
+		At:     time.Now(),
+		Action: action,
+	}
+	beforeByID, afterByID := todosByID(before), todosByID(mvc.Todos)
+	for _, todo := range before {
+		if next := afterByID[todo.ID]; next == nil || !todoEqual(todo, next) {
+			activity.Before.Todos = append(activity.Before.Todos, todo)
+		}
+	}
+	for _, todo := range mvc.Todos {
+		if prev := beforeByID[todo.ID]; prev == nil || !todoEqual(prev, todo) {
+			activity.After.Todos = append(activity.After.Todos, todo)
+		}
+	}
+	return activity, nil
+}
+
+// updateRecorded runs updateMVC and, once it has saved, records what fn changed
+// as action, attributed to the user making the request. The change stands even
+// if recording it fails, so that is only logged.
+func (s *TodoService) updateRecorded(r *http.Request, sessionID string, action components.ActivityAction, fn func(mvc *components.TodoMVC) error) error {
+	ctx := r.Context()
+	var activity *components.Activity
+	if err := s.updateMVC(ctx, sessionID, func(mvc *components.TodoMVC) error {
+		prev, err := snapshotActivity(mvc)
+		if err != nil {
+			return err
+		}
+		if err := fn(mvc); err != nil {
+			return err
+		}
+		activity, err = newActivity(action, prev, mvc)
+		return err
+	}); err != nil {
+		return err
+	}
+
+	if activity != nil {
+		if user, ok := middleware.GetUserFromContext(ctx); ok {
+			activity.Actor = user.Username
+		}
+	}
+	if err := s.recordActivity(ctx, sessionID, activity); err != nil {
+		slog.Error("failed to record activity", "key", sessionID, "action", action, "error", err)
+	}
+	return nil
+}
+
+// RecordView records that the person making the request switched the list it
+// addresses to mode, or to the todos carrying tag. Filters belong to the page,
+// so nothing about the list itself changes.
+func (s *TodoService) RecordView(w http.ResponseWriter, r *http.Request, mode components.TodoViewMode, tag string) error {
+	sessionID, err := s.sessionKey(r, w)
+	if err != nil {
+		return fmt.Errorf("failed to get session id: %w", err)
+	}
+
+	activity := &components.Activity{
+		At:     time.Now(),
+		Action: components.ActivityMode,
+		After:  components.ActivityState{Mode: mode},
+	}
+	if mode == components.TodoViewModeTag {
+		activity.Action, activity.After.Tag = components.ActivityTag, tag
+	}
+	if user, ok := middleware.GetUserFromContext(r.Context()); ok {
+		activity.Actor = user.Username
+	}
+	return s.recordActivity(r.Context(), sessionID, activity)
+}
+
+// recordActivity appends activity to the list at key. A nil activity, for a
+// change that did nothing visible, is skipped.
+func (s *TodoService) recordActivity(ctx context.Context, key string, activity *components.Activity) error {
+	if activity == nil {
+		return nil
+	}
+	b, err := json.Marshal(activity)
+	if err != nil {
+		return fmt.Errorf("failed to marshal activity: %w", err)
+	}
+	if _, err := s.js.Publish(ctx, activitySubject(key), b); err != nil {
+		return fmt.Errorf("failed to publish activity: %w", err)
+	}
+	return nil
+}
+
+// WatchActivity delivers the recorded activity of the list addressed by the
+// request, oldest first, and then follows new activity as it happens. The
+// pending count in each message's metadata tells when the backlog is done.
+func (s *TodoService) WatchActivity(w http.ResponseWriter, r *http.Request) (jetstream.MessagesContext, error) {
+	sessionID, err := s.sessionKey(r, w)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get session id: %w", err)
+	}
+
+	consumer, err := s.js.OrderedConsumer(r.Context(), activityStream, jetstream.OrderedConsumerConfig{
+		FilterSubjects: []string{activitySubject(sessionID)},
+		DeliverPolicy:  jetstream.DeliverAllPolicy,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create activity consumer: %w", err)
+	}
+	return consumer.Messages()
+}
+
+// DecodeActivity unmarshals an activity delivered by WatchActivity.
+func DecodeActivity(b []byte) (*components.Activity, error) {
+	var activity components.Activity
+	if err := json.Unmarshal(b, &activity); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal activity: %w", err)
+	}
+	return &activity, nil
+}
@@ -0,0 +1,217 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"time"
+
+	"northstar/app/features/index/gen/tododb"
+
+	"github.com/delaneyj/toolbelt"
+)
+
+type ListRole string
+
+const (
+	ListRoleViewer ListRole = "viewer"
+	ListRoleEditor ListRole = "editor"
+	ListRoleOwner  ListRole = "owner"
+)
+
+var (
+	ErrListNotFound  = errors.New("list not found")
+	ErrInviteInvalid = errors.New("invite is invalid or has expired")
+)
+
+const inviteTTL = 7 * 24 * time.Hour
+
+func (r ListRole) level() int {
+	switch r {
+	case ListRoleViewer:
+		return 1
+	case ListRoleEditor:
+		return 2
+	case ListRoleOwner:
+		return 3
+	default:
+		return 0
+	}
+}
+
+// Allows reports whether r grants at least the permissions of role.
+func (r ListRole) Allows(role ListRole) bool {
+	return role.level() > 0 && r.level() >= role.level()
+}
+
+func (r ListRole) Valid() bool {
+	return r.level() > 0
+}
+
+// SharedListKey is the store key holding the todos of a shared list.
+func SharedListKey(listID string) string {
+	return "lists." + listID
+}
+
+// ListService manages named todo lists that several users can open at once,
+// their members and the invites used to join them.
+type ListService struct {
+	db      *sql.DB
+	queries *tododb.Queries
+}
+
+func NewListService(db *sql.DB) *ListService {
+	return &ListService{
+		db:      db,
+		queries: tododb.New(db),
+	}
+}
+
+func (s *ListService) CreateList(ctx context.Context, ownerID, name string) (tododb.SharedList, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return tododb.SharedList{}, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+	queries := s.queries.WithTx(tx)
+
+	list, err := queries.CreateSharedList(ctx, tododb.CreateSharedListParams{
+		ID:      toolbelt.NextEncodedID(),
+		Name:    name,
+		OwnerID: ownerID,
+	})
+	if err != nil {
+		return tododb.SharedList{}, fmt.Errorf("failed to create list: %w", err)
+	}
+
+	if err := queries.AddSharedListMember(ctx, tododb.AddSharedListMemberParams{
+		ListID: list.ID,
+		UserID: ownerID,
+		Role:   string(ListRoleOwner),
+	}); err != nil {
+		return tododb.SharedList{}, fmt.Errorf("failed to add list owner: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return tododb.SharedList{}, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return list, nil
+}
+
+func (s *ListService) GetList(ctx context.Context, listID string) (tododb.SharedList, error) {
+	list, err := s.queries.GetSharedList(ctx, listID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return list, ErrListNotFound
+	}
+	return list, err
+}
+
+func (s *ListService) ListsForUser(ctx context.Context, userID string) ([]tododb.ListSharedListsForUserRow, error) {
+	return s.queries.ListSharedListsForUser(ctx, userID)
+}
+
+func (s *ListService) Members(ctx context.Context, listID string) ([]tododb.ListSharedListMembersRow, error) {
+	return s.queries.ListSharedListMembers(ctx, listID)
+}
+
+// Role returns the role userID has on the list, or ErrListNotFound when they
+// are not a member so lists cannot be probed for existence.
+func (s *ListService) Role(ctx context.Context, listID, userID string) (ListRole, error) {
+	if userID == "" {
+		return "", ErrListNotFound
+	}
+
+	role, err := s.queries.GetSharedListMemberRole(ctx, tododb.GetSharedListMemberRoleParams{
+		ListID: listID,
+		UserID: userID,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", ErrListNotFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get list role: %w", err)
+	}
+	return ListRole(role), nil
+}
+
+func (s *ListService) CreateInvite(ctx context.Context, listID, createdBy string, role ListRole) (tododb.SharedListInvite, error) {
+	if !role.Valid() || role == ListRoleOwner {
+		return tododb.SharedListInvite{}, fmt.Errorf("invalid invite role %q", role)
+	}
+
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return tododb.SharedListInvite{}, fmt.Errorf("failed to generate invite token: %w", err)
+	}
+
+	return s.queries.CreateSharedListInvite(ctx, tododb.CreateSharedListInviteParams{
+		Token:     base64.RawURLEncoding.EncodeToString(b),
+		ListID:    listID,
+		Role:      string(role),
+		CreatedBy: createdBy,
+		ExpiresAt: time.Now().Add(inviteTTL).UTC(),
+	})
+}
+
+// AcceptInvite adds userID to the invite's list and returns the list ID.
+// Existing members keep their current role.
+func (s *ListService) AcceptInvite(ctx context.Context, token, userID string) (string, error) {
+	invite, err := s.queries.GetSharedListInvite(ctx, token)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", ErrInviteInvalid
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get invite: %w", err)
+	}
+	if time.Now().After(invite.ExpiresAt) {
+		return "", ErrInviteInvalid
+	}
+
+	if err := s.queries.AddSharedListMember(ctx, tododb.AddSharedListMemberParams{
+		ListID: invite.ListID,
+		UserID: userID,
+		Role:   invite.Role,
+	}); err != nil {
+		return "", fmt.Errorf("failed to add list member: %w", err)
+	}
+	return invite.ListID, nil
+}
+
+// DeleteUserLists deletes the lists userID owns along with their members and
+// invites, and takes userID off the lists of others. It returns the IDs of the
+// deleted lists, whose todos are left for the caller.
+func (s *ListService) DeleteUserLists(ctx context.Context, userID string) ([]string, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+	queries := s.queries.WithTx(tx)
+
+	listIDs, err := queries.ListOwnedSharedListIDs(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list owned lists: %w", err)
+	}
+	for _, listID := range listIDs {
+		if err := queries.DeleteSharedListInvites(ctx, listID); err != nil {
+			return nil, fmt.Errorf("failed to delete list invites: %w", err)
+		}
+		if err := queries.DeleteSharedListMembers(ctx, listID); err != nil {
+			return nil, fmt.Errorf("failed to delete list members: %w", err)
+		}
+		if err := queries.DeleteSharedList(ctx, listID); err != nil {
+			return nil, fmt.Errorf("failed to delete list: %w", err)
+		}
+	}
+	if err := queries.DeleteUserSharedListMemberships(ctx, userID); err != nil {
+		return nil, fmt.Errorf("failed to delete list memberships: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return listIDs, nil
+}
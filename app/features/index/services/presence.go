@@ -0,0 +1,60 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/delaneyj/toolbelt"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+// presenceTTL is how long a connection is shown as present after its last
+// heartbeat, which covers streams that die without cleaning up.
+const presenceTTL = 30 * time.Second
+
+// TrackPresence announces name as connected to the list stored at key until
+// the returned leave func is called or ctx is done. The returned presence key
+// identifies this connection in WatchPresence updates.
+func (s *TodoService) TrackPresence(ctx context.Context, key, name string) (string, func(), error) {
+	presenceKey := key + "." + toolbelt.NextEncodedID()
+	if _, err := s.presence.PutString(ctx, presenceKey, name); err != nil {
+		return "", nil, err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		t := time.NewTicker(presenceTTL / 3)
+		defer t.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-t.C:
+				if _, err := s.presence.PutString(ctx, presenceKey, name); err != nil && !errors.Is(err, context.Canceled) {
+					slog.Error("failed to refresh presence", "key", presenceKey, "error", err)
+				}
+			}
+		}
+	}()
+
+	leave := func() {
+		cancel()
+		<-done
+		// the request context is gone by now, so clean up with a fresh one
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := s.presence.Delete(ctx, presenceKey); err != nil {
+			slog.Error("failed to remove presence", "key", presenceKey, "error", err)
+		}
+	}
+	return presenceKey, leave, nil
+}
+
+// WatchPresence reports connections joining and leaving the list at key.
+func (s *TodoService) WatchPresence(ctx context.Context, key string) (jetstream.KeyWatcher, error) {
+	return s.presence.Watch(ctx, key+".*")
+}
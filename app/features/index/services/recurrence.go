@@ -0,0 +1,204 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Recurrence presets accepted besides cron expressions.
+const (
+	RecurrenceDaily   = "daily"
+	RecurrenceWeekly  = "weekly"
+	RecurrenceMonthly = "monthly"
+)
+
+// cronSearchDays bounds how far ahead NextOccurrence looks for a match, so
+// expressions that never fire, like February 30th, give up.
+const cronSearchDays = 5 * 366
+
+// ParseRecurrence normalizes a recurrence rule, which is either one of the
+// presets or a five field cron expression: minute, hour, day of month, month
+// and day of week.
+func ParseRecurrence(s string) (string, error) {
+	s = strings.Join(strings.Fields(strings.ToLower(s)), " ")
+	switch s {
+	case "", RecurrenceDaily, RecurrenceWeekly, RecurrenceMonthly:
+		return s, nil
+	}
+	c, err := parseCron(s)
+	if err != nil {
+		return "", err
+	}
+	if _, err := c.next(time.Now()); err != nil {
+		return "", err
+	}
+	return s, nil
+}
+
+// NextOccurrence returns when a todo recurring by rule should come back after
+// being completed at now. Presets step the due date, or today when there is
+// none, forward until it lies after today and come back at midnight, while
+// cron rules come back at their next matching minute. Monthly todos due on a
+// day some months lack come back on the last day of those months.
+func NextOccurrence(rule string, due string, now time.Time) (time.Time, error) {
+	today := midnight(now)
+
+	var step func(start time.Time, n int) time.Time
+	switch rule {
+	case RecurrenceDaily:
+		step = func(t time.Time, n int) time.Time { return t.AddDate(0, 0, n) }
+	case RecurrenceWeekly:
+		step = func(t time.Time, n int) time.Time { return t.AddDate(0, 0, 7*n) }
+	case RecurrenceMonthly:
+		step = addMonths
+	default:
+		c, err := parseCron(rule)
+		if err != nil {
+			return time.Time{}, err
+		}
+		return c.next(now)
+	}
+
+	start := today
+	if d, err := time.ParseInLocation(time.DateOnly, due, now.Location()); err == nil {
+		start = d
+	}
+	// step from start each time, so a monthly todo due on the 31st returns
+	// to the 31st after a shorter month
+	next := start
+	for n := 1; !next.After(today); n++ {
+		next = step(start, n)
+	}
+	return next, nil
+}
+
+// addMonths adds n months to t, keeping to the last day of the month when it
+// is shorter than t's, where AddDate would spill into the month after.
+func addMonths(t time.Time, n int) time.Time {
+	y, m, d := t.Date()
+	last := time.Date(y, m+time.Month(n)+1, 0, 0, 0, 0, 0, t.Location()).Day()
+	return time.Date(y, m+time.Month(n), min(d, last), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+}
+
+func midnight(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
+
+type cron struct {
+	minute, hour, dom, month, dow uint64
+	// domAny and dowAny record a "*" day field, since cron matches either day
+	// field when both are restricted.
+	domAny, dowAny bool
+}
+
+func parseCron(s string) (cron, error) {
+	fields := strings.Fields(s)
+	if len(fields) != 5 {
+		return cron{}, fmt.Errorf("invalid recurrence %q: expected daily, weekly, monthly or a cron expression with 5 fields", s)
+	}
+
+	var c cron
+	var err error
+	if c.minute, err = parseCronField(fields[0], 0, 59); err != nil {
+		return cron{}, fmt.Errorf("invalid minute: %w", err)
+	}
+	if c.hour, err = parseCronField(fields[1], 0, 23); err != nil {
+		return cron{}, fmt.Errorf("invalid hour: %w", err)
+	}
+	if c.dom, err = parseCronField(fields[2], 1, 31); err != nil {
+		return cron{}, fmt.Errorf("invalid day of month: %w", err)
+	}
+	if c.month, err = parseCronField(fields[3], 1, 12); err != nil {
+		return cron{}, fmt.Errorf("invalid month: %w", err)
+	}
+	if c.dow, err = parseCronField(fields[4], 0, 7); err != nil {
+		return cron{}, fmt.Errorf("invalid day of week: %w", err)
+	}
+	if c.dow&(1<<7) != 0 {
+		// both 0 and 7 mean Sunday
+		c.dow |= 1
+	}
+	c.domAny = strings.HasPrefix(fields[2], "*")
+	c.dowAny = strings.HasPrefix(fields[4], "*")
+	return c, nil
+}
+
+// parseCronField parses a comma separated list of values, ranges and steps
+// such as "*/15", "1-5" or "0,30" into a bit set.
+func parseCronField(field string, lo, hi int) (uint64, error) {
+	var bits uint64
+	for part := range strings.SplitSeq(field, ",") {
+		rng, stepStr, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			var err error
+			if step, err = strconv.Atoi(stepStr); err != nil || step <= 0 {
+				return 0, fmt.Errorf("invalid step %q", stepStr)
+			}
+		}
+
+		start, end := lo, hi
+		if rng != "*" {
+			first, last, isRange := strings.Cut(rng, "-")
+			var err error
+			if start, err = strconv.Atoi(first); err != nil {
+				return 0, fmt.Errorf("invalid value %q", first)
+			}
+			end = start
+			if isRange {
+				if end, err = strconv.Atoi(last); err != nil {
+					return 0, fmt.Errorf("invalid value %q", last)
+				}
+			} else if hasStep {
+				end = hi
+			}
+		}
+		if start < lo || end > hi || start > end {
+			return 0, fmt.Errorf("%q is out of range %d-%d", part, lo, hi)
+		}
+
+		for i := start; i <= end; i += step {
+			bits |= 1 << i
+		}
+	}
+	return bits, nil
+}
+
+func (c cron) matchesDay(t time.Time) bool {
+	if c.month&(1<<int(t.Month())) == 0 {
+		return false
+	}
+	dom := c.dom&(1<<t.Day()) != 0
+	dow := c.dow&(1<<int(t.Weekday())) != 0
+	switch {
+	case c.domAny && c.dowAny:
+		return true
+	case c.domAny:
+		return dow
+	case c.dowAny:
+		return dom
+	default:
+		return dom || dow
+	}
+}
+
+// next returns the first matching minute after t.
+func (c cron) next(t time.Time) (time.Time, error) {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	for day := 0; day < cronSearchDays; day++ {
+		tomorrow := midnight(t).AddDate(0, 0, 1)
+		if c.matchesDay(t) {
+			for ; t.Before(tomorrow); t = t.Add(time.Minute) {
+				if c.hour&(1<<t.Hour()) != 0 && c.minute&(1<<t.Minute()) != 0 {
+					return t, nil
+				}
+			}
+		}
+		t = tomorrow
+	}
+	return time.Time{}, errors.New("recurrence never fires")
+}
@@ -0,0 +1,78 @@
+package services
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseRecurrence(t *testing.T) {
+	tests := []struct {
+		in, want string
+		wantErr  bool
+	}{
+		{in: "", want: ""},
+		{in: " Weekly ", want: RecurrenceWeekly},
+		{in: "0  9 * *  1-5", want: "0 9 * * 1-5"},
+		{in: "*/15 8-17 1,15 * 7", want: "*/15 8-17 1,15 * 7"},
+		{in: "fortnightly", wantErr: true},
+		{in: "0 9 * *", wantErr: true},
+		{in: "60 * * * *", wantErr: true},
+		{in: "*/0 * * * *", wantErr: true},
+		{in: "5-1 * * * *", wantErr: true},
+		// February 30th never comes
+		{in: "0 0 30 2 *", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseRecurrence(tt.in)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseRecurrence(%q) = %q, want an error", tt.in, got)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("ParseRecurrence(%q) = %q, %v, want %q", tt.in, got, err, tt.want)
+		}
+	}
+}
+
+func TestNextOccurrence(t *testing.T) {
+	// Tuesday
+	now := time.Date(2026, time.March, 10, 15, 30, 0, 0, time.UTC)
+	date := func(y int, m time.Month, d, hour int) time.Time {
+		return time.Date(y, m, d, hour, 0, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name, rule, due string
+		now             time.Time
+		want            time.Time
+	}{
+		{name: "daily without due date", rule: RecurrenceDaily, now: now, want: date(2026, time.March, 11, 0)},
+		{name: "daily due today", rule: RecurrenceDaily, due: "2026-03-10", now: now, want: date(2026, time.March, 11, 0)},
+		{name: "weekly catches up", rule: RecurrenceWeekly, due: "2026-01-01", now: now, want: date(2026, time.March, 12, 0)},
+		{name: "monthly catches up", rule: RecurrenceMonthly, due: "2025-11-10", now: now, want: date(2026, time.April, 10, 0)},
+		{name: "monthly from the 31st", rule: RecurrenceMonthly, due: "2026-01-31", now: date(2026, time.January, 31, 9), want: date(2026, time.February, 28, 0)},
+		{name: "monthly from the 31st in a leap year", rule: RecurrenceMonthly, due: "2028-01-31", now: date(2028, time.February, 1, 9), want: date(2028, time.February, 29, 0)},
+		{name: "monthly back to the 31st", rule: RecurrenceMonthly, due: "2026-01-31", now: date(2026, time.March, 1, 9), want: date(2026, time.March, 31, 0)},
+		{name: "cron later today", rule: "0 18 * * *", now: now, want: date(2026, time.March, 10, 18)},
+		{name: "cron skips the current minute", rule: "30 15 * * *", now: now, want: time.Date(2026, time.March, 11, 15, 30, 0, 0, time.UTC)},
+		{name: "cron weekday", rule: "0 9 * * 5", now: now, want: date(2026, time.March, 13, 9)},
+		{name: "cron day of month", rule: "0 9 15 * *", now: now, want: date(2026, time.March, 15, 9)},
+		{name: "cron 7 is Sunday", rule: "0 9 * * 7", now: now, want: date(2026, time.March, 15, 9)},
+		// with both day fields restricted either may match
+		{name: "cron weekday before day of month", rule: "0 9 15 * 5", now: now, want: date(2026, time.March, 13, 9)},
+		{name: "cron day of month before weekday", rule: "0 9 11 * 5", now: now, want: date(2026, time.March, 11, 9)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NextOccurrence(tt.rule, tt.due, tt.now)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("NextOccurrence(%q, %q, %v) = %v, want %v", tt.rule, tt.due, tt.now, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package services
+
+import (
+	"slices"
+
+	"northstar/app/features/index/components"
+)
+
+// TodoMVCDiff describes how to bring a rendered TodosMVCView up to date with
+// targeted patches instead of rendering it again.
+type TodoMVCDiff struct {
+	// Full is set when the change cannot be expressed as patches, such as a
+	// reorder or a new filter, and the whole view has to be rendered.
+	Full bool
+	// Controls and Footer are set when those sections need rendering again.
+	Controls bool
+	Footer   bool
+	// Appended holds new visible todos to add to the end of the list.
+	Appended []*components.Todo
+	// Changed holds visible todos whose rows need rendering again.
+	Changed []*components.Todo
+	// Removed holds the IDs of rows to take out of the list.
+	Removed []string
+}
+
+// Empty reports whether nothing visible changed.
+func (d TodoMVCDiff) Empty() bool {
+	return !d.Full && !d.Controls && !d.Footer && len(d.Appended) == 0 && len(d.Changed) == 0 && len(d.Removed) == 0
+}
+
+// DiffMVC compares two states of a list as they would be rendered in view on
+// today, in components.TodoDateLayout.
+func DiffMVC(prev, next *components.TodoMVC, view components.TodoListView, today string) TodoMVCDiff {
+	full := TodoMVCDiff{Full: true}
+	if prev == nil || (len(prev.Todos) > 0) != (len(next.Todos) > 0) {
+		return full
+	}
+
+	var diff TodoMVCDiff
+	diff.Controls = (len(prev.Undo) == 0) != (len(next.Undo) == 0) ||
+		(len(prev.Redo) == 0) != (len(next.Redo) == 0)
+
+	prevLeft, prevCompleted := prev.Counts()
+	nextLeft, nextCompleted := next.Counts()
+	diff.Footer = prevLeft != nextLeft ||
+		prevCompleted != nextCompleted ||
+		(len(prev.Todos) > 1) != (len(next.Todos) > 1) ||
+		!slices.Equal(prev.Tags(), next.Tags())
+
+	prevByID, nextByID := todosByID(prev.Todos), todosByID(next.Todos)
+
+	// Todos kept from the previous state must stay in the same order, and new
+	// ones can only be appended, otherwise rows would need moving around.
+	kept := 0
+	for _, todo := range next.Todos {
+		if prevByID[todo.ID] == nil {
+			continue
+		}
+		for kept < len(prev.Todos) && nextByID[prev.Todos[kept].ID] == nil {
+			kept++
+		}
+		if kept == len(prev.Todos) || prev.Todos[kept].ID != todo.ID {
+			return full
+		}
+		kept++
+	}
+
+	appending := false
+	for _, todo := range next.Todos {
+		old := prevByID[todo.ID]
+		if old == nil {
+			appending = true
+			if view.Visible(todo, today) {
+				diff.Appended = append(diff.Appended, todo)
+			}
+			continue
+		}
+		if appending {
+			return full
+		}
+
+		wasVisible, visible := view.Visible(old, today), view.Visible(todo, today)
+		switch {
+		case !wasVisible && visible:
+			// the row has to appear somewhere in the middle of the list
+			return full
+		case wasVisible && !visible:
+			diff.Removed = append(diff.Removed, todo.ID)
+		case visible && !todoEqual(old, todo):
+			if todo.Find(view.EditingID) != nil {
+				return full
+			}
+			diff.Changed = append(diff.Changed, todo)
+		}
+	}
+
+	for _, todo := range prev.Todos {
+		if nextByID[todo.ID] == nil && view.Visible(todo, today) {
+			if todo.Find(view.EditingID) != nil {
+				return full
+			}
+			diff.Removed = append(diff.Removed, todo.ID)
+		}
+	}
+
+	return diff
+}
+
+func todosByID(todos []*components.Todo) map[string]*components.Todo {
+	byID := make(map[string]*components.Todo, len(todos))
+	for _, todo := range todos {
+		byID[todo.ID] = todo
+	}
+	return byID
+}
+
+func todoEqual(a, b *components.Todo) bool {
+	return a.Text == b.Text &&
+		a.Completed == b.Completed &&
+		a.Due == b.Due &&
+		a.Priority == b.Priority &&
+		slices.Equal(a.Tags, b.Tags) &&
+		a.Recurrence == b.Recurrence &&
+		slices.EqualFunc(a.Children, b.Children, todoEqual)
+}
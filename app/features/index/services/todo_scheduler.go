@@ -0,0 +1,221 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"northstar/app/features/index/components"
+
+	"github.com/delaneyj/toolbelt"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/samber/lo"
+)
+
+// schedulerInterval is how often RunScheduler looks for occurrences that are
+// due, which also bounds how late they show up.
+const schedulerInterval = time.Minute
+
+// RunScheduler adds the next occurrences of recurring todos to their lists
+// once they are due, until ctx is cancelled. Watchers of the lists see them
+// appear like any other change.
+func (s *TodoService) RunScheduler(ctx context.Context) error {
+	ticker := time.NewTicker(schedulerInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.materializeDue(ctx, time.Now()); err != nil && ctx.Err() == nil {
+			slog.Error("failed to add recurring todos", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// materializeDue goes through the lists whose earliest upcoming todo is due
+// at now. The schedule bucket maps each list key to that time, so lists
+// without recurring todos are never read.
+func (s *TodoService) materializeDue(ctx context.Context, now time.Time) error {
+	lister, err := s.schedule.ListKeys(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list schedule: %w", err)
+	}
+	var keys []string
+	for key := range lister.Keys() {
+		keys = append(keys, key)
+	}
+
+	var errs []error
+	for _, key := range keys {
+		entry, err := s.schedule.Get(ctx, key)
+		if errors.Is(err, jetstream.ErrKeyNotFound) {
+			continue
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to get schedule for %s: %w", key, err))
+			continue
+		}
+
+		at, err := time.Parse(time.RFC3339, string(entry.Value()))
+		if err == nil && at.After(now) {
+			continue
+		}
+		if err := s.materializeList(ctx, entry, now); err != nil {
+			errs = append(errs, fmt.Errorf("failed to add recurring todos to %s: %w", key, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// materializeList moves the due upcoming todos of one list into it. Saving
+// the list indexes what is still upcoming, otherwise the schedule entry is
+// dropped unless the list was scheduled again in the meantime.
+func (s *TodoService) materializeList(ctx context.Context, entry jetstream.KeyValueEntry, now time.Time) error {
+	key := entry.Key()
+	for attempt := range maxUpdateAttempts {
+		mvc, err := s.todoStore.Get(ctx, key)
+		if errors.Is(err, ErrTodosNotFound) {
+			return s.unschedule(ctx, entry)
+		}
+		if err != nil {
+			return err
+		}
+
+		due := lo.Filter(mvc.Upcoming, func(upcoming *components.UpcomingTodo, _ int) bool {
+			return !upcoming.At.After(now)
+		})
+		if len(due) == 0 {
+			if len(mvc.Upcoming) > 0 {
+				// the entry is stale, point it at the next occurrence
+				return s.indexSchedule(ctx, key, mvc)
+			}
+			return s.unschedule(ctx, entry)
+		}
+
+		prev, err := snapshotActivity(mvc)
+		if err != nil {
+			return err
+		}
+		mvc.Upcoming = lo.Without(mvc.Upcoming, due...)
+		for _, upcoming := range due {
+			mvc.Todos = append(mvc.Todos, upcoming.Todo)
+		}
+		activity, err := newActivity(components.ActivityRecur, prev, mvc)
+		if err != nil {
+			return err
+		}
+
+		err = s.saveMVC(ctx, key, mvc)
+		if err == nil {
+			if err := s.recordActivity(ctx, key, activity); err != nil {
+				slog.Error("failed to record activity", "key", key, "action", components.ActivityRecur, "error", err)
+			}
+			if len(mvc.Upcoming) == 0 {
+				return s.unschedule(ctx, entry)
+			}
+			return nil
+		}
+		if !errors.Is(err, ErrTodosConflict) {
+			return err
+		}
+		if err := backoff(ctx, attempt); err != nil {
+			return err
+		}
+	}
+	return fmt.Errorf("failed to save mvc after %d attempts: %w", maxUpdateAttempts, ErrTodosConflict)
+}
+
+// unschedule deletes a schedule entry, unless it changed since it was read.
+func (s *TodoService) unschedule(ctx context.Context, entry jetstream.KeyValueEntry) error {
+	err := s.schedule.Delete(ctx, entry.Key(), jetstream.LastRevision(entry.Revision()))
+	if err != nil && !IsWrongLastSequence(err) && !errors.Is(err, jetstream.ErrKeyNotFound) {
+		return fmt.Errorf("failed to delete schedule: %w", err)
+	}
+	return nil
+}
+
+// indexSchedule records when the earliest upcoming todo of the list at key is
+// due, so RunScheduler finds it.
+func (s *TodoService) indexSchedule(ctx context.Context, key string, mvc *components.TodoMVC) error {
+	if len(mvc.Upcoming) == 0 {
+		return nil
+	}
+	earliest := slices.MinFunc(mvc.Upcoming, func(a, b *components.UpcomingTodo) int {
+		return a.At.Compare(b.At)
+	})
+	value := earliest.At.UTC().Format(time.RFC3339)
+
+	entry, err := s.schedule.Get(ctx, key)
+	if err == nil && string(entry.Value()) == value {
+		return nil
+	}
+	if err != nil && !errors.Is(err, jetstream.ErrKeyNotFound) {
+		return fmt.Errorf("failed to get schedule: %w", err)
+	}
+	if _, err := s.schedule.PutString(ctx, key, value); err != nil {
+		return fmt.Errorf("failed to put schedule: %w", err)
+	}
+	return nil
+}
+
+// pendingRecurring returns the IDs of the recurring todos that are not
+// completed yet.
+func pendingRecurring(mvc *components.TodoMVC) map[string]bool {
+	pending := map[string]bool{}
+	for _, todo := range mvc.Todos {
+		if todo.Recurrence != "" && !todo.Completed {
+			pending[todo.ID] = true
+		}
+	}
+	return pending
+}
+
+// scheduleRecurring queues the next occurrence of every recurring todo that
+// was pending and is now completed, and cancels the occurrences of todos that
+// were reopened or stopped recurring. Occurrences of deleted todos still come,
+// so clearing completed todos does not end the series.
+func scheduleRecurring(mvc *components.TodoMVC, pending map[string]bool, now time.Time) {
+	todos := todosByID(mvc.Todos)
+	mvc.Upcoming = lo.Filter(mvc.Upcoming, func(upcoming *components.UpcomingTodo, _ int) bool {
+		from := todos[upcoming.From]
+		return from == nil || (from.Completed && from.Recurrence != "")
+	})
+
+	for _, todo := range mvc.Todos {
+		if !todo.Completed || todo.Recurrence == "" || !pending[todo.ID] {
+			continue
+		}
+		at, err := NextOccurrence(todo.Recurrence, todo.Due, now)
+		if err != nil {
+			// rules are validated when saved, so this one was stored before
+			// it became invalid and cannot be followed
+			continue
+		}
+		next := cloneTodo(todo)
+		next.Due = at.Format(components.TodoDateLayout)
+		mvc.Upcoming = append(mvc.Upcoming, &components.UpcomingTodo{
+			At:   at,
+			From: todo.ID,
+			Todo: next,
+		})
+	}
+}
+
+// cloneTodo copies a todo and its subtasks under new IDs, uncompleted.
+func cloneTodo(todo *components.Todo) *components.Todo {
+	clone := *todo
+	clone.ID = toolbelt.NextEncodedID()
+	clone.Completed = false
+	clone.Tags = slices.Clone(todo.Tags)
+	clone.Children = lo.Map(todo.Children, func(child *components.Todo, _ int) *components.Todo {
+		return cloneTodo(child)
+	})
+	return &clone
+}
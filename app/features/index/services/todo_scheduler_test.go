@@ -0,0 +1,75 @@
+package services
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"northstar/app/features/index/components"
+	"northstar/config"
+
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+func TestMaterializeDue(t *testing.T) {
+	s := newTestTodoService(t, config.TodoStorageKV)
+	ctx := t.Context()
+	const key = "scheduled"
+
+	now := time.Date(2026, time.March, 10, 9, 0, 0, 0, time.UTC)
+	first := &components.Todo{ID: "first", Text: "Water the plants", Due: "2026-03-11"}
+	second := &components.Todo{ID: "second", Text: "Pay rent", Due: "2026-04-01"}
+	mvc := &components.TodoMVC{
+		Todos: []*components.Todo{{ID: "done", Text: "Done", Completed: true}},
+		Upcoming: []*components.UpcomingTodo{
+			{At: now.AddDate(0, 0, 1), From: "done", Todo: first},
+			{At: now.AddDate(0, 0, 22), From: "done", Todo: second},
+		},
+	}
+	if err := s.saveMVC(ctx, key, mvc); err != nil {
+		t.Fatal(err)
+	}
+
+	// check materializes the due todos at now and verifies what the list
+	// and its schedule entry hold afterwards.
+	check := func(now time.Time, wantTodos []string, wantUpcoming int, wantSchedule time.Time) {
+		t.Helper()
+		if err := s.materializeDue(ctx, now); err != nil {
+			t.Fatal(err)
+		}
+		mvc, err := s.todoStore.Get(ctx, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var ids []string
+		for _, todo := range mvc.Todos {
+			ids = append(ids, todo.ID)
+		}
+		if !slices.Equal(ids, wantTodos) {
+			t.Fatalf("at %v todos are %v, want %v", now, ids, wantTodos)
+		}
+		if len(mvc.Upcoming) != wantUpcoming {
+			t.Errorf("at %v %d todos are upcoming, want %d", now, len(mvc.Upcoming), wantUpcoming)
+		}
+
+		entry, err := s.schedule.Get(ctx, key)
+		if wantSchedule.IsZero() {
+			if !errors.Is(err, jetstream.ErrKeyNotFound) {
+				t.Errorf("at %v schedule entry is %v, want none", now, err)
+			}
+			return
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := string(entry.Value()), wantSchedule.Format(time.RFC3339); got != want {
+			t.Errorf("at %v schedule is %s, want %s", now, got, want)
+		}
+	}
+
+	check(now, []string{"done"}, 2, now.AddDate(0, 0, 1))
+	check(now.AddDate(0, 0, 1).Add(-time.Second), []string{"done"}, 2, now.AddDate(0, 0, 1))
+	check(now.AddDate(0, 0, 1), []string{"done", "first"}, 1, now.AddDate(0, 0, 22))
+	check(now.AddDate(0, 1, 0), []string{"done", "first", "second"}, 0, time.Time{})
+}
@@ -1,27 +1,43 @@
 package services
 
 import (
+	"bytes"
 	"context"
+	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"math/rand/v2"
 	"net/http"
+	"slices"
+	"strings"
 	"time"
 
 	"northstar/app/features/index/components"
+	"northstar/app/middleware"
+	"northstar/config"
 
 	"github.com/delaneyj/toolbelt"
 	"github.com/delaneyj/toolbelt/embeddednats"
+	"github.com/go-chi/chi/v5"
 	"github.com/gorilla/sessions"
 	"github.com/nats-io/nats.go/jetstream"
 	"github.com/samber/lo"
 )
 
+const maxUpdateAttempts = 20
+
 type TodoService struct {
-	kv    jetstream.KeyValue
-	store sessions.Store
+	js        jetstream.JetStream
+	kv        jetstream.KeyValue
+	presence  jetstream.KeyValue
+	schedule  jetstream.KeyValue
+	todoStore TodoStore
+	lists     *ListService
+	store     sessions.Store
 }
 
-func NewTodoService(ns *embeddednats.Server, store sessions.Store) (*TodoService, error) {
+func NewTodoService(ns *embeddednats.Server, store sessions.Store, db *sql.DB, lists *ListService) (*TodoService, error) {
 	nc, err := ns.Client()
 	if err != nil {
 		return nil, fmt.Errorf("error creating nats client: %w", err)
@@ -36,130 +52,549 @@ func NewTodoService(ns *embeddednats.Server, store sessions.Store) (*TodoService
 		Bucket:      "todos",
 		Description: "Datastar Todos",
 		Compression: true,
-		TTL:         time.Hour,
 		MaxBytes:    16 * 1024 * 1024,
+		History:     TodoHistorySize,
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("error creating key value: %w", err)
+	}
+
+	presence, err := js.CreateOrUpdateKeyValue(context.Background(), jetstream.KeyValueConfig{
+		Bucket:         "todos-presence",
+		Description:    "Who is looking at each shared todo list",
+		TTL:            presenceTTL,
+		LimitMarkerTTL: presenceTTL,
+		Storage:        jetstream.MemoryStorage,
 	})
+	if err != nil {
+		return nil, fmt.Errorf("error creating key value: %w", err)
+	}
 
+	schedule, err := js.CreateOrUpdateKeyValue(context.Background(), jetstream.KeyValueConfig{
+		Bucket:      "todos-schedule",
+		Description: "When each todo list next has a recurring todo due",
+	})
 	if err != nil {
 		return nil, fmt.Errorf("error creating key value: %w", err)
 	}
 
+	if _, err := js.CreateOrUpdateStream(context.Background(), jetstream.StreamConfig{
+		Name:              activityStream,
+		Description:       "Changes made to each todo list",
+		Subjects:          []string{activitySubject(">")},
+		MaxAge:            activityMaxAge,
+		MaxMsgsPerSubject: MaxActivity,
+	}); err != nil {
+		return nil, fmt.Errorf("error creating stream: %w", err)
+	}
+
+	var todoStore TodoStore
+	switch config.Global.TodoStorage {
+	case config.TodoStorageKV:
+		todoStore = NewKVTodoStore(kv)
+	case config.TodoStorageSQLite:
+		todoStore = NewSQLiteTodoStore(db, kv)
+	default:
+		return nil, fmt.Errorf("unknown todo storage %q", config.Global.TodoStorage)
+	}
+
 	return &TodoService{
-		kv:    kv,
-		store: store,
+		js:        js,
+		kv:        kv,
+		presence:  presence,
+		schedule:  schedule,
+		todoStore: todoStore,
+		lists:     lists,
+		store:     store,
 	}, nil
 }
 
 func (s *TodoService) GetSessionMVC(w http.ResponseWriter, r *http.Request) (string, *components.TodoMVC, error) {
-	ctx := r.Context()
-	sessionID, err := s.upsertSessionID(r, w)
+	sessionID, err := s.sessionKey(r, w)
 	if err != nil {
 		return "", nil, fmt.Errorf("failed to get session id: %w", err)
 	}
 
-	mvc := &components.TodoMVC{}
-	if entry, err := s.kv.Get(ctx, sessionID); err != nil {
-		if err != jetstream.ErrKeyNotFound {
-			return "", nil, fmt.Errorf("failed to get key value: %w", err)
+	mvc, err := s.loadMVC(r.Context(), sessionID)
+	if err != nil {
+		return "", nil, err
+	}
+	return sessionID, mvc, nil
+}
+
+// SaveMVC saves mvc if nobody else has saved since it was read, returning
+// ErrTodosConflict otherwise. Use UpdateMVC to retry automatically.
+func (s *TodoService) SaveMVC(ctx context.Context, sessionID string, mvc *components.TodoMVC) error {
+	return s.saveMVC(ctx, sessionID, mvc)
+}
+
+// UpdateMVC applies fn to the session's todos and saves the result. When a
+// concurrent request saved first, fn is re-applied to the fresh state.
+//
+// Changes to the todos themselves are recorded so they can be undone, and
+// every visible change is added to the list's activity as action.
+func (s *TodoService) UpdateMVC(w http.ResponseWriter, r *http.Request, action components.ActivityAction, fn func(mvc *components.TodoMVC)) error {
+	sessionID, err := s.sessionKey(r, w)
+	if err != nil {
+		return fmt.Errorf("failed to get session id: %w", err)
+	}
+	return s.updateRecorded(r, sessionID, action, func(mvc *components.TodoMVC) error {
+		before, err := json.Marshal(mvc.Todos)
+		if err != nil {
+			return fmt.Errorf("failed to marshal todos: %w", err)
 		}
-		s.resetMVC(mvc)
+		revision := mvc.Revision
 
-		if err := s.saveMVC(ctx, sessionID, mvc); err != nil {
-			return "", nil, fmt.Errorf("failed to save mvc: %w", err)
+		fn(mvc)
+
+		after, err := json.Marshal(mvc.Todos)
+		if err != nil {
+			return fmt.Errorf("failed to marshal todos: %w", err)
 		}
-	} else {
-		if err := json.Unmarshal(entry.Value(), mvc); err != nil {
-			return "", nil, fmt.Errorf("failed to unmarshal mvc: %w", err)
+		if !bytes.Equal(before, after) {
+			mvc.Undo = pushRevision(mvc.Undo, revision)
+			mvc.Redo = nil
 		}
+		return nil
+	})
+}
+
+// Undo restores the todos to how they were before the last change.
+func (s *TodoService) Undo(w http.ResponseWriter, r *http.Request) error {
+	sessionID, err := s.sessionKey(r, w)
+	if err != nil {
+		return fmt.Errorf("failed to get session id: %w", err)
 	}
-	return sessionID, mvc, nil
+	return s.updateRecorded(r, sessionID, components.ActivityUndo, func(mvc *components.TodoMVC) error {
+		return s.restoreRevision(r.Context(), sessionID, mvc, &mvc.Undo, &mvc.Redo)
+	})
 }
 
-func (s *TodoService) SaveMVC(ctx context.Context, sessionID string, mvc *components.TodoMVC) error {
-	return s.saveMVC(ctx, sessionID, mvc)
+// Redo reapplies the last change reverted by Undo.
+func (s *TodoService) Redo(w http.ResponseWriter, r *http.Request) error {
+	sessionID, err := s.sessionKey(r, w)
+	if err != nil {
+		return fmt.Errorf("failed to get session id: %w", err)
+	}
+	return s.updateRecorded(r, sessionID, components.ActivityRedo, func(mvc *components.TodoMVC) error {
+		return s.restoreRevision(r.Context(), sessionID, mvc, &mvc.Redo, &mvc.Undo)
+	})
 }
 
 func (s *TodoService) ResetMVC(mvc *components.TodoMVC) {
 	s.resetMVC(mvc)
 }
 
+// WatchUpdates watches the list at sessionID. The latest value is delivered
+// first, so changes made after the caller read the list are never missed.
 func (s *TodoService) WatchUpdates(ctx context.Context, sessionID string) (jetstream.KeyWatcher, error) {
 	return s.kv.Watch(ctx, sessionID)
 }
 
-func (s *TodoService) ToggleTodo(mvc *components.TodoMVC, index int) {
-	if index < 0 {
+// ToggleTodo flips the todo with the given id along with all its subtasks, or
+// toggles every todo when id is empty. Parents are kept completed exactly
+// when all their subtasks are.
+func (s *TodoService) ToggleTodo(mvc *components.TodoMVC, id string) {
+	if id == "" {
 		setCompletedTo := false
-		for _, todo := range mvc.Todos {
+		mvc.Walk(func(todo *components.Todo) {
 			if !todo.Completed {
 				setCompletedTo = true
-				break
 			}
-		}
+		})
 		for _, todo := range mvc.Todos {
-			todo.Completed = setCompletedTo
+			setCompleted(todo, setCompletedTo)
 		}
-	} else if index < len(mvc.Todos) {
-		todo := mvc.Todos[index]
-		todo.Completed = !todo.Completed
+	} else if todo := mvc.Todo(id); todo != nil {
+		setCompleted(todo, !todo.Completed)
 	}
+	syncCompleted(mvc.Todos)
 }
 
-func (s *TodoService) EditTodo(mvc *components.TodoMVC, index int, text string) {
-	if index >= 0 && index < len(mvc.Todos) {
-		mvc.Todos[index].Text = text
-	} else if index < 0 {
+// EditTodo applies the text, due date, priority, tags and recurrence of edit
+// to the todo with the given id, or adds it as a new todo when id is empty.
+// Subtasks do not recur, so their recurrence is left alone.
+func (s *TodoService) EditTodo(mvc *components.TodoMVC, id string, edit components.Todo) {
+	if id == "" {
 		mvc.Todos = append(mvc.Todos, &components.Todo{
-			Text:      text,
-			Completed: false,
+			ID:         toolbelt.NextEncodedID(),
+			Text:       edit.Text,
+			Due:        edit.Due,
+			Priority:   edit.Priority,
+			Tags:       edit.Tags,
+			Recurrence: edit.Recurrence,
 		})
+	} else if todo := mvc.Todo(id); todo != nil {
+		todo.Text = edit.Text
+		todo.Due = edit.Due
+		todo.Priority = edit.Priority
+		todo.Tags = edit.Tags
+		if slices.Contains(mvc.Todos, todo) {
+			todo.Recurrence = edit.Recurrence
+		}
+	}
+}
+
+// DeleteTodo removes the todo or subtask with the given id, or every
+// completed todo and subtask when id is empty.
+func (s *TodoService) DeleteTodo(mvc *components.TodoMVC, id string) {
+	mvc.Todos = deleteTodos(mvc.Todos, func(todo *components.Todo) bool {
+		if id == "" {
+			return todo.Completed
+		}
+		return todo.ID == id
+	})
+	syncCompleted(mvc.Todos)
+}
+
+// AddSubtask appends a subtask with the given text to the todo with parentID.
+// Editing carries on, so several subtasks can be added in a row.
+func (s *TodoService) AddSubtask(mvc *components.TodoMVC, parentID string, text string) {
+	parent := mvc.Todo(parentID)
+	if parent == nil {
+		return
+	}
+	parent.Children = append(parent.Children, &components.Todo{
+		ID:   toolbelt.NextEncodedID(),
+		Text: text,
+	})
+	syncCompleted(mvc.Todos)
+}
+
+// MoveTodo moves the top level todo with the given id in front of beforeID, or
+// to the end of the list when beforeID is empty or unknown.
+func (s *TodoService) MoveTodo(mvc *components.TodoMVC, id string, beforeID string) {
+	i := slices.IndexFunc(mvc.Todos, func(todo *components.Todo) bool { return todo.ID == id })
+	if i < 0 || id == beforeID {
+		return
+	}
+	todo := mvc.Todos[i]
+
+	todos := lo.Without(mvc.Todos, todo)
+	i = slices.IndexFunc(todos, func(todo *components.Todo) bool { return todo.ID == beforeID })
+	if i < 0 {
+		i = len(todos)
+	}
+	mvc.Todos = slices.Insert(todos, i, todo)
+}
+
+// AddTodos appends imported todos to the list, or replaces its todos
+// entirely when replace is set.
+func (s *TodoService) AddTodos(mvc *components.TodoMVC, todos []*components.Todo, replace bool) {
+	if replace {
+		mvc.Todos = nil
 	}
-	mvc.EditingIdx = -1
+	mvc.Todos = append(mvc.Todos, todos...)
 }
 
-func (s *TodoService) DeleteTodo(mvc *components.TodoMVC, index int) {
-	if index >= 0 && index < len(mvc.Todos) {
-		mvc.Todos = append(mvc.Todos[:index], mvc.Todos[index+1:]...)
-	} else if index < 0 {
-		mvc.Todos = lo.Filter(mvc.Todos, func(todo *components.Todo, i int) bool {
-			return !todo.Completed
+func setCompleted(todo *components.Todo, completed bool) {
+	todo.Completed = completed
+	for _, child := range todo.Children {
+		setCompleted(child, completed)
+	}
+}
+
+// syncCompleted marks parents completed exactly when all their subtasks are.
+func syncCompleted(todos []*components.Todo) {
+	for _, todo := range todos {
+		if len(todo.Children) == 0 {
+			continue
+		}
+		syncCompleted(todo.Children)
+		todo.Completed = !slices.ContainsFunc(todo.Children, func(child *components.Todo) bool {
+			return !child.Completed
 		})
 	}
 }
 
-func (s *TodoService) SetMode(mvc *components.TodoMVC, mode components.TodoViewMode) {
-	mvc.Mode = mode
+func deleteTodos(todos []*components.Todo, match func(*components.Todo) bool) []*components.Todo {
+	todos = lo.Filter(todos, func(todo *components.Todo, _ int) bool {
+		return !match(todo)
+	})
+	for _, todo := range todos {
+		todo.Children = deleteTodos(todo.Children, match)
+	}
+	return todos
 }
 
-func (s *TodoService) StartEditing(mvc *components.TodoMVC, index int) {
-	mvc.EditingIdx = index
+// ParseTags splits a comma separated list of tags, dropping blanks, a leading
+// '#' and duplicates.
+func ParseTags(s string) []string {
+	var tags []string
+	for _, tag := range strings.Split(s, ",") {
+		tag = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(tag), "#"))
+		if tag != "" && !slices.Contains(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
 }
 
-func (s *TodoService) CancelEditing(mvc *components.TodoMVC) {
-	mvc.EditingIdx = -1
+func (s *TodoService) loadMVC(ctx context.Context, sessionID string) (*components.TodoMVC, error) {
+	for {
+		mvc, err := s.todoStore.Get(ctx, sessionID)
+		if !errors.Is(err, ErrTodosNotFound) {
+			return mvc, err
+		}
+
+		mvc = &components.TodoMVC{}
+		s.resetMVC(mvc)
+		err = s.saveMVC(ctx, sessionID, mvc)
+		if errors.Is(err, ErrTodosConflict) {
+			// another request created the list first, use theirs
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to save mvc: %w", err)
+		}
+		return mvc, nil
+	}
 }
 
-func (s *TodoService) saveMVC(ctx context.Context, sessionID string, mvc *components.TodoMVC) error {
-	b, err := json.Marshal(mvc)
-	if err != nil {
-		return fmt.Errorf("failed to marshal mvc: %w", err)
+// updateMVC applies fn to the list at sessionID and saves it, retrying on
+// conflicts. Recurring todos completed or reopened by fn have their next
+// occurrence scheduled or cancelled.
+func (s *TodoService) updateMVC(ctx context.Context, sessionID string, fn func(mvc *components.TodoMVC) error) error {
+	for attempt := range maxUpdateAttempts {
+		mvc, err := s.loadMVC(ctx, sessionID)
+		if err != nil {
+			return err
+		}
+
+		pending := pendingRecurring(mvc)
+		if err := fn(mvc); err != nil {
+			return err
+		}
+		scheduleRecurring(mvc, pending, time.Now())
+		err = s.saveMVC(ctx, sessionID, mvc)
+		if !errors.Is(err, ErrTodosConflict) {
+			return err
+		}
+		if err := backoff(ctx, attempt); err != nil {
+			return err
+		}
+	}
+	return fmt.Errorf("failed to save mvc after %d attempts: %w", maxUpdateAttempts, ErrTodosConflict)
+}
+
+// restoreRevision pops the newest revision from `from`, replaces the todos with
+// the ones saved at that revision and pushes the current revision onto `to`.
+// Revisions that have aged out of the store's history are dropped.
+func (s *TodoService) restoreRevision(ctx context.Context, sessionID string, mvc *components.TodoMVC, from, to *[]uint64) error {
+	if len(*from) == 0 {
+		return nil
+	}
+	last := len(*from) - 1
+	revision := (*from)[last]
+	*from = (*from)[:last]
+
+	prev, err := s.todoStore.GetRevision(ctx, sessionID, revision)
+	if errors.Is(err, ErrTodosNotFound) {
+		*from = nil
+		return nil
 	}
-	if _, err := s.kv.Put(ctx, sessionID, b); err != nil {
-		return fmt.Errorf("failed to put key value: %w", err)
+	if err != nil {
+		return err
 	}
+
+	*to = pushRevision(*to, mvc.Revision)
+	mvc.Todos = prev.Todos
 	return nil
 }
 
+func pushRevision(revisions []uint64, revision uint64) []uint64 {
+	if revision == 0 {
+		return revisions
+	}
+	revisions = append(revisions, revision)
+	if len(revisions) >= TodoHistorySize {
+		revisions = revisions[len(revisions)-TodoHistorySize+1:]
+	}
+	return revisions
+}
+
+// backoff waits a random, growing delay so writers that keep colliding on the
+// same list spread out instead of retrying in lockstep.
+func backoff(ctx context.Context, attempt int) error {
+	delay := time.Duration(rand.Int64N(int64(attempt+1) * int64(5*time.Millisecond)))
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(delay):
+		return nil
+	}
+}
+
+func (s *TodoService) saveMVC(ctx context.Context, sessionID string, mvc *components.TodoMVC) error {
+	if err := s.todoStore.Put(ctx, sessionID, mvc); err != nil {
+		return err
+	}
+	return s.indexSchedule(ctx, sessionID, mvc)
+}
+
 func (s *TodoService) resetMVC(mvc *components.TodoMVC) {
-	mvc.Mode = components.TodoViewModeAll
 	mvc.Todos = []*components.Todo{
-		{Text: "Learn any backend language", Completed: true},
-		{Text: "Learn Datastar", Completed: false},
-		{Text: "Create Hypermedia", Completed: false},
-		{Text: "???", Completed: false},
-		{Text: "Profit", Completed: false},
+		{ID: toolbelt.NextEncodedID(), Text: "Learn any backend language", Completed: true},
+		{ID: toolbelt.NextEncodedID(), Text: "Learn Datastar", Completed: false},
+		{ID: toolbelt.NextEncodedID(), Text: "Create Hypermedia", Completed: false},
+		{ID: toolbelt.NextEncodedID(), Text: "???", Completed: false},
+		{ID: toolbelt.NextEncodedID(), Text: "Profit", Completed: false},
+	}
+}
+
+// Role returns what the current user may do with the list addressed by the
+// request. Everyone owns their personal list.
+func (s *TodoService) Role(r *http.Request) (ListRole, error) {
+	listID := chi.URLParam(r, "listID")
+	if listID == "" {
+		return ListRoleOwner, nil
+	}
+	return s.lists.Role(r.Context(), listID, middleware.GetUserIDFromContext(r.Context()))
+}
+
+// sessionKey returns the KV key holding the todos for this request. Shared
+// lists are addressed by the route and only open to their members. Otherwise
+// logged-in users own their list, so it follows them across browsers;
+// anonymous visitors fall back to the random ID kept in the "connections"
+// cookie.
+func (s *TodoService) sessionKey(r *http.Request, w http.ResponseWriter) (string, error) {
+	userID := middleware.GetUserIDFromContext(r.Context())
+	if listID := chi.URLParam(r, "listID"); listID != "" {
+		if _, err := s.lists.Role(r.Context(), listID, userID); err != nil {
+			return "", err
+		}
+		return SharedListKey(listID), nil
+	}
+
+	if userID == "" {
+		return s.upsertSessionID(r, w)
+	}
+
+	key := userKey(userID)
+	if err := s.adoptAnonymousMVC(r, w, key); err != nil {
+		return "", fmt.Errorf("failed to merge anonymous todos: %w", err)
+	}
+	return key, nil
+}
+
+// adoptAnonymousMVC merges the todos created before logging in or signing up
+// into the user's list, then forgets the anonymous list so it is only merged
+// once.
+func (s *TodoService) adoptAnonymousMVC(r *http.Request, w http.ResponseWriter, key string) error {
+	ctx := r.Context()
+	sess, err := s.store.Get(r, "connections")
+	if err != nil {
+		return fmt.Errorf("failed to get session: %w", err)
+	}
+
+	anonID, ok := sess.Values["id"].(string)
+	if !ok {
+		return nil
+	}
+
+	anon, err := s.todoStore.Get(ctx, anonID)
+	if err != nil && !errors.Is(err, ErrTodosNotFound) {
+		return err
+	}
+
+	if anon != nil {
+		if err := s.mergeIntoMVC(ctx, key, anon); err != nil {
+			return err
+		}
+		if err := s.todoStore.Delete(ctx, anonID); err != nil {
+			return err
+		}
+	}
+
+	delete(sess.Values, "id")
+	if err := sess.Save(r, w); err != nil {
+		return fmt.Errorf("failed to save session: %w", err)
+	}
+	return nil
+}
+
+func (s *TodoService) mergeIntoMVC(ctx context.Context, key string, anon *components.TodoMVC) error {
+	for attempt := range maxUpdateAttempts {
+		mvc, err := s.todoStore.Get(ctx, key)
+		if errors.Is(err, ErrTodosNotFound) {
+			mvc, anon.Revision = anon, 0
+			mvc.Undo, mvc.Redo = nil, nil
+		} else if err != nil {
+			return err
+		} else {
+			s.mergeMVC(mvc, anon)
+		}
+
+		err = s.saveMVC(ctx, key, mvc)
+		if !errors.Is(err, ErrTodosConflict) {
+			return err
+		}
+		if err := backoff(ctx, attempt); err != nil {
+			return err
+		}
+	}
+	return fmt.Errorf("failed to save mvc after %d attempts: %w", maxUpdateAttempts, ErrTodosConflict)
+}
+
+// mergeMVC appends the todos from src that dst does not already have, so the
+// starter list is not duplicated every time someone logs in.
+func (s *TodoService) mergeMVC(dst, src *components.TodoMVC) {
+	for _, todo := range src.Todos {
+		exists := lo.ContainsBy(dst.Todos, func(t *components.Todo) bool {
+			return t.Text == todo.Text
+		})
+		if !exists {
+			dst.Todos = append(dst.Todos, todo)
+		}
 	}
-	mvc.EditingIdx = -1
+}
+
+// DeleteUserData deletes the personal todos of userID and the lists they own,
+// including their history, schedule and activity, and takes them off the
+// lists of others. It is called when the user deletes their account.
+func (s *TodoService) DeleteUserData(ctx context.Context, userID string) error {
+	listIDs, err := s.lists.DeleteUserLists(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	keys := []string{userKey(userID)}
+	for _, listID := range listIDs {
+		keys = append(keys, SharedListKey(listID))
+	}
+
+	stream, err := s.js.Stream(ctx, activityStream)
+	if err != nil {
+		return fmt.Errorf("failed to get activity stream: %w", err)
+	}
+
+	var errs []error
+	for _, key := range keys {
+		errs = append(errs, s.deleteList(ctx, stream, key))
+	}
+	return errors.Join(errs...)
+}
+
+// deleteList deletes the todos at key without leaving past revisions behind.
+func (s *TodoService) deleteList(ctx context.Context, stream jetstream.Stream, key string) error {
+	if err := s.todoStore.Delete(ctx, key); err != nil {
+		return err
+	}
+	if err := s.kv.Purge(ctx, key); err != nil {
+		return fmt.Errorf("failed to purge todos: %w", err)
+	}
+	if err := s.schedule.Purge(ctx, key); err != nil {
+		return fmt.Errorf("failed to purge schedule: %w", err)
+	}
+	if err := stream.Purge(ctx, jetstream.WithPurgeSubject(activitySubject(key))); err != nil {
+		return fmt.Errorf("failed to purge activity: %w", err)
+	}
+	return nil
+}
+
+func userKey(userID string) string {
+	return "users." + userID
 }
 
 func (s *TodoService) upsertSessionID(r *http.Request, w http.ResponseWriter) (string, error) {
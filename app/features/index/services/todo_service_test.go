@@ -0,0 +1,119 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"northstar/app/features/auth/gen/authdb"
+	"northstar/app/features/index/components"
+	"northstar/app/middleware"
+	"northstar/config"
+	"northstar/db"
+	"northstar/nats"
+
+	"github.com/gorilla/sessions"
+)
+
+// newTestTodoService starts a TodoService keeping todos in storage, with its
+// database and NATS data in a temporary directory.
+func newTestTodoService(t *testing.T, storage config.TodoStorage) *TodoService {
+	t.Helper()
+	t.Chdir(t.TempDir())
+
+	prev := config.Global.TodoStorage
+	config.Global.TodoStorage = storage
+	t.Cleanup(func() { config.Global.TodoStorage = prev })
+
+	database, err := db.InitDatabase()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { database.Close() })
+
+	ns, err := nats.SetupNATS(t.Context())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := NewTodoService(ns, sessions.NewCookieStore([]byte("test")), database, NewListService(database))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestUpdateMVCConcurrent(t *testing.T) {
+	const (
+		writers = 8
+		updates = 25
+	)
+
+	for _, storage := range []config.TodoStorage{config.TodoStorageKV, config.TodoStorageSQLite} {
+		t.Run(string(storage), func(t *testing.T) {
+			s := newTestTodoService(t, storage)
+			user := authdb.User{ID: "hammer", Username: "hammer"}
+			ctx := context.WithValue(t.Context(), middleware.UserContextKey, user)
+
+			// saved holds the todos whose update succeeded. Updates may give up
+			// with ErrTodosConflict, but must never report a save that is lost.
+			var (
+				wg    sync.WaitGroup
+				mu    sync.Mutex
+				saved = map[string]bool{}
+			)
+			for writer := range writers {
+				wg.Go(func() {
+					for update := range updates {
+						r := httptest.NewRequestWithContext(ctx, "PUT", "/api/todos", nil)
+						text := fmt.Sprintf("todo %d-%d", writer, update)
+						err := s.UpdateMVC(httptest.NewRecorder(), r, components.ActivityAdd, func(mvc *components.TodoMVC) {
+							s.EditTodo(mvc, "", components.Todo{Text: text})
+						})
+						if err != nil && !errors.Is(err, ErrTodosConflict) {
+							t.Errorf("UpdateMVC: %v", err)
+						}
+						if err == nil {
+							mu.Lock()
+							saved[text] = true
+							mu.Unlock()
+						}
+					}
+				})
+			}
+			wg.Wait()
+			if len(saved) == 0 {
+				t.Fatal("no update succeeded")
+			}
+
+			mvc, err := s.loadMVC(t.Context(), userKey(user.ID))
+			if err != nil {
+				t.Fatal(err)
+			}
+			found := map[string]bool{}
+			for _, todo := range mvc.Todos {
+				if strings.HasPrefix(todo.Text, "todo ") {
+					if found[todo.Text] {
+						t.Errorf("todo %q saved twice", todo.Text)
+					}
+					found[todo.Text] = true
+				}
+			}
+			for text := range saved {
+				if !found[text] {
+					t.Errorf("lost update %q", text)
+				}
+			}
+			for text := range found {
+				if !saved[text] {
+					t.Errorf("todo %q saved by an update that failed", text)
+				}
+			}
+			t.Logf("%d of %d updates saved", len(saved), writers*updates)
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"northstar/app/features/index/components"
+
+	"github.com/delaneyj/toolbelt"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+// TodoHistorySize is how many past revisions of a list are kept around for
+// undo and redo.
+const TodoHistorySize = 20
+
+var (
+	ErrTodosNotFound = errors.New("todos not found")
+	ErrTodosConflict = errors.New("todos were modified concurrently")
+)
+
+// TodoStore persists a TodoMVC per key. Implementations must also publish every
+// change to the "todos" bucket, which is what TodosSSE watches.
+//
+// Get sets mvc.Revision, and Put only succeeds if the stored revision still
+// matches it (zero meaning the key must not exist yet), returning
+// ErrTodosConflict otherwise. On success Put advances mvc.Revision.
+//
+// GetRevision returns an earlier state, as long as it is one of the last
+// TodoHistorySize revisions of the key.
+type TodoStore interface {
+	Get(ctx context.Context, key string) (*components.TodoMVC, error)
+	GetRevision(ctx context.Context, key string, revision uint64) (*components.TodoMVC, error)
+	Put(ctx context.Context, key string, mvc *components.TodoMVC) error
+	Delete(ctx context.Context, key string) error
+}
+
+type kvTodoStore struct {
+	kv jetstream.KeyValue
+}
+
+func NewKVTodoStore(kv jetstream.KeyValue) TodoStore {
+	return &kvTodoStore{kv: kv}
+}
+
+func (s *kvTodoStore) Get(ctx context.Context, key string) (*components.TodoMVC, error) {
+	entry, err := s.kv.Get(ctx, key)
+	if err != nil {
+		if errors.Is(err, jetstream.ErrKeyNotFound) {
+			return nil, ErrTodosNotFound
+		}
+		return nil, fmt.Errorf("failed to get key value: %w", err)
+	}
+
+	mvc, err := DecodeMVC(entry.Value())
+	if err != nil {
+		return nil, err
+	}
+	mvc.Revision = entry.Revision()
+	return mvc, nil
+}
+
+func (s *kvTodoStore) GetRevision(ctx context.Context, key string, revision uint64) (*components.TodoMVC, error) {
+	entry, err := s.kv.GetRevision(ctx, key, revision)
+	if err != nil {
+		if errors.Is(err, jetstream.ErrKeyNotFound) || errors.Is(err, jetstream.ErrKeyDeleted) {
+			return nil, ErrTodosNotFound
+		}
+		return nil, fmt.Errorf("failed to get key value revision: %w", err)
+	}
+
+	mvc, err := DecodeMVC(entry.Value())
+	if err != nil {
+		return nil, err
+	}
+	mvc.Revision = entry.Revision()
+	return mvc, nil
+}
+
+func (s *kvTodoStore) Put(ctx context.Context, key string, mvc *components.TodoMVC) error {
+	b, err := json.Marshal(mvc)
+	if err != nil {
+		return fmt.Errorf("failed to marshal mvc: %w", err)
+	}
+
+	var revision uint64
+	if mvc.Revision == 0 {
+		revision, err = s.kv.Create(ctx, key, b)
+	} else {
+		revision, err = s.kv.Update(ctx, key, b, mvc.Revision)
+	}
+	if err != nil {
+		if IsWrongLastSequence(err) {
+			return ErrTodosConflict
+		}
+		return fmt.Errorf("failed to put key value: %w", err)
+	}
+
+	mvc.Revision = revision
+	return nil
+}
+
+func (s *kvTodoStore) Delete(ctx context.Context, key string) error {
+	if err := s.kv.Delete(ctx, key); err != nil && !errors.Is(err, jetstream.ErrKeyNotFound) {
+		return fmt.Errorf("failed to delete key value: %w", err)
+	}
+	return nil
+}
+
+// DecodeMVC unmarshals a stored TodoMVC. Lists saved before todos had IDs
+// addressed them by index, so missing IDs are assigned here.
+func DecodeMVC(b []byte) (*components.TodoMVC, error) {
+	var mvc components.TodoMVC
+	if err := json.Unmarshal(b, &mvc); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal mvc: %w", err)
+	}
+
+	mvc.Walk(func(todo *components.Todo) {
+		if todo.ID == "" {
+			todo.ID = toolbelt.NextEncodedID()
+		}
+	})
+
+	return &mvc, nil
+}
+
+// IsWrongLastSequence reports whether a conditional KV write lost a race with
+// another writer to the same key.
+func IsWrongLastSequence(err error) bool {
+	var apiErr *jetstream.APIError
+	return errors.As(err, &apiErr) && apiErr.ErrorCode == jetstream.JSErrCodeStreamWrongLastSequence
+}
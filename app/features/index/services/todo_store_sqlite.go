@@ -0,0 +1,225 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"northstar/app/features/index/components"
+	"northstar/app/features/index/gen/tododb"
+
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+const (
+	maxMirrorAttempts = 5
+	mirrorRetryDelay  = 100 * time.Millisecond
+)
+
+// sqliteTodoStore keeps todos in the database so they outlive the bucket's TTL.
+// Writes are mirrored into the bucket to notify watchers.
+type sqliteTodoStore struct {
+	db      *sql.DB
+	queries *tododb.Queries
+	kv      jetstream.KeyValue
+
+	// mu numbers the database writes in the order they commit.
+	mu  sync.Mutex
+	seq uint64
+
+	// mirrorMu guards mirrored, the number of the last write mirrored into
+	// the bucket for each key. Mirrors run after mu is released, so a slow
+	// bucket never holds up the database.
+	mirrorMu sync.Mutex
+	mirrored map[string]uint64
+}
+
+func NewSQLiteTodoStore(db *sql.DB, kv jetstream.KeyValue) TodoStore {
+	return &sqliteTodoStore{
+		db:       db,
+		queries:  tododb.New(db),
+		kv:       kv,
+		mirrored: make(map[string]uint64),
+	}
+}
+
+func (s *sqliteTodoStore) Get(ctx context.Context, key string) (*components.TodoMVC, error) {
+	list, err := s.queries.GetTodoList(ctx, key)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrTodosNotFound
+		}
+		return nil, fmt.Errorf("failed to get todo list: %w", err)
+	}
+
+	mvc, err := DecodeMVC([]byte(list.Data))
+	if err != nil {
+		return nil, err
+	}
+	mvc.Revision = uint64(list.Revision)
+	return mvc, nil
+}
+
+func (s *sqliteTodoStore) Put(ctx context.Context, key string, mvc *components.TodoMVC) error {
+	b, err := json.Marshal(mvc)
+	if err != nil {
+		return fmt.Errorf("failed to marshal mvc: %w", err)
+	}
+
+	seq, err := s.write(ctx, func(queries *tododb.Queries) error {
+		return s.save(ctx, queries, key, mvc, b)
+	})
+	if err != nil {
+		return err
+	}
+	mvc.Revision++
+
+	s.mirror(ctx, key, seq, func(ctx context.Context) error {
+		_, err := s.kv.Put(ctx, key, b)
+		return err
+	})
+	return nil
+}
+
+// save writes the list and a revision of it to its history. The list must
+// still be at mvc.Revision.
+func (s *sqliteTodoStore) save(ctx context.Context, queries *tododb.Queries, key string, mvc *components.TodoMVC, b []byte) error {
+	var (
+		rows int64
+		err  error
+	)
+	if mvc.Revision == 0 {
+		rows, err = queries.CreateTodoList(ctx, tododb.CreateTodoListParams{
+			ID:   key,
+			Data: string(b),
+		})
+	} else {
+		rows, err = queries.UpdateTodoList(ctx, tododb.UpdateTodoListParams{
+			Data:     string(b),
+			ID:       key,
+			Revision: int64(mvc.Revision),
+		})
+	}
+	if err != nil {
+		return fmt.Errorf("failed to save todo list: %w", err)
+	}
+	if rows == 0 {
+		return ErrTodosConflict
+	}
+	revision := int64(mvc.Revision) + 1
+
+	if err := queries.CreateTodoListRevision(ctx, tododb.CreateTodoListRevisionParams{
+		ListID:   key,
+		Revision: revision,
+		Data:     string(b),
+	}); err != nil {
+		return fmt.Errorf("failed to save todo list revision: %w", err)
+	}
+	if err := queries.PruneTodoListHistory(ctx, tododb.PruneTodoListHistoryParams{
+		ListID:   key,
+		Revision: revision - TodoHistorySize,
+	}); err != nil {
+		return fmt.Errorf("failed to prune todo list history: %w", err)
+	}
+	return nil
+}
+
+// write runs fn in a transaction and returns the number of the write once it
+// has committed.
+func (s *sqliteTodoStore) write(ctx context.Context, fn func(queries *tododb.Queries) error) (uint64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := fn(s.queries.WithTx(tx)); err != nil {
+		return 0, err
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	s.seq++
+	return s.seq, nil
+}
+
+// mirror applies write seq of the list at key to the bucket with op, so
+// watchers see it. The database already holds the write, so the bucket is
+// only a notification: failures are retried a few times and then logged
+// instead of failing the write. A write is dropped once a later one of the
+// same list has been mirrored.
+func (s *sqliteTodoStore) mirror(ctx context.Context, key string, seq uint64, op func(ctx context.Context) error) {
+	ctx = context.WithoutCancel(ctx)
+	for attempt := 1; ; attempt++ {
+		s.mirrorMu.Lock()
+		if s.mirrored[key] >= seq {
+			s.mirrorMu.Unlock()
+			return
+		}
+		err := op(ctx)
+		if err == nil {
+			s.mirrored[key] = seq
+		}
+		s.mirrorMu.Unlock()
+
+		if err == nil {
+			return
+		}
+		if attempt == maxMirrorAttempts {
+			slog.Error("failed to mirror todo list", "key", key, "attempts", attempt, "error", err)
+			return
+		}
+		time.Sleep(time.Duration(attempt) * mirrorRetryDelay)
+	}
+}
+
+func (s *sqliteTodoStore) GetRevision(ctx context.Context, key string, revision uint64) (*components.TodoMVC, error) {
+	list, err := s.queries.GetTodoListRevision(ctx, tododb.GetTodoListRevisionParams{
+		ListID:   key,
+		Revision: int64(revision),
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrTodosNotFound
+		}
+		return nil, fmt.Errorf("failed to get todo list revision: %w", err)
+	}
+
+	mvc, err := DecodeMVC([]byte(list.Data))
+	if err != nil {
+		return nil, err
+	}
+	mvc.Revision = revision
+	return mvc, nil
+}
+
+func (s *sqliteTodoStore) Delete(ctx context.Context, key string) error {
+	seq, err := s.write(ctx, func(queries *tododb.Queries) error {
+		if err := queries.DeleteTodoList(ctx, key); err != nil {
+			return fmt.Errorf("failed to delete todo list: %w", err)
+		}
+		if err := queries.DeleteTodoListHistory(ctx, key); err != nil {
+			return fmt.Errorf("failed to delete todo list history: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	s.mirror(ctx, key, seq, func(ctx context.Context) error {
+		if err := s.kv.Delete(ctx, key); err != nil && !errors.Is(err, jetstream.ErrKeyNotFound) {
+			return err
+		}
+		return nil
+	})
+	return nil
+}
@@ -0,0 +1,58 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"northstar/app/features/index/components"
+	"northstar/config"
+
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+func TestSQLiteMirrorKeepsWriteOrder(t *testing.T) {
+	store := newTestTodoService(t, config.TodoStorageSQLite).todoStore.(*sqliteTodoStore)
+	ctx, key := t.Context(), "ordered"
+	put := func(value string) func(ctx context.Context) error {
+		return func(ctx context.Context) error {
+			_, err := store.kv.Put(ctx, key, []byte(value))
+			return err
+		}
+	}
+
+	// the later write got to the bucket first, so the earlier one is dropped
+	store.mirror(ctx, key, 2, put("later"))
+	store.mirror(ctx, key, 1, put("earlier"))
+
+	entry, err := store.kv.Get(ctx, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(entry.Value()); got != "later" {
+		t.Errorf("bucket holds %q, want %q", got, "later")
+	}
+}
+
+func TestSQLiteDelete(t *testing.T) {
+	store := newTestTodoService(t, config.TodoStorageSQLite).todoStore
+	ctx, key := t.Context(), "deleted"
+
+	mvc := &components.TodoMVC{Todos: []*components.Todo{{ID: "a", Text: "A"}}}
+	if err := store.Put(ctx, key, mvc); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Delete(ctx, key); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := store.Get(ctx, key); !errors.Is(err, ErrTodosNotFound) {
+		t.Errorf("Get after Delete: %v, want ErrTodosNotFound", err)
+	}
+	if _, err := store.GetRevision(ctx, key, 1); !errors.Is(err, ErrTodosNotFound) {
+		t.Errorf("GetRevision after Delete: %v, want ErrTodosNotFound", err)
+	}
+	if _, err := store.(*sqliteTodoStore).kv.Get(ctx, key); !errors.Is(err, jetstream.ErrKeyNotFound) {
+		t.Errorf("bucket after Delete: %v, want ErrKeyNotFound", err)
+	}
+}
@@ -0,0 +1,443 @@
+package services
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"northstar/app/features/index/components"
+
+	"github.com/delaneyj/toolbelt"
+)
+
+// TodoFormat is a file format todos can be exported to and imported from.
+type TodoFormat string
+
+const (
+	TodoFormatJSON     TodoFormat = "json"
+	TodoFormatCSV      TodoFormat = "csv"
+	TodoFormatMarkdown TodoFormat = "md"
+)
+
+// MaxImportTodos caps how many todos a single import may contain.
+const MaxImportTodos = 1000
+
+var ErrInvalidImport = errors.New("invalid import")
+
+// csvHeader lists the exported columns. Subtasks follow their parent with a
+// depth one greater.
+var csvHeader = []string{"text", "completed", "due", "priority", "tags", "recurrence", "depth"}
+
+// markdownTodo matches a checklist item such as "- [x] Buy milk". Subtasks
+// are indented below their parent.
+var markdownTodo = regexp.MustCompile(`^(\s*)[-*+]\s+\[([ xX])\]\s+(.*)$`)
+
+// markdownSuffix and markdownEscapedSuffix find the words of a todo text that
+// look like due:, !priority, every: or #tag suffixes, unescaped and escaped
+// with a backslash.
+var (
+	markdownSuffix        = regexp.MustCompile(`(^|[\s\x{85}\p{Z}])(\\*(?:#|!|due:|every:))`)
+	markdownEscapedSuffix = regexp.MustCompile(`(^|[\s\x{85}\p{Z}])\\(\\*(?:#|!|due:|every:))`)
+)
+
+func ParseTodoFormat(s string) (TodoFormat, error) {
+	switch format := TodoFormat(strings.ToLower(strings.TrimPrefix(s, "."))); format {
+	case TodoFormatJSON, TodoFormatCSV, TodoFormatMarkdown:
+		return format, nil
+	case "markdown":
+		return TodoFormatMarkdown, nil
+	default:
+		return "", fmt.Errorf("unsupported format %q", s)
+	}
+}
+
+func (f TodoFormat) ContentType() string {
+	switch f {
+	case TodoFormatCSV:
+		return "text/csv; charset=utf-8"
+	case TodoFormatMarkdown:
+		return "text/markdown; charset=utf-8"
+	default:
+		return "application/json"
+	}
+}
+
+// ExportTodos writes todos to w in the given format.
+func ExportTodos(w io.Writer, format TodoFormat, todos []*components.Todo) error {
+	switch format {
+	case TodoFormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(todos)
+	case TodoFormatCSV:
+		cw := csv.NewWriter(w)
+		if err := cw.Write(csvHeader); err != nil {
+			return err
+		}
+		if err := walkTodos(todos, 0, func(todo *components.Todo, depth int) error {
+			return cw.Write([]string{
+				todo.Text,
+				strconv.FormatBool(todo.Completed),
+				todo.Due,
+				priorityName(todo.Priority),
+				strings.Join(todo.Tags, ", "),
+				todo.Recurrence,
+				strconv.Itoa(depth),
+			})
+		}); err != nil {
+			return err
+		}
+		cw.Flush()
+		return cw.Error()
+	case TodoFormatMarkdown:
+		return walkTodos(todos, 0, func(todo *components.Todo, depth int) error {
+			check := " "
+			if todo.Completed {
+				check = "x"
+			}
+			line := fmt.Sprintf("%s- [%s] %s", strings.Repeat("  ", depth), check, escapeMarkdownText(todo.Text))
+			if todo.Due != "" {
+				line += " due:" + todo.Due
+			}
+			if todo.Priority != components.TodoPriorityNone {
+				line += " !" + priorityName(todo.Priority)
+			}
+			if todo.Recurrence != "" {
+				// cron rules contain spaces, which would split the suffix
+				line += " every:" + strings.ReplaceAll(todo.Recurrence, " ", "_")
+			}
+			for _, tag := range todo.Tags {
+				line += " #" + markdownTag(tag)
+			}
+			_, err := fmt.Fprintln(w, line)
+			return err
+		})
+	default:
+		return fmt.Errorf("unsupported format %q", format)
+	}
+}
+
+// ImportTodos reads todos in the given format from r. Every todo is
+// validated and given a fresh ID, so imports never collide with existing
+// todos. Errors wrap ErrInvalidImport when the input itself is at fault.
+func ImportTodos(r io.Reader, format TodoFormat) ([]*components.Todo, error) {
+	var (
+		todos []*components.Todo
+		err   error
+	)
+	switch format {
+	case TodoFormatJSON:
+		todos, err = importJSON(r)
+	case TodoFormatCSV:
+		todos, err = importCSV(r)
+	case TodoFormatMarkdown:
+		todos, err = importMarkdown(r)
+	default:
+		return nil, fmt.Errorf("unsupported format %q", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	count := 0
+	if err := walkTodos(todos, 0, func(todo *components.Todo, depth int) error {
+		if count++; count > MaxImportTodos {
+			return fmt.Errorf("%w: more than %d todos", ErrInvalidImport, MaxImportTodos)
+		}
+		if err := validateImportedTodo(todo); err != nil {
+			return fmt.Errorf("%w: todo %d: %v", ErrInvalidImport, count, err)
+		}
+		if depth > 0 {
+			// only top level todos recur
+			todo.Recurrence = ""
+		}
+		todo.ID = toolbelt.NextEncodedID()
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	syncCompleted(todos)
+	return todos, nil
+}
+
+// importJSON accepts both the exported array and a whole TodoMVC as stored in
+// the todos bucket.
+func importJSON(r io.Reader) ([]*components.Todo, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var todos []*components.Todo
+	if b = bytes.TrimSpace(b); len(b) > 0 && b[0] == '{' {
+		var mvc components.TodoMVC
+		err = json.Unmarshal(b, &mvc)
+		todos = mvc.Todos
+	} else {
+		err = json.Unmarshal(b, &todos)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidImport, err)
+	}
+	if err := walkTodos(todos, 0, func(todo *components.Todo, _ int) error {
+		if slices.Contains(todo.Children, nil) {
+			return fmt.Errorf("%w: subtask of %q is null", ErrInvalidImport, todo.Text)
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	if slices.Contains(todos, nil) {
+		return nil, fmt.Errorf("%w: todo is null", ErrInvalidImport)
+	}
+	return todos, nil
+}
+
+func importCSV(r io.Reader) ([]*components.Todo, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidImport, err)
+	}
+	columns := map[string]int{}
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	if _, ok := columns["text"]; !ok {
+		return nil, fmt.Errorf("%w: missing text column", ErrInvalidImport)
+	}
+
+	var todos []*components.Todo
+	for {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			return todos, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidImport, err)
+		}
+		line, _ := cr.FieldPos(0)
+		field := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		todo := &components.Todo{
+			Text:       field("text"),
+			Due:        field("due"),
+			Tags:       ParseTags(field("tags")),
+			Recurrence: field("recurrence"),
+		}
+		if s := field("completed"); s != "" {
+			if todo.Completed, err = strconv.ParseBool(s); err != nil {
+				return nil, fmt.Errorf("%w: line %d: invalid completed value %q", ErrInvalidImport, line, s)
+			}
+		}
+		if todo.Priority, err = parsePriority(field("priority")); err != nil {
+			return nil, fmt.Errorf("%w: line %d: %v", ErrInvalidImport, line, err)
+		}
+
+		depth := 0
+		if s := field("depth"); s != "" {
+			if depth, err = strconv.Atoi(s); err != nil || depth < 0 {
+				return nil, fmt.Errorf("%w: line %d: invalid depth %q", ErrInvalidImport, line, s)
+			}
+		}
+		if todos, err = nestTodo(todos, todo, depth); err != nil {
+			return nil, fmt.Errorf("%w: line %d: %v", ErrInvalidImport, line, err)
+		}
+	}
+}
+
+// importMarkdown reads checklist items, picking up the due:, !priority,
+// every: and #tag suffixes written by ExportTodos. Other lines are ignored so a whole
+// document can be pasted in.
+func importMarkdown(r io.Reader) ([]*components.Todo, error) {
+	var todos []*components.Todo
+	scanner := bufio.NewScanner(r)
+	var indents []int
+	for line := 1; scanner.Scan(); line++ {
+		m := markdownTodo.FindStringSubmatch(scanner.Text())
+		if m == nil {
+			continue
+		}
+
+		// the depth is how many of the enclosing items are indented less
+		indent := len(strings.ReplaceAll(m[1], "\t", "    "))
+		for len(indents) > 0 && indents[len(indents)-1] >= indent {
+			indents = indents[:len(indents)-1]
+		}
+		depth := len(indents)
+		indents = append(indents, indent)
+
+		// suffixes are cut off the end, leaving the spacing of the text alone
+		todo := &components.Todo{Completed: m[2] != " "}
+		rest := m[3]
+	suffixes:
+		for {
+			rest = strings.TrimRightFunc(rest, unicode.IsSpace)
+			text, word := lastMarkdownWord(rest)
+			switch {
+			case strings.HasPrefix(word, "#") && len(word) > 1:
+				tag := word[1:]
+				if unquoted, err := strconv.Unquote(tag); err == nil {
+					tag = unquoted
+				}
+				todo.Tags = append(ParseTags(tag), todo.Tags...)
+			case strings.HasPrefix(word, "due:") && todo.Due == "":
+				todo.Due = strings.TrimPrefix(word, "due:")
+			case strings.HasPrefix(word, "every:") && todo.Recurrence == "":
+				todo.Recurrence = strings.ReplaceAll(strings.TrimPrefix(word, "every:"), "_", " ")
+			case strings.HasPrefix(word, "!") && todo.Priority == components.TodoPriorityNone && isPriority(word[1:]):
+				todo.Priority, _ = parsePriority(word[1:])
+			default:
+				break suffixes
+			}
+			rest = text
+		}
+		todo.Text = unescapeMarkdownText(rest)
+
+		var err error
+		if todos, err = nestTodo(todos, todo, depth); err != nil {
+			return nil, fmt.Errorf("%w: line %d: %v", ErrInvalidImport, line, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidImport, err)
+	}
+	return todos, nil
+}
+
+// walkTodos calls fn for every todo and subtask, parents first, stopping at
+// the first error.
+func walkTodos(todos []*components.Todo, depth int, fn func(todo *components.Todo, depth int) error) error {
+	for _, todo := range todos {
+		if todo == nil {
+			continue
+		}
+		if err := fn(todo, depth); err != nil {
+			return err
+		}
+		if err := walkTodos(todo.Children, depth+1, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// nestTodo appends todo at depth below the last todo of the level above.
+func nestTodo(todos []*components.Todo, todo *components.Todo, depth int) ([]*components.Todo, error) {
+	if depth == 0 {
+		return append(todos, todo), nil
+	}
+	if len(todos) == 0 {
+		return nil, errors.New("subtask without a parent")
+	}
+	parent := todos[len(todos)-1]
+	children, err := nestTodo(parent.Children, todo, depth-1)
+	if err != nil {
+		return nil, err
+	}
+	parent.Children = children
+	return todos, nil
+}
+
+// escapeMarkdownText keeps words in the todo text that look like due:,
+// !priority, every: or #tag suffixes from being read back as such.
+func escapeMarkdownText(text string) string {
+	return markdownSuffix.ReplaceAllString(text, `$1\$2`)
+}
+
+func unescapeMarkdownText(text string) string {
+	return markdownEscapedSuffix.ReplaceAllString(text, "$1$2")
+}
+
+// markdownTag quotes tags that would not read back as a single word.
+func markdownTag(tag string) string {
+	if strings.ContainsFunc(tag, unicode.IsSpace) || strings.HasPrefix(tag, `"`) {
+		return strconv.Quote(tag)
+	}
+	return tag
+}
+
+// lastMarkdownWord splits the last word off s, taking a tag quoted by
+// markdownTag as one word.
+func lastMarkdownWord(s string) (rest, word string) {
+	if i := strings.LastIndex(s, `#"`); i >= 0 && strings.HasSuffix(s, `"`) {
+		before, _ := utf8.DecodeLastRuneInString(s[:i])
+		if _, err := strconv.Unquote(s[i+1:]); err == nil && (i == 0 || unicode.IsSpace(before)) {
+			return s[:i], s[i:]
+		}
+	}
+	i := strings.LastIndexFunc(s, unicode.IsSpace)
+	return s[:i+1], s[i+1:]
+}
+
+func validateImportedTodo(todo *components.Todo) error {
+	todo.Text = strings.TrimSpace(todo.Text)
+	if todo.Text == "" {
+		return errors.New("text is empty")
+	}
+	if todo.Due != "" {
+		if _, err := time.Parse(components.TodoDateLayout, todo.Due); err != nil {
+			return fmt.Errorf("invalid due date %q", todo.Due)
+		}
+	}
+	if todo.Priority < components.TodoPriorityNone || todo.Priority >= components.TodoPriorityLast {
+		return fmt.Errorf("invalid priority %d", todo.Priority)
+	}
+	todo.Tags = ParseTags(strings.Join(todo.Tags, ","))
+	recurrence, err := ParseRecurrence(todo.Recurrence)
+	if err != nil {
+		return err
+	}
+	todo.Recurrence = recurrence
+	return nil
+}
+
+func priorityName(p components.TodoPriority) string {
+	if p <= components.TodoPriorityNone || p >= components.TodoPriorityLast {
+		return ""
+	}
+	return strings.ToLower(components.TodoPriorityStrings[p])
+}
+
+func isPriority(s string) bool {
+	_, err := parsePriority(s)
+	return s != "" && err == nil
+}
+
+// parsePriority accepts either a priority name or its number.
+func parsePriority(s string) (components.TodoPriority, error) {
+	if s == "" {
+		return components.TodoPriorityNone, nil
+	}
+	for i, name := range components.TodoPriorityStrings {
+		if strings.EqualFold(s, name) {
+			return components.TodoPriority(i), nil
+		}
+	}
+	if n, err := strconv.Atoi(s); err == nil && n >= int(components.TodoPriorityNone) && n < int(components.TodoPriorityLast) {
+		return components.TodoPriority(n), nil
+	}
+	return 0, fmt.Errorf("invalid priority %q", s)
+}
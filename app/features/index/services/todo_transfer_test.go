@@ -0,0 +1,206 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"northstar/app/features/auth/gen/authdb"
+	"northstar/app/features/index/components"
+	"northstar/app/middleware"
+	"northstar/config"
+)
+
+func TestTransferRoundTrip(t *testing.T) {
+	todos := []*components.Todo{
+		{
+			Text:       "Water  the   plants",
+			Due:        "2026-05-01",
+			Priority:   components.TodoPriorityHigh,
+			Tags:       []string{"home chores", "garden"},
+			Recurrence: "0 9 * * 1-5",
+			Children: []*components.Todo{
+				{Text: "Fill the can", Completed: true, Tags: []string{"quick"}},
+				{Text: "Back\tporch", Due: "2026-05-02"},
+			},
+		},
+		{
+			Text:     `Read #notatag due:soon !high every:day \#escaped`,
+			Priority: components.TodoPriorityLow,
+			Tags:     []string{`"quoted"`, `back\slash`},
+		},
+		{Text: `Call mum #"hi"`, Completed: true, Recurrence: "weekly"},
+		{Text: "Done", Completed: true},
+	}
+
+	for _, format := range []TodoFormat{TodoFormatJSON, TodoFormatCSV, TodoFormatMarkdown} {
+		t.Run(string(format), func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := ExportTodos(&buf, format, todos); err != nil {
+				t.Fatal(err)
+			}
+			exported := buf.String()
+
+			imported, err := ImportTodos(&buf, format)
+			if err != nil {
+				t.Fatalf("import: %v\n%s", err, exported)
+			}
+			if got, want := transferJSON(t, imported), transferJSON(t, todos); got != want {
+				t.Errorf("round trip changed the todos\nexported:\n%s\ngot:  %s\nwant: %s", exported, got, want)
+			}
+		})
+	}
+}
+
+func TestImportMarkdownSuffixes(t *testing.T) {
+	in := "# Groceries\n\n" +
+		"- [ ] Buy  milk due:2026-01-02 !medium #\"home chores\" #shop\n" +
+		"  * [X] Oat milk\n" +
+		"  + [ ] Soy milk\n" +
+		"Some notes\n"
+
+	todos, err := ImportTodos(bytes.NewBufferString(in), TodoFormatMarkdown)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*components.Todo{{
+		Text:     "Buy  milk",
+		Due:      "2026-01-02",
+		Priority: components.TodoPriorityMedium,
+		Tags:     []string{"home chores", "shop"},
+		Children: []*components.Todo{
+			{Text: "Oat milk", Completed: true},
+			{Text: "Soy milk"},
+		},
+	}}
+	if got, want := transferJSON(t, todos), transferJSON(t, want); got != want {
+		t.Errorf("got  %s\nwant %s", got, want)
+	}
+}
+
+// transferJSON marshals todos without their IDs, which imports replace.
+func transferJSON(t *testing.T, todos []*components.Todo) string {
+	t.Helper()
+	var withoutIDs func(todos []*components.Todo) []*components.Todo
+	withoutIDs = func(todos []*components.Todo) []*components.Todo {
+		var cleared []*components.Todo
+		for _, todo := range todos {
+			copied := *todo
+			copied.ID = ""
+			copied.Children = withoutIDs(todo.Children)
+			cleared = append(cleared, &copied)
+		}
+		return cleared
+	}
+	b, err := json.Marshal(withoutIDs(todos))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+func TestImportThroughService(t *testing.T) {
+	for _, storage := range []config.TodoStorage{config.TodoStorageKV, config.TodoStorageSQLite} {
+		t.Run(string(storage), func(t *testing.T) {
+			s := newTestTodoService(t, storage)
+			user := authdb.User{ID: "importer", Username: "importer"}
+			ctx := context.WithValue(t.Context(), middleware.UserContextKey, user)
+
+			// load reads the list back the way the handlers do.
+			load := func() *components.TodoMVC {
+				t.Helper()
+				_, mvc, err := s.GetSessionMVC(httptest.NewRecorder(), httptest.NewRequestWithContext(ctx, "GET", "/", nil))
+				if err != nil {
+					t.Fatal(err)
+				}
+				return mvc
+			}
+			// importTodos imports in into the list as the import handler does.
+			importTodos := func(format TodoFormat, in string, replace bool) []*components.Todo {
+				t.Helper()
+				todos, err := ImportTodos(bytes.NewBufferString(in), format)
+				if err != nil {
+					t.Fatal(err)
+				}
+				r := httptest.NewRequestWithContext(ctx, "POST", "/import", nil)
+				if err := s.UpdateMVC(httptest.NewRecorder(), r, components.ActivityImport, func(mvc *components.TodoMVC) {
+					s.AddTodos(mvc, todos, replace)
+				}); err != nil {
+					t.Fatal(err)
+				}
+				return todos
+			}
+			export := func(format TodoFormat, todos []*components.Todo) string {
+				t.Helper()
+				var buf bytes.Buffer
+				if err := ExportTodos(&buf, format, todos); err != nil {
+					t.Fatal(err)
+				}
+				return buf.String()
+			}
+
+			// Replacing the list with its own export keeps the todos but gives
+			// every one of them a new ID.
+			before := load().Todos
+			if len(before) == 0 {
+				t.Fatal("new list has no todos to export")
+			}
+			importTodos(TodoFormatJSON, export(TodoFormatJSON, before), true)
+			replaced := load().Todos
+			if got, want := transferJSON(t, replaced), transferJSON(t, before); got != want {
+				t.Errorf("replace changed the todos\ngot:  %s\nwant: %s", got, want)
+			}
+			oldIDs := map[string]bool{}
+			walkTodos(before, 0, func(todo *components.Todo, _ int) error {
+				oldIDs[todo.ID] = true
+				return nil
+			})
+			walkTodos(replaced, 0, func(todo *components.Todo, _ int) error {
+				if todo.ID == "" || oldIDs[todo.ID] {
+					t.Errorf("todo %q kept ID %q", todo.Text, todo.ID)
+				}
+				return nil
+			})
+
+			// Appending keeps the existing todos and adds the imported ones
+			// after them.
+			appended := importTodos(TodoFormatMarkdown, "- [ ] Buy milk #shop\n  - [x] Oat milk\n", false)
+			mvc := load()
+			want := append(slices.Clone(replaced), appended...)
+			if got, want := export(TodoFormatCSV, mvc.Todos), export(TodoFormatCSV, want); got != want {
+				t.Errorf("append changed the todos\ngot:\n%s\nwant:\n%s", got, want)
+			}
+			for i, todo := range replaced {
+				if mvc.Todos[i].ID != todo.ID {
+					t.Errorf("append changed the ID of %q", todo.Text)
+				}
+			}
+		})
+	}
+}
+
+func TestImportLimit(t *testing.T) {
+	var in strings.Builder
+	for i := range MaxImportTodos {
+		fmt.Fprintf(&in, "- [ ] todo %d\n", i)
+	}
+	todos, err := ImportTodos(strings.NewReader(in.String()), TodoFormatMarkdown)
+	if err != nil {
+		t.Fatalf("importing %d todos: %v", MaxImportTodos, err)
+	}
+	if len(todos) != MaxImportTodos {
+		t.Fatalf("imported %d todos, want %d", len(todos), MaxImportTodos)
+	}
+
+	// Subtasks count towards the limit too.
+	in.WriteString("  - [ ] one too many\n")
+	if _, err := ImportTodos(strings.NewReader(in.String()), TodoFormatMarkdown); !errors.Is(err, ErrInvalidImport) {
+		t.Errorf("importing %d todos: got %v, want ErrInvalidImport", MaxImportTodos+1, err)
+	}
+}
@@ -1,4 +1,4 @@
-import { LitElement, html } from 'lit'
+import { LitElement, html, nothing } from 'lit'
 import { customElement, property, query } from 'lit/decorators.js'
 import Sortable from 'sortablejs'
 
@@ -6,6 +6,10 @@ interface SortableItem {
   name: string
 }
 
+// Sorts the items it renders from `items`. With `sort-items` set it renders
+// nothing and sorts the children of the server rendered list it wraps instead,
+// so Datastar keeps patching them; each of those needs a data-id, and `change`
+// tells which one moved and which one it now sits in front of.
 @customElement('sortable-example')
 export class SortableExample extends LitElement {
   @query('#sortable-container')
@@ -14,9 +18,32 @@ export class SortableExample extends LitElement {
   @property({ type: String }) title: string = ''
   @property({ type: String }) value: string = ''
   @property({ type: Array }) items: SortableItem[] = []
+  @property({ type: String, attribute: 'sort-items' }) sortItems: string = ''
+
+  private sortable?: Sortable
+
+  connectedCallback() {
+    super.connectedCallback()
+    if (this.hasUpdated) this.setupSortable()
+  }
+
+  disconnectedCallback() {
+    super.disconnectedCallback()
+    this.sortable?.destroy()
+    this.sortable = undefined
+  }
 
   firstUpdated() {
-    new Sortable(this.sortContainer, {
+    this.setupSortable()
+  }
+
+  private setupSortable() {
+    if (this.sortItems) {
+      this.sortWrappedList()
+      return
+    }
+
+    this.sortable = new Sortable(this.sortContainer, {
       animation: 150,
       ghostClass: 'opacity-25',
       onEnd: (evt) => {
@@ -30,11 +57,39 @@ export class SortableExample extends LitElement {
     })
   }
 
+  private sortWrappedList() {
+    const container = this.firstElementChild as HTMLElement | null
+    if (!container) return
+
+    const draggable = this.sortItems
+    this.sortable = new Sortable(container, {
+      animation: 150,
+      ghostClass: 'opacity-25',
+      draggable,
+      onEnd: (evt) => {
+        if (evt.oldIndex === evt.newIndex) return
+
+        let before = evt.item.nextElementSibling as HTMLElement | null
+        while (before && !(before.matches(draggable) && before.dataset.id)) {
+          before = before.nextElementSibling as HTMLElement | null
+        }
+
+        this.dispatchEvent(
+          new CustomEvent('change', {
+            detail: { id: evt.item.dataset.id, before: before?.dataset.id ?? '' },
+          }),
+        )
+      },
+    })
+  }
+
   protected createRenderRoot() {
     return this
   }
 
   render() {
+    if (this.sortItems) return nothing
+
     console.log(this)
     return html`
       <div class="sortable-wrapper">
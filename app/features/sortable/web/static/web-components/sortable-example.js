@@ -1,55 +1,36 @@
-var Fn=Object.defineProperty;var Bn=Object.getOwnPropertyDescriptor;var vt=(i,t,e,n)=>{for(var o=n>1?void 0:n?Bn(t,e):t,r=i.length-1,a;r>=0;r--)(a=i[r])&&(o=(n?a(t,e,o):a(o))||o);return n&&o&&Fn(t,e,o),o};var Vt=globalThis,Jt=Vt.ShadowRoot&&(Vt.ShadyCSS===void 0||Vt.ShadyCSS.nativeShadow)&&"adoptedStyleSheets"in Document.prototype&&"replace"in CSSStyleSheet.prototype,Ge=Symbol(),qe=new WeakMap,Zt=class{constructor(t,e,n){if(this._$cssResult$=!0,n!==Ge)throw Error("CSSResult is not constructable. Use `unsafeCSS` or `css` instead.");this.cssText=t,this.t=e}get styleSheet(){let t=this.o,e=this.t;if(Jt&&t===void 0){let n=e!==void 0&&e.length===1;n&&(t=qe.get(e)),t===void 0&&((this.o=t=new CSSStyleSheet).replaceSync(this.cssText),n&&qe.set(e,t))}return t}toString(){return this.cssText}},Ke=i=>new Zt(typeof i=="string"?i:i+"",void 0,Ge);var Ve=(i,t)=>{if(Jt)i.adoptedStyleSheets=t.map((e=>e instanceof CSSStyleSheet?e:e.styleSheet));else for(let e of t){let n=document.createElement("style"),o=Vt.litNonce;o!==void 0&&n.setAttribute("nonce",o),n.textContent=e.cssText,i.appendChild(n)}},ve=Jt?i=>i:i=>i instanceof CSSStyleSheet?(t=>{let e="";for(let n of t.cssRules)e+=n.cssText;return Ke(e)})(i):i;var{is:Ln,defineProperty:jn,getOwnPropertyDescriptor:Xn,getOwnPropertyNames:Yn,getOwnPropertySymbols:zn,getPrototypeOf:Wn}=Object,Qt=globalThis,Ze=Qt.trustedTypes,qn=Ze?Ze.emptyScript:"",Gn=Qt.reactiveElementPolyfillSupport,Pt=(i,t)=>i,Nt={toAttribute(i,t){switch(t){case Boolean:i=i?qn:null;break;case Object:case Array:i=i==null?i:JSON.stringify(i)}return i},fromAttribute(i,t){let e=i;switch(t){case Boolean:e=i!==null;break;case Number:e=i===null?null:Number(i);break;case Object:case Array:try{e=JSON.parse(i)}catch{e=null}}return e}},te=(i,t)=>!Ln(i,t),Je={attribute:!0,type:String,converter:Nt,reflect:!1,useDefault:!1,hasChanged:te};Symbol.metadata??=Symbol("metadata"),Qt.litPropertyMetadata??=new WeakMap;var G=class extends HTMLElement{static addInitializer(t){this._$Ei(),(this.l??=[]).push(t)}static get observedAttributes(){return this.finalize(),this._$Eh&&[...this._$Eh.keys()]}static createProperty(t,e=Je){if(e.state&&(e.attribute=!1),this._$Ei(),this.prototype.hasOwnProperty(t)&&((e=Object.create(e)).wrapped=!0),this.elementProperties.set(t,e),!e.noAccessor){let n=Symbol(),o=this.getPropertyDescriptor(t,n,e);o!==void 0&&jn(this.prototype,t,o)}}static getPropertyDescriptor(t,e,n){let{get:o,set:r}=Xn(this.prototype,t)??{get(){return this[e]},set(a){this[e]=a}};return{get:o,set(a){let l=o?.call(this);r?.call(this,a),this.requestUpdate(t,l,n)},configurable:!0,enumerable:!0}}static getPropertyOptions(t){return this.elementProperties.get(t)??Je}static _$Ei(){if(this.hasOwnProperty(Pt("elementProperties")))return;let t=Wn(this);t.finalize(),t.l!==void 0&&(this.l=[...t.l]),this.elementProperties=new Map(t.elementProperties)}static finalize(){if(this.hasOwnProperty(Pt("finalized")))return;if(this.finalized=!0,this._$Ei(),this.hasOwnProperty(Pt("properties"))){let e=this.properties,n=[...Yn(e),...zn(e)];for(let o of n)this.createProperty(o,e[o])}let t=this[Symbol.metadata];if(t!==null){let e=litPropertyMetadata.get(t);if(e!==void 0)for(let[n,o]of e)this.elementProperties.set(n,o)}this._$Eh=new Map;for(let[e,n]of this.elementProperties){let o=this._$Eu(e,n);o!==void 0&&this._$Eh.set(o,e)}this.elementStyles=this.finalizeStyles(this.styles)}static finalizeStyles(t){let e=[];if(Array.isArray(t)){let n=new Set(t.flat(1/0).reverse());for(let o of n)e.unshift(ve(o))}else t!==void 0&&e.push(ve(t));return e}static _$Eu(t,e){let n=e.attribute;return n===!1?void 0:typeof n=="string"?n:typeof t=="string"?t.toLowerCase():void 0}constructor(){super(),this._$Ep=void 0,this.isUpdatePending=!1,this.hasUpdated=!1,this._$Em=null,this._$Ev()}_$Ev(){this._$ES=new Promise((t=>this.enableUpdating=t)),this._$AL=new Map,this._$E_(),this.requestUpdate(),this.constructor.l?.forEach((t=>t(this)))}addController(t){(this._$EO??=new Set).add(t),this.renderRoot!==void 0&&this.isConnected&&t.hostConnected?.()}removeController(t){this._$EO?.delete(t)}_$E_(){let t=new Map,e=this.constructor.elementProperties;for(let n of e.keys())this.hasOwnProperty(n)&&(t.set(n,this[n]),delete this[n]);t.size>0&&(this._$Ep=t)}createRenderRoot(){let t=this.shadowRoot??this.attachShadow(this.constructor.shadowRootOptions);return Ve(t,this.constructor.elementStyles),t}connectedCallback(){this.renderRoot??=this.createRenderRoot(),this.enableUpdating(!0),this._$EO?.forEach((t=>t.hostConnected?.()))}enableUpdating(t){}disconnectedCallback(){this._$EO?.forEach((t=>t.hostDisconnected?.()))}attributeChangedCallback(t,e,n){this._$AK(t,n)}_$ET(t,e){let n=this.constructor.elementProperties.get(t),o=this.constructor._$Eu(t,n);if(o!==void 0&&n.reflect===!0){let r=(n.converter?.toAttribute!==void 0?n.converter:Nt).toAttribute(e,n.type);this._$Em=t,r==null?this.removeAttribute(o):this.setAttribute(o,r),this._$Em=null}}_$AK(t,e){let n=this.constructor,o=n._$Eh.get(t);if(o!==void 0&&this._$Em!==o){let r=n.getPropertyOptions(o),a=typeof r.converter=="function"?{fromAttribute:r.converter}:r.converter?.fromAttribute!==void 0?r.converter:Nt;this._$Em=o;let l=a.fromAttribute(e,r.type);this[o]=l??this._$Ej?.get(o)??l,this._$Em=null}}requestUpdate(t,e,n){if(t!==void 0){let o=this.constructor,r=this[t];if(n??=o.getPropertyOptions(t),!((n.hasChanged??te)(r,e)||n.useDefault&&n.reflect&&r===this._$Ej?.get(t)&&!this.hasAttribute(o._$Eu(t,n))))return;this.C(t,e,n)}this.isUpdatePending===!1&&(this._$ES=this._$EP())}C(t,e,{useDefault:n,reflect:o,wrapped:r},a){n&&!(this._$Ej??=new Map).has(t)&&(this._$Ej.set(t,a??e??this[t]),r!==!0||a!==void 0)||(this._$AL.has(t)||(this.hasUpdated||n||(e=void 0),this._$AL.set(t,e)),o===!0&&this._$Em!==t&&(this._$Eq??=new Set).add(t))}async _$EP(){this.isUpdatePending=!0;try{await this._$ES}catch(e){Promise.reject(e)}let t=this.scheduleUpdate();return t!=null&&await t,!this.isUpdatePending}scheduleUpdate(){return this.performUpdate()}performUpdate(){if(!this.isUpdatePending)return;if(!this.hasUpdated){if(this.renderRoot??=this.createRenderRoot(),this._$Ep){for(let[o,r]of this._$Ep)this[o]=r;this._$Ep=void 0}let n=this.constructor.elementProperties;if(n.size>0)for(let[o,r]of n){let{wrapped:a}=r,l=this[o];a!==!0||this._$AL.has(o)||l===void 0||this.C(o,void 0,r,l)}}let t=!1,e=this._$AL;try{t=this.shouldUpdate(e),t?(this.willUpdate(e),this._$EO?.forEach((n=>n.hostUpdate?.())),this.update(e)):this._$EM()}catch(n){throw t=!1,this._$EM(),n}t&&this._$AE(e)}willUpdate(t){}_$AE(t){this._$EO?.forEach((e=>e.hostUpdated?.())),this.hasUpdated||(this.hasUpdated=!0,this.firstUpdated(t)),this.updated(t)}_$EM(){this._$AL=new Map,this.isUpdatePending=!1}get updateComplete(){return this.getUpdateComplete()}getUpdateComplete(){return this._$ES}shouldUpdate(t){return!0}update(t){this._$Eq&&=this._$Eq.forEach((e=>this._$ET(e,this[e]))),this._$EM()}updated(t){}firstUpdated(t){}};G.elementStyles=[],G.shadowRootOptions={mode:"open"},G[Pt("elementProperties")]=new Map,G[Pt("finalized")]=new Map,Gn?.({ReactiveElement:G}),(Qt.reactiveElementVersions??=[]).push("2.1.1");var Ae=globalThis,ee=Ae.trustedTypes,Qe=ee?ee.createPolicy("lit-html",{createHTML:i=>i}):void 0,an="$lit$",tt=`lit$${Math.random().toFixed(9).slice(2)}$`,sn="?"+tt,Kn=`<${sn}>`,ht=document,Mt=()=>ht.createComment(""),Rt=i=>i===null||typeof i!="object"&&typeof i!="function",De=Array.isArray,Vn=i=>De(i)||typeof i?.[Symbol.iterator]=="function",be=`[ 	
-\f\r]`,It=/<(?:(!--|\/[^a-zA-Z])|(\/?[a-zA-Z][^>\s]*)|(\/?$))/g,tn=/-->/g,en=/>/g,ct=RegExp(`>|${be}(?:([^\\s"'>=/]+)(${be}*=${be}*(?:[^ 	
-\f\r"'\`<>=]|("|')|))|$)`,"g"),nn=/'/g,on=/"/g,ln=/^(?:script|style|textarea|title)$/i,Ce=i=>(t,...e)=>({_$litType$:i,strings:t,values:e}),$e=Ce(1),Mi=Ce(2),Ri=Ce(3),dt=Symbol.for("lit-noChange"),$=Symbol.for("lit-nothing"),rn=new WeakMap,ut=ht.createTreeWalker(ht,129);function cn(i,t){if(!De(i)||!i.hasOwnProperty("raw"))throw Error("invalid template strings array");return Qe!==void 0?Qe.createHTML(t):t}var Zn=(i,t)=>{let e=i.length-1,n=[],o,r=t===2?"<svg>":t===3?"<math>":"",a=It;for(let l=0;l<e;l++){let s=i[l],c,d,u=-1,g=0;for(;g<s.length&&(a.lastIndex=g,d=a.exec(s),d!==null);)g=a.lastIndex,a===It?d[1]==="!--"?a=tn:d[1]!==void 0?a=en:d[2]!==void 0?(ln.test(d[2])&&(o=RegExp("</"+d[2],"g")),a=ct):d[3]!==void 0&&(a=ct):a===ct?d[0]===">"?(a=o??It,u=-1):d[1]===void 0?u=-2:(u=a.lastIndex-d[2].length,c=d[1],a=d[3]===void 0?ct:d[3]==='"'?on:nn):a===on||a===nn?a=ct:a===tn||a===en?a=It:(a=ct,o=void 0);let b=a===ct&&i[l+1].startsWith("/>")?" ":"";r+=a===It?s+Kn:u>=0?(n.push(c),s.slice(0,u)+an+s.slice(u)+tt+b):s+tt+(u===-2?l:b)}return[cn(i,r+(i[e]||"<?>")+(t===2?"</svg>":t===3?"</math>":"")),n]},Ht=class i{constructor({strings:t,_$litType$:e},n){let o;this.parts=[];let r=0,a=0,l=t.length-1,s=this.parts,[c,d]=Zn(t,e);if(this.el=i.createElement(c,n),ut.currentNode=this.el.content,e===2||e===3){let u=this.el.content.firstChild;u.replaceWith(...u.childNodes)}for(;(o=ut.nextNode())!==null&&s.length<l;){if(o.nodeType===1){if(o.hasAttributes())for(let u of o.getAttributeNames())if(u.endsWith(an)){let g=d[a++],b=o.getAttribute(u).split(tt),v=/([.?@])?(.*)/.exec(g);s.push({type:1,index:r,name:v[2],strings:b,ctor:v[1]==="."?_e:v[1]==="?"?Ee:v[1]==="@"?we:yt}),o.removeAttribute(u)}else u.startsWith(tt)&&(s.push({type:6,index:r}),o.removeAttribute(u));if(ln.test(o.tagName)){let u=o.textContent.split(tt),g=u.length-1;if(g>0){o.textContent=ee?ee.emptyScript:"";for(let b=0;b<g;b++)o.append(u[b],Mt()),ut.nextNode(),s.push({type:2,index:++r});o.append(u[g],Mt())}}}else if(o.nodeType===8)if(o.data===sn)s.push({type:2,index:r});else{let u=-1;for(;(u=o.data.indexOf(tt,u+1))!==-1;)s.push({type:7,index:r}),u+=tt.length-1}r++}}static createElement(t,e){let n=ht.createElement("template");return n.innerHTML=t,n}};function bt(i,t,e=i,n){if(t===dt)return t;let o=n!==void 0?e._$Co?.[n]:e._$Cl,r=Rt(t)?void 0:t._$litDirective$;return o?.constructor!==r&&(o?._$AO?.(!1),r===void 0?o=void 0:(o=new r(i),o._$AT(i,e,n)),n!==void 0?(e._$Co??=[])[n]=o:e._$Cl=o),o!==void 0&&(t=bt(i,o._$AS(i,t.values),o,n)),t}var ye=class{constructor(t,e){this._$AV=[],this._$AN=void 0,this._$AD=t,this._$AM=e}get parentNode(){return this._$AM.parentNode}get _$AU(){return this._$AM._$AU}u(t){let{el:{content:e},parts:n}=this._$AD,o=(t?.creationScope??ht).importNode(e,!0);ut.currentNode=o;let r=ut.nextNode(),a=0,l=0,s=n[0];for(;s!==void 0;){if(a===s.index){let c;s.type===2?c=new Ut(r,r.nextSibling,this,t):s.type===1?c=new s.ctor(r,s.name,s.strings,this,t):s.type===6&&(c=new Se(r,this,t)),this._$AV.push(c),s=n[++l]}a!==s?.index&&(r=ut.nextNode(),a++)}return ut.currentNode=ht,o}p(t){let e=0;for(let n of this._$AV)n!==void 0&&(n.strings!==void 0?(n._$AI(t,n,e),e+=n.strings.length-2):n._$AI(t[e])),e++}},Ut=class i{get _$AU(){return this._$AM?._$AU??this._$Cv}constructor(t,e,n,o){this.type=2,this._$AH=$,this._$AN=void 0,this._$AA=t,this._$AB=e,this._$AM=n,this.options=o,this._$Cv=o?.isConnected??!0}get parentNode(){let t=this._$AA.parentNode,e=this._$AM;return e!==void 0&&t?.nodeType===11&&(t=e.parentNode),t}get startNode(){return this._$AA}get endNode(){return this._$AB}_$AI(t,e=this){t=bt(this,t,e),Rt(t)?t===$||t==null||t===""?(this._$AH!==$&&this._$AR(),this._$AH=$):t!==this._$AH&&t!==dt&&this._(t):t._$litType$!==void 0?this.$(t):t.nodeType!==void 0?this.T(t):Vn(t)?this.k(t):this._(t)}O(t){return this._$AA.parentNode.insertBefore(t,this._$AB)}T(t){this._$AH!==t&&(this._$AR(),this._$AH=this.O(t))}_(t){this._$AH!==$&&Rt(this._$AH)?this._$AA.nextSibling.data=t:this.T(ht.createTextNode(t)),this._$AH=t}$(t){let{values:e,_$litType$:n}=t,o=typeof n=="number"?this._$AC(t):(n.el===void 0&&(n.el=Ht.createElement(cn(n.h,n.h[0]),this.options)),n);if(this._$AH?._$AD===o)this._$AH.p(e);else{let r=new ye(o,this),a=r.u(this.options);r.p(e),this.T(a),this._$AH=r}}_$AC(t){let e=rn.get(t.strings);return e===void 0&&rn.set(t.strings,e=new Ht(t)),e}k(t){De(this._$AH)||(this._$AH=[],this._$AR());let e=this._$AH,n,o=0;for(let r of t)o===e.length?e.push(n=new i(this.O(Mt()),this.O(Mt()),this,this.options)):n=e[o],n._$AI(r),o++;o<e.length&&(this._$AR(n&&n._$AB.nextSibling,o),e.length=o)}_$AR(t=this._$AA.nextSibling,e){for(this._$AP?.(!1,!0,e);t!==this._$AB;){let n=t.nextSibling;t.remove(),t=n}}setConnected(t){this._$AM===void 0&&(this._$Cv=t,this._$AP?.(t))}},yt=class{get tagName(){return this.element.tagName}get _$AU(){return this._$AM._$AU}constructor(t,e,n,o,r){this.type=1,this._$AH=$,this._$AN=void 0,this.element=t,this.name=e,this._$AM=o,this.options=r,n.length>2||n[0]!==""||n[1]!==""?(this._$AH=Array(n.length-1).fill(new String),this.strings=n):this._$AH=$}_$AI(t,e=this,n,o){let r=this.strings,a=!1;if(r===void 0)t=bt(this,t,e,0),a=!Rt(t)||t!==this._$AH&&t!==dt,a&&(this._$AH=t);else{let l=t,s,c;for(t=r[0],s=0;s<r.length-1;s++)c=bt(this,l[n+s],e,s),c===dt&&(c=this._$AH[s]),a||=!Rt(c)||c!==this._$AH[s],c===$?t=$:t!==$&&(t+=(c??"")+r[s+1]),this._$AH[s]=c}a&&!o&&this.j(t)}j(t){t===$?this.element.removeAttribute(this.name):this.element.setAttribute(this.name,t??"")}},_e=class extends yt{constructor(){super(...arguments),this.type=3}j(t){this.element[this.name]=t===$?void 0:t}},Ee=class extends yt{constructor(){super(...arguments),this.type=4}j(t){this.element.toggleAttribute(this.name,!!t&&t!==$)}},we=class extends yt{constructor(t,e,n,o,r){super(t,e,n,o,r),this.type=5}_$AI(t,e=this){if((t=bt(this,t,e,0)??$)===dt)return;let n=this._$AH,o=t===$&&n!==$||t.capture!==n.capture||t.once!==n.once||t.passive!==n.passive,r=t!==$&&(n===$||o);o&&this.element.removeEventListener(this.name,this,n),r&&this.element.addEventListener(this.name,this,t),this._$AH=t}handleEvent(t){typeof this._$AH=="function"?this._$AH.call(this.options?.host??this.element,t):this._$AH.handleEvent(t)}},Se=class{constructor(t,e,n){this.element=t,this.type=6,this._$AN=void 0,this._$AM=e,this.options=n}get _$AU(){return this._$AM._$AU}_$AI(t){bt(this,t)}};var Jn=Ae.litHtmlPolyfillSupport;Jn?.(Ht,Ut),(Ae.litHtmlVersions??=[]).push("3.3.1");var un=(i,t,e)=>{let n=e?.renderBefore??t,o=n._$litPart$;if(o===void 0){let r=e?.renderBefore??null;n._$litPart$=o=new Ut(t.insertBefore(Mt(),r),r,void 0,e??{})}return o._$AI(i),o};var Oe=globalThis,et=class extends G{constructor(){super(...arguments),this.renderOptions={host:this},this._$Do=void 0}createRenderRoot(){let t=super.createRenderRoot();return this.renderOptions.renderBefore??=t.firstChild,t}update(t){let e=this.render();this.hasUpdated||(this.renderOptions.isConnected=this.isConnected),super.update(t),this._$Do=un(e,this.renderRoot,this.renderOptions)}connectedCallback(){super.connectedCallback(),this._$Do?.setConnected(!0)}disconnectedCallback(){super.disconnectedCallback(),this._$Do?.setConnected(!1)}render(){return dt}};et._$litElement$=!0,et.finalized=!0,Oe.litElementHydrateSupport?.({LitElement:et});var Qn=Oe.litElementPolyfillSupport;Qn?.({LitElement:et});(Oe.litElementVersions??=[]).push("4.2.1");var hn=i=>(t,e)=>{e!==void 0?e.addInitializer((()=>{customElements.define(i,t)})):customElements.define(i,t)};var ti={attribute:!0,type:String,converter:Nt,reflect:!1,hasChanged:te},ei=(i=ti,t,e)=>{let{kind:n,metadata:o}=e,r=globalThis.litPropertyMetadata.get(o);if(r===void 0&&globalThis.litPropertyMetadata.set(o,r=new Map),n==="setter"&&((i=Object.create(i)).wrapped=!0),r.set(e.name,i),n==="accessor"){let{name:a}=e;return{set(l){let s=t.get.call(this);t.set.call(this,l),this.requestUpdate(a,s,i)},init(l){return l!==void 0&&this.C(a,void 0,i,l),l}}}if(n==="setter"){let{name:a}=e;return function(l){let s=this[a];t.call(this,l),this.requestUpdate(a,s,i)}}throw Error("Unsupported decorator location: "+n)};function kt(i){return(t,e)=>typeof e=="object"?ei(i,t,e):((n,o,r)=>{let a=o.hasOwnProperty(r);return o.constructor.createProperty(r,n),a?Object.getOwnPropertyDescriptor(o,r):void 0})(i,t,e)}var ft=(i,t,e)=>(e.configurable=!0,e.enumerable=!0,Reflect.decorate&&typeof t!="object"&&Object.defineProperty(i,t,e),e);function dn(i,t){return(e,n,o)=>{let r=a=>a.renderRoot?.querySelector(i)??null;if(t){let{get:a,set:l}=typeof n=="object"?e:o??(()=>{let s=Symbol();return{get(){return this[s]},set(c){this[s]=c}}})();return ft(e,n,{get(){let s=a.call(this);return s===void 0&&(s=r(this),(s!==null||this.hasUpdated)&&l.call(this,s)),s}})}return ft(e,n,{get(){return r(this)}})}}function fn(i,t){var e=Object.keys(i);if(Object.getOwnPropertySymbols){var n=Object.getOwnPropertySymbols(i);t&&(n=n.filter(function(o){return Object.getOwnPropertyDescriptor(i,o).enumerable})),e.push.apply(e,n)}return e}function z(i){for(var t=1;t<arguments.length;t++){var e=arguments[t]!=null?arguments[t]:{};t%2?fn(Object(e),!0).forEach(function(n){ni(i,n,e[n])}):Object.getOwnPropertyDescriptors?Object.defineProperties(i,Object.getOwnPropertyDescriptors(e)):fn(Object(e)).forEach(function(n){Object.defineProperty(i,n,Object.getOwnPropertyDescriptor(e,n))})}return i}function ae(i){"@babel/helpers - typeof";return typeof Symbol=="function"&&typeof Symbol.iterator=="symbol"?ae=function(t){return typeof t}:ae=function(t){return t&&typeof Symbol=="function"&&t.constructor===Symbol&&t!==Symbol.prototype?"symbol":typeof t},ae(i)}function ni(i,t,e){return t in i?Object.defineProperty(i,t,{value:e,enumerable:!0,configurable:!0,writable:!0}):i[t]=e,i}function V(){return V=Object.assign||function(i){for(var t=1;t<arguments.length;t++){var e=arguments[t];for(var n in e)Object.prototype.hasOwnProperty.call(e,n)&&(i[n]=e[n])}return i},V.apply(this,arguments)}function ii(i,t){if(i==null)return{};var e={},n=Object.keys(i),o,r;for(r=0;r<n.length;r++)o=n[r],!(t.indexOf(o)>=0)&&(e[o]=i[o]);return e}function oi(i,t){if(i==null)return{};var e=ii(i,t),n,o;if(Object.getOwnPropertySymbols){var r=Object.getOwnPropertySymbols(i);for(o=0;o<r.length;o++)n=r[o],!(t.indexOf(n)>=0)&&Object.prototype.propertyIsEnumerable.call(i,n)&&(e[n]=i[n])}return e}var ri="1.15.6";function K(i){if(typeof window<"u"&&window.navigator)return!!navigator.userAgent.match(i)}var Z=K(/(?:Trident.*rv[ :]?11\.|msie|iemobile|Windows Phone)/i),qt=K(/Edge/i),pn=K(/firefox/i),jt=K(/safari/i)&&!K(/chrome/i)&&!K(/android/i),je=K(/iP(ad|od|hone)/i),wn=K(/chrome/i)&&K(/android/i),Sn={capture:!1,passive:!1};function _(i,t,e){i.addEventListener(t,e,!Z&&Sn)}function y(i,t,e){i.removeEventListener(t,e,!Z&&Sn)}function he(i,t){if(t){if(t[0]===">"&&(t=t.substring(1)),i)try{if(i.matches)return i.matches(t);if(i.msMatchesSelector)return i.msMatchesSelector(t);if(i.webkitMatchesSelector)return i.webkitMatchesSelector(t)}catch{return!1}return!1}}function An(i){return i.host&&i!==document&&i.host.nodeType?i.host:i.parentNode}function j(i,t,e,n){if(i){e=e||document;do{if(t!=null&&(t[0]===">"?i.parentNode===e&&he(i,t):he(i,t))||n&&i===e)return i;if(i===e)break}while(i=An(i))}return null}var gn=/\s+/g;function H(i,t,e){if(i&&t)if(i.classList)i.classList[e?"add":"remove"](t);else{var n=(" "+i.className+" ").replace(gn," ").replace(" "+t+" "," ");i.className=(n+(e?" "+t:"")).replace(gn," ")}}function f(i,t,e){var n=i&&i.style;if(n){if(e===void 0)return document.defaultView&&document.defaultView.getComputedStyle?e=document.defaultView.getComputedStyle(i,""):i.currentStyle&&(e=i.currentStyle),t===void 0?e:e[t];!(t in n)&&t.indexOf("webkit")===-1&&(t="-webkit-"+t),n[t]=e+(typeof e=="string"?"":"px")}}function At(i,t){var e="";if(typeof i=="string")e=i;else do{var n=f(i,"transform");n&&n!=="none"&&(e=n+" "+e)}while(!t&&(i=i.parentNode));var o=window.DOMMatrix||window.WebKitCSSMatrix||window.CSSMatrix||window.MSCSSMatrix;return o&&new o(e)}function Dn(i,t,e){if(i){var n=i.getElementsByTagName(t),o=0,r=n.length;if(e)for(;o<r;o++)e(n[o],o);return n}return[]}function Y(){var i=document.scrollingElement;return i||document.documentElement}function C(i,t,e,n,o){if(!(!i.getBoundingClientRect&&i!==window)){var r,a,l,s,c,d,u;if(i!==window&&i.parentNode&&i!==Y()?(r=i.getBoundingClientRect(),a=r.top,l=r.left,s=r.bottom,c=r.right,d=r.height,u=r.width):(a=0,l=0,s=window.innerHeight,c=window.innerWidth,d=window.innerHeight,u=window.innerWidth),(t||e)&&i!==window&&(o=o||i.parentNode,!Z))do if(o&&o.getBoundingClientRect&&(f(o,"transform")!=="none"||e&&f(o,"position")!=="static")){var g=o.getBoundingClientRect();a-=g.top+parseInt(f(o,"border-top-width")),l-=g.left+parseInt(f(o,"border-left-width")),s=a+r.height,c=l+r.width;break}while(o=o.parentNode);if(n&&i!==window){var b=At(o||i),v=b&&b.a,E=b&&b.d;b&&(a/=E,l/=v,u/=v,d/=E,s=a+d,c=l+u)}return{top:a,left:l,bottom:s,right:c,width:u,height:d}}}function mn(i,t,e){for(var n=ot(i,!0),o=C(i)[t];n;){var r=C(n)[e],a=void 0;if(e==="top"||e==="left"?a=o>=r:a=o<=r,!a)return n;if(n===Y())break;n=ot(n,!1)}return!1}function Dt(i,t,e,n){for(var o=0,r=0,a=i.children;r<a.length;){if(a[r].style.display!=="none"&&a[r]!==p.ghost&&(n||a[r]!==p.dragged)&&j(a[r],e.draggable,i,!1)){if(o===t)return a[r];o++}r++}return null}function Xe(i,t){for(var e=i.lastElementChild;e&&(e===p.ghost||f(e,"display")==="none"||t&&!he(e,t));)e=e.previousElementSibling;return e||null}function k(i,t){var e=0;if(!i||!i.parentNode)return-1;for(;i=i.previousElementSibling;)i.nodeName.toUpperCase()!=="TEMPLATE"&&i!==p.clone&&(!t||he(i,t))&&e++;return e}function vn(i){var t=0,e=0,n=Y();if(i)do{var o=At(i),r=o.a,a=o.d;t+=i.scrollLeft*r,e+=i.scrollTop*a}while(i!==n&&(i=i.parentNode));return[t,e]}function ai(i,t){for(var e in i)if(i.hasOwnProperty(e)){for(var n in t)if(t.hasOwnProperty(n)&&t[n]===i[e][n])return Number(e)}return-1}function ot(i,t){if(!i||!i.getBoundingClientRect)return Y();var e=i,n=!1;do if(e.clientWidth<e.scrollWidth||e.clientHeight<e.scrollHeight){var o=f(e);if(e.clientWidth<e.scrollWidth&&(o.overflowX=="auto"||o.overflowX=="scroll")||e.clientHeight<e.scrollHeight&&(o.overflowY=="auto"||o.overflowY=="scroll")){if(!e.getBoundingClientRect||e===document.body)return Y();if(n||t)return e;n=!0}}while(e=e.parentNode);return Y()}function si(i,t){if(i&&t)for(var e in t)t.hasOwnProperty(e)&&(i[e]=t[e]);return i}function Te(i,t){return Math.round(i.top)===Math.round(t.top)&&Math.round(i.left)===Math.round(t.left)&&Math.round(i.height)===Math.round(t.height)&&Math.round(i.width)===Math.round(t.width)}var Xt;function Cn(i,t){return function(){if(!Xt){var e=arguments,n=this;e.length===1?i.call(n,e[0]):i.apply(n,e),Xt=setTimeout(function(){Xt=void 0},t)}}}function li(){clearTimeout(Xt),Xt=void 0}function $n(i,t,e){i.scrollLeft+=t,i.scrollTop+=e}function On(i){var t=window.Polymer,e=window.jQuery||window.Zepto;return t&&t.dom?t.dom(i).cloneNode(!0):e?e(i).clone(!0)[0]:i.cloneNode(!0)}function Tn(i,t,e){var n={};return Array.from(i.children).forEach(function(o){var r,a,l,s;if(!(!j(o,t.draggable,i,!1)||o.animated||o===e)){var c=C(o);n.left=Math.min((r=n.left)!==null&&r!==void 0?r:1/0,c.left),n.top=Math.min((a=n.top)!==null&&a!==void 0?a:1/0,c.top),n.right=Math.max((l=n.right)!==null&&l!==void 0?l:-1/0,c.right),n.bottom=Math.max((s=n.bottom)!==null&&s!==void 0?s:-1/0,c.bottom)}}),n.width=n.right-n.left,n.height=n.bottom-n.top,n.x=n.left,n.y=n.top,n}var I="Sortable"+new Date().getTime();function ci(){var i=[],t;return{captureAnimationState:function(){if(i=[],!!this.options.animation){var n=[].slice.call(this.el.children);n.forEach(function(o){if(!(f(o,"display")==="none"||o===p.ghost)){i.push({target:o,rect:C(o)});var r=z({},i[i.length-1].rect);if(o.thisAnimationDuration){var a=At(o,!0);a&&(r.top-=a.f,r.left-=a.e)}o.fromRect=r}})}},addAnimationState:function(n){i.push(n)},removeAnimationState:function(n){i.splice(ai(i,{target:n}),1)},animateAll:function(n){var o=this;if(!this.options.animation){clearTimeout(t),typeof n=="function"&&n();return}var r=!1,a=0;i.forEach(function(l){var s=0,c=l.target,d=c.fromRect,u=C(c),g=c.prevFromRect,b=c.prevToRect,v=l.rect,E=At(c,!0);E&&(u.top-=E.f,u.left-=E.e),c.toRect=u,c.thisAnimationDuration&&Te(g,u)&&!Te(d,u)&&(v.top-u.top)/(v.left-u.left)===(d.top-u.top)/(d.left-u.left)&&(s=hi(v,g,b,o.options)),Te(u,d)||(c.prevFromRect=d,c.prevToRect=u,s||(s=o.options.animation),o.animate(c,v,u,s)),s&&(r=!0,a=Math.max(a,s),clearTimeout(c.animationResetTimer),c.animationResetTimer=setTimeout(function(){c.animationTime=0,c.prevFromRect=null,c.fromRect=null,c.prevToRect=null,c.thisAnimationDuration=null},s),c.thisAnimationDuration=s)}),clearTimeout(t),r?t=setTimeout(function(){typeof n=="function"&&n()},a):typeof n=="function"&&n(),i=[]},animate:function(n,o,r,a){if(a){f(n,"transition",""),f(n,"transform","");var l=At(this.el),s=l&&l.a,c=l&&l.d,d=(o.left-r.left)/(s||1),u=(o.top-r.top)/(c||1);n.animatingX=!!d,n.animatingY=!!u,f(n,"transform","translate3d("+d+"px,"+u+"px,0)"),this.forRepaintDummy=ui(n),f(n,"transition","transform "+a+"ms"+(this.options.easing?" "+this.options.easing:"")),f(n,"transform","translate3d(0,0,0)"),typeof n.animated=="number"&&clearTimeout(n.animated),n.animated=setTimeout(function(){f(n,"transition",""),f(n,"transform",""),n.animated=!1,n.animatingX=!1,n.animatingY=!1},a)}}}}function ui(i){return i.offsetWidth}function hi(i,t,e,n){return Math.sqrt(Math.pow(t.top-i.top,2)+Math.pow(t.left-i.left,2))/Math.sqrt(Math.pow(t.top-e.top,2)+Math.pow(t.left-e.left,2))*n.animation}var _t=[],xe={initializeByDefault:!0},Gt={mount:function(t){for(var e in xe)xe.hasOwnProperty(e)&&!(e in t)&&(t[e]=xe[e]);_t.forEach(function(n){if(n.pluginName===t.pluginName)throw"Sortable: Cannot mount plugin ".concat(t.pluginName," more than once")}),_t.push(t)},pluginEvent:function(t,e,n){var o=this;this.eventCanceled=!1,n.cancel=function(){o.eventCanceled=!0};var r=t+"Global";_t.forEach(function(a){e[a.pluginName]&&(e[a.pluginName][r]&&e[a.pluginName][r](z({sortable:e},n)),e.options[a.pluginName]&&e[a.pluginName][t]&&e[a.pluginName][t](z({sortable:e},n)))})},initializePlugins:function(t,e,n,o){_t.forEach(function(l){var s=l.pluginName;if(!(!t.options[s]&&!l.initializeByDefault)){var c=new l(t,e,t.options);c.sortable=t,c.options=t.options,t[s]=c,V(n,c.defaults)}});for(var r in t.options)if(t.options.hasOwnProperty(r)){var a=this.modifyOption(t,r,t.options[r]);typeof a<"u"&&(t.options[r]=a)}},getEventProperties:function(t,e){var n={};return _t.forEach(function(o){typeof o.eventProperties=="function"&&V(n,o.eventProperties.call(e[o.pluginName],t))}),n},modifyOption:function(t,e,n){var o;return _t.forEach(function(r){t[r.pluginName]&&r.optionListeners&&typeof r.optionListeners[e]=="function"&&(o=r.optionListeners[e].call(t[r.pluginName],n))}),o}};function di(i){var t=i.sortable,e=i.rootEl,n=i.name,o=i.targetEl,r=i.cloneEl,a=i.toEl,l=i.fromEl,s=i.oldIndex,c=i.newIndex,d=i.oldDraggableIndex,u=i.newDraggableIndex,g=i.originalEvent,b=i.putSortable,v=i.extraEventProperties;if(t=t||e&&e[I],!!t){var E,F=t.options,W="on"+n.charAt(0).toUpperCase()+n.substr(1);window.CustomEvent&&!Z&&!qt?E=new CustomEvent(n,{bubbles:!0,cancelable:!0}):(E=document.createEvent("Event"),E.initEvent(n,!0,!0)),E.to=a||e,E.from=l||e,E.item=o||e,E.clone=r,E.oldIndex=s,E.newIndex=c,E.oldDraggableIndex=d,E.newDraggableIndex=u,E.originalEvent=g,E.pullMode=b?b.lastPutMode:void 0;var x=z(z({},v),Gt.getEventProperties(n,t));for(var B in x)E[B]=x[B];e&&e.dispatchEvent(E),F[W]&&F[W].call(t,E)}}var fi=["evt"],N=function(t,e){var n=arguments.length>2&&arguments[2]!==void 0?arguments[2]:{},o=n.evt,r=oi(n,fi);Gt.pluginEvent.bind(p)(t,e,z({dragEl:h,parentEl:A,ghostEl:m,rootEl:w,nextEl:mt,lastDownEl:se,cloneEl:S,cloneHidden:it,dragStarted:Ft,putSortable:O,activeSortable:p.active,originalEvent:o,oldIndex:St,oldDraggableIndex:Yt,newIndex:U,newDraggableIndex:nt,hideGhostForTarget:In,unhideGhostForTarget:Mn,cloneNowHidden:function(){it=!0},cloneNowShown:function(){it=!1},dispatchSortableEvent:function(l){P({sortable:e,name:l,originalEvent:o})}},r))};function P(i){di(z({putSortable:O,cloneEl:S,targetEl:h,rootEl:w,oldIndex:St,oldDraggableIndex:Yt,newIndex:U,newDraggableIndex:nt},i))}var h,A,m,w,mt,se,S,it,St,U,Yt,nt,ne,O,wt=!1,de=!1,fe=[],pt,L,Pe,Ne,bn,yn,Ft,Et,zt,Wt=!1,ie=!1,le,T,Ie=[],ke=!1,pe=[],me=typeof document<"u",oe=je,_n=qt||Z?"cssFloat":"float",pi=me&&!wn&&!je&&"draggable"in document.createElement("div"),xn=(function(){if(me){if(Z)return!1;var i=document.createElement("x");return i.style.cssText="pointer-events:auto",i.style.pointerEvents==="auto"}})(),Pn=function(t,e){var n=f(t),o=parseInt(n.width)-parseInt(n.paddingLeft)-parseInt(n.paddingRight)-parseInt(n.borderLeftWidth)-parseInt(n.borderRightWidth),r=Dt(t,0,e),a=Dt(t,1,e),l=r&&f(r),s=a&&f(a),c=l&&parseInt(l.marginLeft)+parseInt(l.marginRight)+C(r).width,d=s&&parseInt(s.marginLeft)+parseInt(s.marginRight)+C(a).width;if(n.display==="flex")return n.flexDirection==="column"||n.flexDirection==="column-reverse"?"vertical":"horizontal";if(n.display==="grid")return n.gridTemplateColumns.split(" ").length<=1?"vertical":"horizontal";if(r&&l.float&&l.float!=="none"){var u=l.float==="left"?"left":"right";return a&&(s.clear==="both"||s.clear===u)?"vertical":"horizontal"}return r&&(l.display==="block"||l.display==="flex"||l.display==="table"||l.display==="grid"||c>=o&&n[_n]==="none"||a&&n[_n]==="none"&&c+d>o)?"vertical":"horizontal"},gi=function(t,e,n){var o=n?t.left:t.top,r=n?t.right:t.bottom,a=n?t.width:t.height,l=n?e.left:e.top,s=n?e.right:e.bottom,c=n?e.width:e.height;return o===l||r===s||o+a/2===l+c/2},mi=function(t,e){var n;return fe.some(function(o){var r=o[I].options.emptyInsertThreshold;if(!(!r||Xe(o))){var a=C(o),l=t>=a.left-r&&t<=a.right+r,s=e>=a.top-r&&e<=a.bottom+r;if(l&&s)return n=o}}),n},Nn=function(t){function e(r,a){return function(l,s,c,d){var u=l.options.group.name&&s.options.group.name&&l.options.group.name===s.options.group.name;if(r==null&&(a||u))return!0;if(r==null||r===!1)return!1;if(a&&r==="clone")return r;if(typeof r=="function")return e(r(l,s,c,d),a)(l,s,c,d);var g=(a?l:s).options.group.name;return r===!0||typeof r=="string"&&r===g||r.join&&r.indexOf(g)>-1}}var n={},o=t.group;(!o||ae(o)!="object")&&(o={name:o}),n.name=o.name,n.checkPull=e(o.pull,!0),n.checkPut=e(o.put),n.revertClone=o.revertClone,t.group=n},In=function(){!xn&&m&&f(m,"display","none")},Mn=function(){!xn&&m&&f(m,"display","")};me&&!wn&&document.addEventListener("click",function(i){if(de)return i.preventDefault(),i.stopPropagation&&i.stopPropagation(),i.stopImmediatePropagation&&i.stopImmediatePropagation(),de=!1,!1},!0);var gt=function(t){if(h){t=t.touches?t.touches[0]:t;var e=mi(t.clientX,t.clientY);if(e){var n={};for(var o in t)t.hasOwnProperty(o)&&(n[o]=t[o]);n.target=n.rootEl=e,n.preventDefault=void 0,n.stopPropagation=void 0,e[I]._onDragOver(n)}}},vi=function(t){h&&h.parentNode[I]._isOutsideThisEl(t.target)};function p(i,t){if(!(i&&i.nodeType&&i.nodeType===1))throw"Sortable: `el` must be an HTMLElement, not ".concat({}.toString.call(i));this.el=i,this.options=t=V({},t),i[I]=this;var e={group:null,sort:!0,disabled:!1,store:null,handle:null,draggable:/^[uo]l$/i.test(i.nodeName)?">li":">*",swapThreshold:1,invertSwap:!1,invertedSwapThreshold:null,removeCloneOnHide:!0,direction:function(){return Pn(i,this.options)},ghostClass:"sortable-ghost",chosenClass:"sortable-chosen",dragClass:"sortable-drag",ignore:"a, img",filter:null,preventOnFilter:!0,animation:0,easing:null,setData:function(a,l){a.setData("Text",l.textContent)},dropBubble:!1,dragoverBubble:!1,dataIdAttr:"data-id",delay:0,delayOnTouchOnly:!1,touchStartThreshold:(Number.parseInt?Number:window).parseInt(window.devicePixelRatio,10)||1,forceFallback:!1,fallbackClass:"sortable-fallback",fallbackOnBody:!1,fallbackTolerance:0,fallbackOffset:{x:0,y:0},supportPointer:p.supportPointer!==!1&&"PointerEvent"in window&&(!jt||je),emptyInsertThreshold:5};Gt.initializePlugins(this,i,e);for(var n in e)!(n in t)&&(t[n]=e[n]);Nn(t);for(var o in this)o.charAt(0)==="_"&&typeof this[o]=="function"&&(this[o]=this[o].bind(this));this.nativeDraggable=t.forceFallback?!1:pi,this.nativeDraggable&&(this.options.touchStartThreshold=1),t.supportPointer?_(i,"pointerdown",this._onTapStart):(_(i,"mousedown",this._onTapStart),_(i,"touchstart",this._onTapStart)),this.nativeDraggable&&(_(i,"dragover",this),_(i,"dragenter",this)),fe.push(this.el),t.store&&t.store.get&&this.sort(t.store.get(this)||[]),V(this,ci())}p.prototype={constructor:p,_isOutsideThisEl:function(t){!this.el.contains(t)&&t!==this.el&&(Et=null)},_getDirection:function(t,e){return typeof this.options.direction=="function"?this.options.direction.call(this,t,e,h):this.options.direction},_onTapStart:function(t){if(t.cancelable){var e=this,n=this.el,o=this.options,r=o.preventOnFilter,a=t.type,l=t.touches&&t.touches[0]||t.pointerType&&t.pointerType==="touch"&&t,s=(l||t).target,c=t.target.shadowRoot&&(t.path&&t.path[0]||t.composedPath&&t.composedPath()[0])||s,d=o.filter;if(Di(n),!h&&!(/mousedown|pointerdown/.test(a)&&t.button!==0||o.disabled)&&!c.isContentEditable&&!(!this.nativeDraggable&&jt&&s&&s.tagName.toUpperCase()==="SELECT")&&(s=j(s,o.draggable,n,!1),!(s&&s.animated)&&se!==s)){if(St=k(s),Yt=k(s,o.draggable),typeof d=="function"){if(d.call(this,t,s,this)){P({sortable:e,rootEl:c,name:"filter",targetEl:s,toEl:n,fromEl:n}),N("filter",e,{evt:t}),r&&t.preventDefault();return}}else if(d&&(d=d.split(",").some(function(u){if(u=j(c,u.trim(),n,!1),u)return P({sortable:e,rootEl:u,name:"filter",targetEl:s,fromEl:n,toEl:n}),N("filter",e,{evt:t}),!0}),d)){r&&t.preventDefault();return}o.handle&&!j(c,o.handle,n,!1)||this._prepareDragStart(t,l,s)}}},_prepareDragStart:function(t,e,n){var o=this,r=o.el,a=o.options,l=r.ownerDocument,s;if(n&&!h&&n.parentNode===r){var c=C(n);if(w=r,h=n,A=h.parentNode,mt=h.nextSibling,se=n,ne=a.group,p.dragged=h,pt={target:h,clientX:(e||t).clientX,clientY:(e||t).clientY},bn=pt.clientX-c.left,yn=pt.clientY-c.top,this._lastX=(e||t).clientX,this._lastY=(e||t).clientY,h.style["will-change"]="all",s=function(){if(N("delayEnded",o,{evt:t}),p.eventCanceled){o._onDrop();return}o._disableDelayedDragEvents(),!pn&&o.nativeDraggable&&(h.draggable=!0),o._triggerDragStart(t,e),P({sortable:o,name:"choose",originalEvent:t}),H(h,a.chosenClass,!0)},a.ignore.split(",").forEach(function(d){Dn(h,d.trim(),Me)}),_(l,"dragover",gt),_(l,"mousemove",gt),_(l,"touchmove",gt),a.supportPointer?(_(l,"pointerup",o._onDrop),!this.nativeDraggable&&_(l,"pointercancel",o._onDrop)):(_(l,"mouseup",o._onDrop),_(l,"touchend",o._onDrop),_(l,"touchcancel",o._onDrop)),pn&&this.nativeDraggable&&(this.options.touchStartThreshold=4,h.draggable=!0),N("delayStart",this,{evt:t}),a.delay&&(!a.delayOnTouchOnly||e)&&(!this.nativeDraggable||!(qt||Z))){if(p.eventCanceled){this._onDrop();return}a.supportPointer?(_(l,"pointerup",o._disableDelayedDrag),_(l,"pointercancel",o._disableDelayedDrag)):(_(l,"mouseup",o._disableDelayedDrag),_(l,"touchend",o._disableDelayedDrag),_(l,"touchcancel",o._disableDelayedDrag)),_(l,"mousemove",o._delayedDragTouchMoveHandler),_(l,"touchmove",o._delayedDragTouchMoveHandler),a.supportPointer&&_(l,"pointermove",o._delayedDragTouchMoveHandler),o._dragStartTimer=setTimeout(s,a.delay)}else s()}},_delayedDragTouchMoveHandler:function(t){var e=t.touches?t.touches[0]:t;Math.max(Math.abs(e.clientX-this._lastX),Math.abs(e.clientY-this._lastY))>=Math.floor(this.options.touchStartThreshold/(this.nativeDraggable&&window.devicePixelRatio||1))&&this._disableDelayedDrag()},_disableDelayedDrag:function(){h&&Me(h),clearTimeout(this._dragStartTimer),this._disableDelayedDragEvents()},_disableDelayedDragEvents:function(){var t=this.el.ownerDocument;y(t,"mouseup",this._disableDelayedDrag),y(t,"touchend",this._disableDelayedDrag),y(t,"touchcancel",this._disableDelayedDrag),y(t,"pointerup",this._disableDelayedDrag),y(t,"pointercancel",this._disableDelayedDrag),y(t,"mousemove",this._delayedDragTouchMoveHandler),y(t,"touchmove",this._delayedDragTouchMoveHandler),y(t,"pointermove",this._delayedDragTouchMoveHandler)},_triggerDragStart:function(t,e){e=e||t.pointerType=="touch"&&t,!this.nativeDraggable||e?this.options.supportPointer?_(document,"pointermove",this._onTouchMove):e?_(document,"touchmove",this._onTouchMove):_(document,"mousemove",this._onTouchMove):(_(h,"dragend",this),_(w,"dragstart",this._onDragStart));try{document.selection?ce(function(){document.selection.empty()}):window.getSelection().removeAllRanges()}catch{}},_dragStarted:function(t,e){if(wt=!1,w&&h){N("dragStarted",this,{evt:e}),this.nativeDraggable&&_(document,"dragover",vi);var n=this.options;!t&&H(h,n.dragClass,!1),H(h,n.ghostClass,!0),p.active=this,t&&this._appendGhost(),P({sortable:this,name:"start",originalEvent:e})}else this._nulling()},_emulateDragOver:function(){if(L){this._lastX=L.clientX,this._lastY=L.clientY,In();for(var t=document.elementFromPoint(L.clientX,L.clientY),e=t;t&&t.shadowRoot&&(t=t.shadowRoot.elementFromPoint(L.clientX,L.clientY),t!==e);)e=t;if(h.parentNode[I]._isOutsideThisEl(t),e)do{if(e[I]){var n=void 0;if(n=e[I]._onDragOver({clientX:L.clientX,clientY:L.clientY,target:t,rootEl:e}),n&&!this.options.dragoverBubble)break}t=e}while(e=An(e));Mn()}},_onTouchMove:function(t){if(pt){var e=this.options,n=e.fallbackTolerance,o=e.fallbackOffset,r=t.touches?t.touches[0]:t,a=m&&At(m,!0),l=m&&a&&a.a,s=m&&a&&a.d,c=oe&&T&&vn(T),d=(r.clientX-pt.clientX+o.x)/(l||1)+(c?c[0]-Ie[0]:0)/(l||1),u=(r.clientY-pt.clientY+o.y)/(s||1)+(c?c[1]-Ie[1]:0)/(s||1);if(!p.active&&!wt){if(n&&Math.max(Math.abs(r.clientX-this._lastX),Math.abs(r.clientY-this._lastY))<n)return;this._onDragStart(t,!0)}if(m){a?(a.e+=d-(Pe||0),a.f+=u-(Ne||0)):a={a:1,b:0,c:0,d:1,e:d,f:u};var g="matrix(".concat(a.a,",").concat(a.b,",").concat(a.c,",").concat(a.d,",").concat(a.e,",").concat(a.f,")");f(m,"webkitTransform",g),f(m,"mozTransform",g),f(m,"msTransform",g),f(m,"transform",g),Pe=d,Ne=u,L=r}t.cancelable&&t.preventDefault()}},_appendGhost:function(){if(!m){var t=this.options.fallbackOnBody?document.body:w,e=C(h,!0,oe,!0,t),n=this.options;if(oe){for(T=t;f(T,"position")==="static"&&f(T,"transform")==="none"&&T!==document;)T=T.parentNode;T!==document.body&&T!==document.documentElement?(T===document&&(T=Y()),e.top+=T.scrollTop,e.left+=T.scrollLeft):T=Y(),Ie=vn(T)}m=h.cloneNode(!0),H(m,n.ghostClass,!1),H(m,n.fallbackClass,!0),H(m,n.dragClass,!0),f(m,"transition",""),f(m,"transform",""),f(m,"box-sizing","border-box"),f(m,"margin",0),f(m,"top",e.top),f(m,"left",e.left),f(m,"width",e.width),f(m,"height",e.height),f(m,"opacity","0.8"),f(m,"position",oe?"absolute":"fixed"),f(m,"zIndex","100000"),f(m,"pointerEvents","none"),p.ghost=m,t.appendChild(m),f(m,"transform-origin",bn/parseInt(m.style.width)*100+"% "+yn/parseInt(m.style.height)*100+"%")}},_onDragStart:function(t,e){var n=this,o=t.dataTransfer,r=n.options;if(N("dragStart",this,{evt:t}),p.eventCanceled){this._onDrop();return}N("setupClone",this),p.eventCanceled||(S=On(h),S.removeAttribute("id"),S.draggable=!1,S.style["will-change"]="",this._hideClone(),H(S,this.options.chosenClass,!1),p.clone=S),n.cloneId=ce(function(){N("clone",n),!p.eventCanceled&&(n.options.removeCloneOnHide||w.insertBefore(S,h),n._hideClone(),P({sortable:n,name:"clone"}))}),!e&&H(h,r.dragClass,!0),e?(de=!0,n._loopId=setInterval(n._emulateDragOver,50)):(y(document,"mouseup",n._onDrop),y(document,"touchend",n._onDrop),y(document,"touchcancel",n._onDrop),o&&(o.effectAllowed="move",r.setData&&r.setData.call(n,o,h)),_(document,"drop",n),f(h,"transform","translateZ(0)")),wt=!0,n._dragStartId=ce(n._dragStarted.bind(n,e,t)),_(document,"selectstart",n),Ft=!0,window.getSelection().removeAllRanges(),jt&&f(document.body,"user-select","none")},_onDragOver:function(t){var e=this.el,n=t.target,o,r,a,l=this.options,s=l.group,c=p.active,d=ne===s,u=l.sort,g=O||c,b,v=this,E=!1;if(ke)return;function F(xt,Un){N(xt,v,z({evt:t,isOwner:d,axis:b?"vertical":"horizontal",revert:a,dragRect:o,targetRect:r,canSort:u,fromSortable:g,target:n,completed:x,onMove:function(We,kn){return re(w,e,h,o,We,C(We),t,kn)},changed:B},Un))}function W(){F("dragOverAnimationCapture"),v.captureAnimationState(),v!==g&&g.captureAnimationState()}function x(xt){return F("dragOverCompleted",{insertion:xt}),xt&&(d?c._hideClone():c._showClone(v),v!==g&&(H(h,O?O.options.ghostClass:c.options.ghostClass,!1),H(h,l.ghostClass,!0)),O!==v&&v!==p.active?O=v:v===p.active&&O&&(O=null),g===v&&(v._ignoreWhileAnimating=n),v.animateAll(function(){F("dragOverAnimationComplete"),v._ignoreWhileAnimating=null}),v!==g&&(g.animateAll(),g._ignoreWhileAnimating=null)),(n===h&&!h.animated||n===e&&!n.animated)&&(Et=null),!l.dragoverBubble&&!t.rootEl&&n!==document&&(h.parentNode[I]._isOutsideThisEl(t.target),!xt&&gt(t)),!l.dragoverBubble&&t.stopPropagation&&t.stopPropagation(),E=!0}function B(){U=k(h),nt=k(h,l.draggable),P({sortable:v,name:"change",toEl:e,newIndex:U,newDraggableIndex:nt,originalEvent:t})}if(t.preventDefault!==void 0&&t.cancelable&&t.preventDefault(),n=j(n,l.draggable,e,!0),F("dragOver"),p.eventCanceled)return E;if(h.contains(t.target)||n.animated&&n.animatingX&&n.animatingY||v._ignoreWhileAnimating===n)return x(!1);if(de=!1,c&&!l.disabled&&(d?u||(a=A!==w):O===this||(this.lastPutMode=ne.checkPull(this,c,h,t))&&s.checkPut(this,c,h,t))){if(b=this._getDirection(t,n)==="vertical",o=C(h),F("dragOverValid"),p.eventCanceled)return E;if(a)return A=w,W(),this._hideClone(),F("revert"),p.eventCanceled||(mt?w.insertBefore(h,mt):w.appendChild(h)),x(!0);var M=Xe(e,l.draggable);if(!M||Ei(t,b,this)&&!M.animated){if(M===h)return x(!1);if(M&&e===t.target&&(n=M),n&&(r=C(n)),re(w,e,h,o,n,r,t,!!n)!==!1)return W(),M&&M.nextSibling?e.insertBefore(h,M.nextSibling):e.appendChild(h),A=e,B(),x(!0)}else if(M&&_i(t,b,this)){var at=Dt(e,0,l,!0);if(at===h)return x(!1);if(n=at,r=C(n),re(w,e,h,o,n,r,t,!1)!==!1)return W(),e.insertBefore(h,at),A=e,B(),x(!0)}else if(n.parentNode===e){r=C(n);var X=0,st,Ct=h.parentNode!==e,R=!gi(h.animated&&h.toRect||o,n.animated&&n.toRect||r,b),$t=b?"top":"left",J=mn(n,"top","top")||mn(h,"top","top"),Ot=J?J.scrollTop:void 0;Et!==n&&(st=r[$t],Wt=!1,ie=!R&&l.invertSwap||Ct),X=wi(t,n,r,b,R?1:l.swapThreshold,l.invertedSwapThreshold==null?l.swapThreshold:l.invertedSwapThreshold,ie,Et===n);var q;if(X!==0){var lt=k(h);do lt-=X,q=A.children[lt];while(q&&(f(q,"display")==="none"||q===m))}if(X===0||q===n)return x(!1);Et=n,zt=X;var Tt=n.nextElementSibling,Q=!1;Q=X===1;var Kt=re(w,e,h,o,n,r,t,Q);if(Kt!==!1)return(Kt===1||Kt===-1)&&(Q=Kt===1),ke=!0,setTimeout(yi,30),W(),Q&&!Tt?e.appendChild(h):n.parentNode.insertBefore(h,Q?Tt:n),J&&$n(J,0,Ot-J.scrollTop),A=h.parentNode,st!==void 0&&!ie&&(le=Math.abs(st-C(n)[$t])),B(),x(!0)}if(e.contains(h))return x(!1)}return!1},_ignoreWhileAnimating:null,_offMoveEvents:function(){y(document,"mousemove",this._onTouchMove),y(document,"touchmove",this._onTouchMove),y(document,"pointermove",this._onTouchMove),y(document,"dragover",gt),y(document,"mousemove",gt),y(document,"touchmove",gt)},_offUpEvents:function(){var t=this.el.ownerDocument;y(t,"mouseup",this._onDrop),y(t,"touchend",this._onDrop),y(t,"pointerup",this._onDrop),y(t,"pointercancel",this._onDrop),y(t,"touchcancel",this._onDrop),y(document,"selectstart",this)},_onDrop:function(t){var e=this.el,n=this.options;if(U=k(h),nt=k(h,n.draggable),N("drop",this,{evt:t}),A=h&&h.parentNode,U=k(h),nt=k(h,n.draggable),p.eventCanceled){this._nulling();return}wt=!1,ie=!1,Wt=!1,clearInterval(this._loopId),clearTimeout(this._dragStartTimer),Fe(this.cloneId),Fe(this._dragStartId),this.nativeDraggable&&(y(document,"drop",this),y(e,"dragstart",this._onDragStart)),this._offMoveEvents(),this._offUpEvents(),jt&&f(document.body,"user-select",""),f(h,"transform",""),t&&(Ft&&(t.cancelable&&t.preventDefault(),!n.dropBubble&&t.stopPropagation()),m&&m.parentNode&&m.parentNode.removeChild(m),(w===A||O&&O.lastPutMode!=="clone")&&S&&S.parentNode&&S.parentNode.removeChild(S),h&&(this.nativeDraggable&&y(h,"dragend",this),Me(h),h.style["will-change"]="",Ft&&!wt&&H(h,O?O.options.ghostClass:this.options.ghostClass,!1),H(h,this.options.chosenClass,!1),P({sortable:this,name:"unchoose",toEl:A,newIndex:null,newDraggableIndex:null,originalEvent:t}),w!==A?(U>=0&&(P({rootEl:A,name:"add",toEl:A,fromEl:w,originalEvent:t}),P({sortable:this,name:"remove",toEl:A,originalEvent:t}),P({rootEl:A,name:"sort",toEl:A,fromEl:w,originalEvent:t}),P({sortable:this,name:"sort",toEl:A,originalEvent:t})),O&&O.save()):U!==St&&U>=0&&(P({sortable:this,name:"update",toEl:A,originalEvent:t}),P({sortable:this,name:"sort",toEl:A,originalEvent:t})),p.active&&((U==null||U===-1)&&(U=St,nt=Yt),P({sortable:this,name:"end",toEl:A,originalEvent:t}),this.save()))),this._nulling()},_nulling:function(){N("nulling",this),w=h=A=m=mt=S=se=it=pt=L=Ft=U=nt=St=Yt=Et=zt=O=ne=p.dragged=p.ghost=p.clone=p.active=null,pe.forEach(function(t){t.checked=!0}),pe.length=Pe=Ne=0},handleEvent:function(t){switch(t.type){case"drop":case"dragend":this._onDrop(t);break;case"dragenter":case"dragover":h&&(this._onDragOver(t),bi(t));break;case"selectstart":t.preventDefault();break}},toArray:function(){for(var t=[],e,n=this.el.children,o=0,r=n.length,a=this.options;o<r;o++)e=n[o],j(e,a.draggable,this.el,!1)&&t.push(e.getAttribute(a.dataIdAttr)||Ai(e));return t},sort:function(t,e){var n={},o=this.el;this.toArray().forEach(function(r,a){var l=o.children[a];j(l,this.options.draggable,o,!1)&&(n[r]=l)},this),e&&this.captureAnimationState(),t.forEach(function(r){n[r]&&(o.removeChild(n[r]),o.appendChild(n[r]))}),e&&this.animateAll()},save:function(){var t=this.options.store;t&&t.set&&t.set(this)},closest:function(t,e){return j(t,e||this.options.draggable,this.el,!1)},option:function(t,e){var n=this.options;if(e===void 0)return n[t];var o=Gt.modifyOption(this,t,e);typeof o<"u"?n[t]=o:n[t]=e,t==="group"&&Nn(n)},destroy:function(){N("destroy",this);var t=this.el;t[I]=null,y(t,"mousedown",this._onTapStart),y(t,"touchstart",this._onTapStart),y(t,"pointerdown",this._onTapStart),this.nativeDraggable&&(y(t,"dragover",this),y(t,"dragenter",this)),Array.prototype.forEach.call(t.querySelectorAll("[draggable]"),function(e){e.removeAttribute("draggable")}),this._onDrop(),this._disableDelayedDragEvents(),fe.splice(fe.indexOf(this.el),1),this.el=t=null},_hideClone:function(){if(!it){if(N("hideClone",this),p.eventCanceled)return;f(S,"display","none"),this.options.removeCloneOnHide&&S.parentNode&&S.parentNode.removeChild(S),it=!0}},_showClone:function(t){if(t.lastPutMode!=="clone"){this._hideClone();return}if(it){if(N("showClone",this),p.eventCanceled)return;h.parentNode==w&&!this.options.group.revertClone?w.insertBefore(S,h):mt?w.insertBefore(S,mt):w.appendChild(S),this.options.group.revertClone&&this.animate(h,S),f(S,"display",""),it=!1}}};function bi(i){i.dataTransfer&&(i.dataTransfer.dropEffect="move"),i.cancelable&&i.preventDefault()}function re(i,t,e,n,o,r,a,l){var s,c=i[I],d=c.options.onMove,u;return window.CustomEvent&&!Z&&!qt?s=new CustomEvent("move",{bubbles:!0,cancelable:!0}):(s=document.createEvent("Event"),s.initEvent("move",!0,!0)),s.to=t,s.from=i,s.dragged=e,s.draggedRect=n,s.related=o||t,s.relatedRect=r||C(t),s.willInsertAfter=l,s.originalEvent=a,i.dispatchEvent(s),d&&(u=d.call(c,s,a)),u}function Me(i){i.draggable=!1}function yi(){ke=!1}function _i(i,t,e){var n=C(Dt(e.el,0,e.options,!0)),o=Tn(e.el,e.options,m),r=10;return t?i.clientX<o.left-r||i.clientY<n.top&&i.clientX<n.right:i.clientY<o.top-r||i.clientY<n.bottom&&i.clientX<n.left}function Ei(i,t,e){var n=C(Xe(e.el,e.options.draggable)),o=Tn(e.el,e.options,m),r=10;return t?i.clientX>o.right+r||i.clientY>n.bottom&&i.clientX>n.left:i.clientY>o.bottom+r||i.clientX>n.right&&i.clientY>n.top}function wi(i,t,e,n,o,r,a,l){var s=n?i.clientY:i.clientX,c=n?e.height:e.width,d=n?e.top:e.left,u=n?e.bottom:e.right,g=!1;if(!a){if(l&&le<c*o){if(!Wt&&(zt===1?s>d+c*r/2:s<u-c*r/2)&&(Wt=!0),Wt)g=!0;else if(zt===1?s<d+le:s>u-le)return-zt}else if(s>d+c*(1-o)/2&&s<u-c*(1-o)/2)return Si(t)}return g=g||a,g&&(s<d+c*r/2||s>u-c*r/2)?s>d+c/2?1:-1:0}function Si(i){return k(h)<k(i)?1:-1}function Ai(i){for(var t=i.tagName+i.className+i.src+i.href+i.textContent,e=t.length,n=0;e--;)n+=t.charCodeAt(e);return n.toString(36)}function Di(i){pe.length=0;for(var t=i.getElementsByTagName("input"),e=t.length;e--;){var n=t[e];n.checked&&pe.push(n)}}function ce(i){return setTimeout(i,0)}function Fe(i){return clearTimeout(i)}me&&_(document,"touchmove",function(i){(p.active||wt)&&i.cancelable&&i.preventDefault()});p.utils={on:_,off:y,css:f,find:Dn,is:function(t,e){return!!j(t,e,t,!1)},extend:si,throttle:Cn,closest:j,toggleClass:H,clone:On,index:k,nextTick:ce,cancelNextTick:Fe,detectDirection:Pn,getChild:Dt,expando:I};p.get=function(i){return i[I]};p.mount=function(){for(var i=arguments.length,t=new Array(i),e=0;e<i;e++)t[e]=arguments[e];t[0].constructor===Array&&(t=t[0]),t.forEach(function(n){if(!n.prototype||!n.prototype.constructor)throw"Sortable: Mounted plugin must be a constructor function, not ".concat({}.toString.call(n));n.utils&&(p.utils=z(z({},p.utils),n.utils)),Gt.mount(n)})};p.create=function(i,t){return new p(i,t)};p.version=ri;var D=[],Bt,Be,Le=!1,Re,He,ge,Lt;function Ci(){function i(){this.defaults={scroll:!0,forceAutoScrollFallback:!1,scrollSensitivity:30,scrollSpeed:10,bubbleScroll:!0};for(var t in this)t.charAt(0)==="_"&&typeof this[t]=="function"&&(this[t]=this[t].bind(this))}return i.prototype={dragStarted:function(e){var n=e.originalEvent;this.sortable.nativeDraggable?_(document,"dragover",this._handleAutoScroll):this.options.supportPointer?_(document,"pointermove",this._handleFallbackAutoScroll):n.touches?_(document,"touchmove",this._handleFallbackAutoScroll):_(document,"mousemove",this._handleFallbackAutoScroll)},dragOverCompleted:function(e){var n=e.originalEvent;!this.options.dragOverBubble&&!n.rootEl&&this._handleAutoScroll(n)},drop:function(){this.sortable.nativeDraggable?y(document,"dragover",this._handleAutoScroll):(y(document,"pointermove",this._handleFallbackAutoScroll),y(document,"touchmove",this._handleFallbackAutoScroll),y(document,"mousemove",this._handleFallbackAutoScroll)),En(),ue(),li()},nulling:function(){ge=Be=Bt=Le=Lt=Re=He=null,D.length=0},_handleFallbackAutoScroll:function(e){this._handleAutoScroll(e,!0)},_handleAutoScroll:function(e,n){var o=this,r=(e.touches?e.touches[0]:e).clientX,a=(e.touches?e.touches[0]:e).clientY,l=document.elementFromPoint(r,a);if(ge=e,n||this.options.forceAutoScrollFallback||qt||Z||jt){Ue(e,this.options,l,n);var s=ot(l,!0);Le&&(!Lt||r!==Re||a!==He)&&(Lt&&En(),Lt=setInterval(function(){var c=ot(document.elementFromPoint(r,a),!0);c!==s&&(s=c,ue()),Ue(e,o.options,c,n)},10),Re=r,He=a)}else{if(!this.options.bubbleScroll||ot(l,!0)===Y()){ue();return}Ue(e,this.options,ot(l,!1),!1)}}},V(i,{pluginName:"scroll",initializeByDefault:!0})}function ue(){D.forEach(function(i){clearInterval(i.pid)}),D=[]}function En(){clearInterval(Lt)}var Ue=Cn(function(i,t,e,n){if(t.scroll){var o=(i.touches?i.touches[0]:i).clientX,r=(i.touches?i.touches[0]:i).clientY,a=t.scrollSensitivity,l=t.scrollSpeed,s=Y(),c=!1,d;Be!==e&&(Be=e,ue(),Bt=t.scroll,d=t.scrollFn,Bt===!0&&(Bt=ot(e,!0)));var u=0,g=Bt;do{var b=g,v=C(b),E=v.top,F=v.bottom,W=v.left,x=v.right,B=v.width,M=v.height,at=void 0,X=void 0,st=b.scrollWidth,Ct=b.scrollHeight,R=f(b),$t=b.scrollLeft,J=b.scrollTop;b===s?(at=B<st&&(R.overflowX==="auto"||R.overflowX==="scroll"||R.overflowX==="visible"),X=M<Ct&&(R.overflowY==="auto"||R.overflowY==="scroll"||R.overflowY==="visible")):(at=B<st&&(R.overflowX==="auto"||R.overflowX==="scroll"),X=M<Ct&&(R.overflowY==="auto"||R.overflowY==="scroll"));var Ot=at&&(Math.abs(x-o)<=a&&$t+B<st)-(Math.abs(W-o)<=a&&!!$t),q=X&&(Math.abs(F-r)<=a&&J+M<Ct)-(Math.abs(E-r)<=a&&!!J);if(!D[u])for(var lt=0;lt<=u;lt++)D[lt]||(D[lt]={});(D[u].vx!=Ot||D[u].vy!=q||D[u].el!==b)&&(D[u].el=b,D[u].vx=Ot,D[u].vy=q,clearInterval(D[u].pid),(Ot!=0||q!=0)&&(c=!0,D[u].pid=setInterval(function(){n&&this.layer===0&&p.active._onTouchMove(ge);var Tt=D[this.layer].vy?D[this.layer].vy*l:0,Q=D[this.layer].vx?D[this.layer].vx*l:0;typeof d=="function"&&d.call(p.dragged.parentNode[I],Q,Tt,i,ge,D[this.layer].el)!=="continue"||$n(D[this.layer].el,Q,Tt)}.bind({layer:u}),24))),u++}while(t.bubbleScroll&&g!==s&&(g=ot(g,!1)));Le=c}},30),Rn=function(t){var e=t.originalEvent,n=t.putSortable,o=t.dragEl,r=t.activeSortable,a=t.dispatchSortableEvent,l=t.hideGhostForTarget,s=t.unhideGhostForTarget;if(e){var c=n||r;l();var d=e.changedTouches&&e.changedTouches.length?e.changedTouches[0]:e,u=document.elementFromPoint(d.clientX,d.clientY);s(),c&&!c.el.contains(u)&&(a("spill"),this.onSpill({dragEl:o,putSortable:n}))}};function Ye(){}Ye.prototype={startIndex:null,dragStart:function(t){var e=t.oldDraggableIndex;this.startIndex=e},onSpill:function(t){var e=t.dragEl,n=t.putSortable;this.sortable.captureAnimationState(),n&&n.captureAnimationState();var o=Dt(this.sortable.el,this.startIndex,this.options);o?this.sortable.el.insertBefore(e,o):this.sortable.el.appendChild(e),this.sortable.animateAll(),n&&n.animateAll()},drop:Rn};V(Ye,{pluginName:"revertOnSpill"});function ze(){}ze.prototype={onSpill:function(t){var e=t.dragEl,n=t.putSortable,o=n||this.sortable;o.captureAnimationState(),e.parentNode&&e.parentNode.removeChild(e),o.animateAll()},drop:Rn};V(ze,{pluginName:"removeOnSpill"});p.mount(new Ci);p.mount(ze,Ye);var Hn=p;var rt=class extends et{constructor(){super(...arguments);this.title="";this.value="";this.items=[]}firstUpdated(){new Hn(this.sortContainer,{animation:150,ghostClass:"opacity-25",onEnd:e=>{this.value=`Moved from ${e.oldIndex} to ${e.newIndex}`,this.dispatchEvent(new CustomEvent("change",{detail:`Moved from ${e.oldIndex} to ${e.newIndex}`}))}})}createRenderRoot(){return this}render(){return console.log(this),$e`
+var Jn=Object.defineProperty;var Zn=Object.getOwnPropertyDescriptor;var ue=(i,e,t,n)=>{for(var r=n>1?void 0:n?Zn(e,t):e,o=i.length-1,a;o>=0;o--)(a=i[o])&&(r=(n?a(e,t,r):a(r))||r);return n&&r&&Jn(e,t,r),r};var et=globalThis,St=et.ShadowRoot&&(et.ShadyCSS===void 0||et.ShadyCSS.nativeShadow)&&"adoptedStyleSheets"in Document.prototype&&"replace"in CSSStyleSheet.prototype,ln=Symbol(),sn=new WeakMap,wt=class{_$cssResult$=!0;cssText;_styleSheet;_strings;constructor(e,t,n){if(n!==ln)throw new Error("CSSResult is not constructable. Use `unsafeCSS` or `css` instead.");this.cssText=e,this._strings=t}get styleSheet(){let e=this._styleSheet,t=this._strings;if(St&&e===void 0){let n=t!==void 0&&t.length===1;n&&(e=sn.get(t)),e===void 0&&((this._styleSheet=e=new CSSStyleSheet).replaceSync(this.cssText),n&&sn.set(t,e))}return e}toString(){return this.cssText}};var ei=i=>new wt(typeof i=="string"?i:String(i),void 0,ln);var dn=(i,e)=>{if(St)i.adoptedStyleSheets=e.map(t=>t instanceof CSSStyleSheet?t:t.styleSheet);else for(let t of e){let n=document.createElement("style"),r=et.litNonce;r!==void 0&&n.setAttribute("nonce",r),n.textContent=t.cssText,i.appendChild(n)}},ti=i=>{let e="";for(let t of i.cssRules)e+=t.cssText;return ei(e)},Tt=St?i=>i:i=>i instanceof CSSStyleSheet?ti(i):i;var{is:ni,defineProperty:ii,getOwnPropertyDescriptor:ri,getOwnPropertyNames:oi,getOwnPropertySymbols:ai,getPrototypeOf:si}=Object,li=!1,Ie=globalThis;li&&(Ie.customElements??=customElements);var cn=Ie.trustedTypes,di=cn?cn.emptyScript:"",ci=Ie.reactiveElementPolyfillSupport,ui=void 0,Me=(i,e)=>i,$e={toAttribute(i,e){switch(e){case Boolean:i=i?di:null;break;case Object:case Array:i=i==null?i:JSON.stringify(i);break}return i},fromAttribute(i,e){let t=i;switch(e){case Boolean:t=i!==null;break;case Number:t=i===null?null:Number(i);break;case Object:case Array:try{t=JSON.parse(i)}catch{t=null}break}return t}},tt=(i,e)=>!ni(i,e),un={attribute:!0,type:String,converter:$e,reflect:!1,useDefault:!1,hasChanged:tt};Symbol.metadata??=Symbol("metadata");Ie.litPropertyMetadata??=new WeakMap;var q=class extends HTMLElement{static enabledWarnings;static enableWarning;static disableWarning;static addInitializer(e){this.__prepare(),(this._initializers??=[]).push(e)}static _initializers;static __attributeToPropertyMap;static finalized;static elementProperties;static properties;static elementStyles=[];static styles;static get observedAttributes(){return this.finalize(),this.__attributeToPropertyMap&&[...this.__attributeToPropertyMap.keys()]}__instanceProperties=void 0;static createProperty(e,t=un){if(t.state&&(t.attribute=!1),this.__prepare(),this.prototype.hasOwnProperty(e)&&(t=Object.create(t),t.wrapped=!0),this.elementProperties.set(e,t),!t.noAccessor){let n=Symbol(),r=this.getPropertyDescriptor(e,n,t);r!==void 0&&ii(this.prototype,e,r)}}static getPropertyDescriptor(e,t,n){let{get:r,set:o}=ri(this.prototype,e)??{get(){return this[t]},set(a){this[t]=a}};return{get:r,set(a){let l=r?.call(this);o?.call(this,a),this.requestUpdate(e,l,n)},configurable:!0,enumerable:!0}}static getPropertyOptions(e){return this.elementProperties.get(e)??un}static __prepare(){if(this.hasOwnProperty(Me("elementProperties",this)))return;let e=si(this);e.finalize(),e._initializers!==void 0&&(this._initializers=[...e._initializers]),this.elementProperties=new Map(e.elementProperties)}static finalize(){if(this.hasOwnProperty(Me("finalized",this)))return;if(this.finalized=!0,this.__prepare(),this.hasOwnProperty(Me("properties",this))){let t=this.properties,n=[...oi(t),...ai(t)];for(let r of n)this.createProperty(r,t[r])}let e=this[Symbol.metadata];if(e!==null){let t=litPropertyMetadata.get(e);if(t!==void 0)for(let[n,r]of t)this.elementProperties.set(n,r)}this.__attributeToPropertyMap=new Map;for(let[t,n]of this.elementProperties){let r=this.__attributeNameForProperty(t,n);r!==void 0&&this.__attributeToPropertyMap.set(r,t)}this.elementStyles=this.finalizeStyles(this.styles)}static shadowRootOptions={mode:"open"};static finalizeStyles(e){let t=[];if(Array.isArray(e)){let n=new Set(e.flat(1/0).reverse());for(let r of n)t.unshift(Tt(r))}else e!==void 0&&t.push(Tt(e));return t}renderRoot;static __attributeNameForProperty(e,t){let n=t.attribute;return n===!1?void 0:typeof n=="string"?n:typeof e=="string"?e.toLowerCase():void 0}__updatePromise;isUpdatePending=!1;hasUpdated=!1;_$changedProperties;__defaultValues;__reflectingProperties;__reflectingProperty=null;__controllers;constructor(){super(),this.__initialize()}__initialize(){this.__updatePromise=new Promise(e=>this.enableUpdating=e),this._$changedProperties=new Map,this.__saveInstanceProperties(),this.requestUpdate(),this.constructor._initializers?.forEach(e=>e(this))}addController(e){(this.__controllers??=new Set).add(e),this.renderRoot!==void 0&&this.isConnected&&e.hostConnected?.()}removeController(e){this.__controllers?.delete(e)}__saveInstanceProperties(){let e=new Map,t=this.constructor.elementProperties;for(let n of t.keys())this.hasOwnProperty(n)&&(e.set(n,this[n]),delete this[n]);e.size>0&&(this.__instanceProperties=e)}createRenderRoot(){let e=this.shadowRoot??this.attachShadow(this.constructor.shadowRootOptions);return dn(e,this.constructor.elementStyles),e}connectedCallback(){this.renderRoot??=this.createRenderRoot(),this.enableUpdating(!0),this.__controllers?.forEach(e=>e.hostConnected?.())}enableUpdating(e){}disconnectedCallback(){this.__controllers?.forEach(e=>e.hostDisconnected?.())}attributeChangedCallback(e,t,n){this._$attributeToProperty(e,n)}__propertyToAttribute(e,t){let r=this.constructor.elementProperties.get(e),o=this.constructor.__attributeNameForProperty(e,r);if(o!==void 0&&r.reflect===!0){let l=(r.converter?.toAttribute!==void 0?r.converter:$e).toAttribute(t,r.type);this.__reflectingProperty=e,l==null?this.removeAttribute(o):this.setAttribute(o,l),this.__reflectingProperty=null}}_$attributeToProperty(e,t){let n=this.constructor,r=n.__attributeToPropertyMap.get(e);if(r!==void 0&&this.__reflectingProperty!==r){let o=n.getPropertyOptions(r),a=typeof o.converter=="function"?{fromAttribute:o.converter}:o.converter?.fromAttribute!==void 0?o.converter:$e;this.__reflectingProperty=r;let l=a.fromAttribute(t,o.type);this[r]=l??this.__defaultValues?.get(r)??l,this.__reflectingProperty=null}}requestUpdate(e,t,n){if(e!==void 0){let r=this.constructor,o=this[e];if(n??=r.getPropertyOptions(e),(n.hasChanged??tt)(o,t)||n.useDefault&&n.reflect&&o===this.__defaultValues?.get(e)&&!this.hasAttribute(r.__attributeNameForProperty(e,n)))this._$changeProperty(e,t,n);else return}this.isUpdatePending===!1&&(this.__updatePromise=this.__enqueueUpdate())}_$changeProperty(e,t,{useDefault:n,reflect:r,wrapped:o},a){n&&!(this.__defaultValues??=new Map).has(e)&&(this.__defaultValues.set(e,a??t??this[e]),o!==!0||a!==void 0)||(this._$changedProperties.has(e)||(!this.hasUpdated&&!n&&(t=void 0),this._$changedProperties.set(e,t)),r===!0&&this.__reflectingProperty!==e&&(this.__reflectingProperties??=new Set).add(e))}async __enqueueUpdate(){this.isUpdatePending=!0;try{await this.__updatePromise}catch(t){Promise.reject(t)}let e=this.scheduleUpdate();return e!=null&&await e,!this.isUpdatePending}scheduleUpdate(){return this.performUpdate()}performUpdate(){if(!this.isUpdatePending)return;if(ui?.({kind:"update"}),!this.hasUpdated){if(this.renderRoot??=this.createRenderRoot(),this.__instanceProperties){for(let[r,o]of this.__instanceProperties)this[r]=o;this.__instanceProperties=void 0}let n=this.constructor.elementProperties;if(n.size>0)for(let[r,o]of n){let{wrapped:a}=o,l=this[r];a===!0&&!this._$changedProperties.has(r)&&l!==void 0&&this._$changeProperty(r,void 0,o,l)}}let e=!1,t=this._$changedProperties;try{e=this.shouldUpdate(t),e?(this.willUpdate(t),this.__controllers?.forEach(n=>n.hostUpdate?.()),this.update(t)):this.__markUpdated()}catch(n){throw e=!1,this.__markUpdated(),n}e&&this._$didUpdate(t)}willUpdate(e){}_$didUpdate(e){this.__controllers?.forEach(t=>t.hostUpdated?.()),this.hasUpdated||(this.hasUpdated=!0,this.firstUpdated(e)),this.updated(e)}__markUpdated(){this._$changedProperties=new Map,this.isUpdatePending=!1}get updateComplete(){return this.getUpdateComplete()}getUpdateComplete(){return this.__updatePromise}shouldUpdate(e){return!0}update(e){this.__reflectingProperties&&=this.__reflectingProperties.forEach(t=>this.__propertyToAttribute(t,this[t])),this.__markUpdated()}updated(e){}firstUpdated(e){}};q[Me("elementProperties",q)]=new Map;q[Me("finalized",q)]=new Map;ci?.({ReactiveElement:q});(Ie.reactiveElementVersions??=[]).push("2.1.1");var ve=globalThis,w=void 0;var K=ve.ShadyDOM?.inUse&&ve.ShadyDOM?.noPatch===!0?ve.ShadyDOM.wrap:i=>i,nt=ve.trustedTypes,pn=nt?nt.createPolicy("lit-html",{createHTML:i=>i}):void 0,pi=i=>i,kt=(i,e,t)=>pi,fi=i=>{if(_e!==kt)throw new Error("Attempted to overwrite existing lit-html security policy. setSanitizeDOMValueFactory should be called at most once.");_e=i};var Dt=(i,e,t)=>_e(i,e,t),En="$lit$",ie=`lit$${Math.random().toFixed(9).slice(2)}$`,wn="?"+ie,hi=`<${wn}>`,he=document,Ve=()=>he.createComment(""),Ue=i=>i===null||typeof i!="object"&&typeof i!="function",Mt=Array.isArray,mi=i=>Mt(i)||typeof i?.[Symbol.iterator]=="function",Ct=`[ 	
+\f\r]`,gi=`[^ 	
+\f\r"'\`<>=]`,yi=`[^\\s"'>=/]`,Le=/<(?:(!--|\/[^a-zA-Z])|(\/?[a-zA-Z][^>\s]*)|(\/?$))/g,fn=1,Pt=2,bi=3,hn=/-->/g,mn=/>/g,pe=new RegExp(`>|${Ct}(?:(${yi}+)(${Ct}*=${Ct}*(?:${gi}|("|')|))|$)`,"g"),vi=0,gn=1,_i=2,yn=3,bn=/'/g,vn=/"/g,Sn=/^(?:script|style|textarea|title)$/i,Ei=1,it=2,rt=3,$t=1,ot=2,wi=3,Si=4,Ti=5,It=6,Ci=7,Lt=i=>(e,...t)=>({_$litType$:i,strings:e,values:t}),Vt=Lt(Ei),pr=Lt(it),fr=Lt(rt),me=Symbol.for("lit-noChange"),P=Symbol.for("lit-nothing"),_n=new WeakMap,fe=he.createTreeWalker(he,129),_e=kt;function Tn(i,e){if(!Mt(i)||!i.hasOwnProperty("raw")){let t="invalid template strings array";throw new Error(t)}return pn!==void 0?pn.createHTML(e):e}var Pi=(i,e)=>{let t=i.length-1,n=[],r=e===it?"<svg>":e===rt?"<math>":"",o,a=Le;for(let s=0;s<t;s++){let d=i[s],p=-1,u,f=0,h;for(;f<d.length&&(a.lastIndex=f,h=a.exec(d),h!==null);)f=a.lastIndex,a===Le?h[fn]==="!--"?a=hn:h[fn]!==void 0?a=mn:h[Pt]!==void 0?(Sn.test(h[Pt])&&(o=new RegExp(`</${h[Pt]}`,"g")),a=pe):h[bi]!==void 0&&(a=pe):a===pe?h[vi]===">"?(a=o??Le,p=-1):h[gn]===void 0?p=-2:(p=a.lastIndex-h[_i].length,u=h[gn],a=h[yn]===void 0?pe:h[yn]==='"'?vn:bn):a===vn||a===bn?a=pe:a===hn||a===mn?a=Le:(a=pe,o=void 0);let b=a===pe&&i[s+1].startsWith("/>")?" ":"";r+=a===Le?d+hi:p>=0?(n.push(u),d.slice(0,p)+En+d.slice(p)+ie+b):d+ie+(p===-2?s:b)}let l=r+(i[t]||"<?>")+(e===it?"</svg>":e===rt?"</math>":"");return[Tn(i,l),n]},We=class i{el;parts=[];constructor({strings:e,["_$litType$"]:t},n){let r,o=0,a=0,l=e.length-1,s=this.parts,[d,p]=Pi(e,t);if(this.el=i.createElement(d,n),fe.currentNode=this.el.content,t===it||t===rt){let u=this.el.content.firstChild;u.replaceWith(...u.childNodes)}for(;(r=fe.nextNode())!==null&&s.length<l;){if(r.nodeType===1){if(r.hasAttributes())for(let u of r.getAttributeNames())if(u.endsWith(En)){let f=p[a++],b=r.getAttribute(u).split(ie),E=/([.?@])?(.*)/.exec(f);s.push({type:$t,index:o,name:E[2],strings:b,ctor:E[1]==="."?Rt:E[1]==="?"?At:E[1]==="@"?Ot:we}),r.removeAttribute(u)}else u.startsWith(ie)&&(s.push({type:It,index:o}),r.removeAttribute(u));if(Sn.test(r.tagName)){let u=r.textContent.split(ie),f=u.length-1;if(f>0){r.textContent=nt?nt.emptyScript:"";for(let h=0;h<f;h++)r.append(u[h],Ve()),fe.nextNode(),s.push({type:ot,index:++o});r.append(u[f],Ve())}}}else if(r.nodeType===8)if(r.data===wn)s.push({type:ot,index:o});else{let f=-1;for(;(f=r.data.indexOf(ie,f+1))!==-1;)s.push({type:Ci,index:o}),f+=ie.length-1}o++}w&&w({kind:"template prep",template:this,clonableTemplate:this.el,parts:this.parts,strings:e})}static createElement(e,t){let n=he.createElement("template");return n.innerHTML=e,n}};function Ee(i,e,t=i,n){if(e===me)return e;let r=n!==void 0?t.__directives?.[n]:t.__directive,o=Ue(e)?void 0:e._$litDirective$;return r?.constructor!==o&&(r?._$notifyDirectiveConnectionChanged?.(!1),o===void 0?r=void 0:(r=new o(i),r._$initialize(i,t,n)),n!==void 0?(t.__directives??=[])[n]=r:t.__directive=r),r!==void 0&&(e=Ee(i,r._$resolve(i,e.values),r,n)),e}var xt=class{_$template;_$parts=[];_$parent;_$disconnectableChildren=void 0;constructor(e,t){this._$template=e,this._$parent=t}get parentNode(){return this._$parent.parentNode}get _$isConnected(){return this._$parent._$isConnected}_clone(e){let{el:{content:t},parts:n}=this._$template,r=(e?.creationScope??he).importNode(t,!0);fe.currentNode=r;let o=fe.nextNode(),a=0,l=0,s=n[0];for(;s!==void 0;){if(a===s.index){let d;s.type===ot?d=new He(o,o.nextSibling,this,e):s.type===$t?d=new s.ctor(o,s.name,s.strings,this,e):s.type===It&&(d=new Nt(o,this,e)),this._$parts.push(d),s=n[++l]}a!==s?.index&&(o=fe.nextNode(),a++)}return fe.currentNode=he,r}_update(e){let t=0;for(let n of this._$parts)n!==void 0&&(w&&w({kind:"set part",part:n,value:e[t],valueIndex:t,values:e,templateInstance:this}),n.strings!==void 0?(n._$setValue(e,n,t),t+=n.strings.length-2):n._$setValue(e[t])),t++}},He=class i{type=ot;options;_$committedValue=P;__directive;_$startNode;_$endNode;_textSanitizer;_$parent;__isConnected;get _$isConnected(){return this._$parent?._$isConnected??this.__isConnected}_$disconnectableChildren=void 0;constructor(e,t,n,r){this._$startNode=e,this._$endNode=t,this._$parent=n,this.options=r,this.__isConnected=r?.isConnected??!0,this._textSanitizer=void 0}get parentNode(){let e=K(this._$startNode).parentNode,t=this._$parent;return t!==void 0&&e?.nodeType===11&&(e=t.parentNode),e}get startNode(){return this._$startNode}get endNode(){return this._$endNode}_$setValue(e,t=this){e=Ee(this,e,t),Ue(e)?e===P||e==null||e===""?(this._$committedValue!==P&&(w&&w({kind:"commit nothing to child",start:this._$startNode,end:this._$endNode,parent:this._$parent,options:this.options}),this._$clear()),this._$committedValue=P):e!==this._$committedValue&&e!==me&&this._commitText(e):e._$litType$!==void 0?this._commitTemplateResult(e):e.nodeType!==void 0?this._commitNode(e):mi(e)?this._commitIterable(e):this._commitText(e)}_insert(e){return K(K(this._$startNode).parentNode).insertBefore(e,this._$endNode)}_commitNode(e){if(this._$committedValue!==e){if(this._$clear(),_e!==kt){let t=this._$startNode.parentNode?.nodeName;if(t==="STYLE"||t==="SCRIPT"){let n="Forbidden";throw new Error(n)}}w&&w({kind:"commit node",start:this._$startNode,parent:this._$parent,value:e,options:this.options}),this._$committedValue=this._insert(e)}}_commitText(e){if(this._$committedValue!==P&&Ue(this._$committedValue)){let t=K(this._$startNode).nextSibling;this._textSanitizer===void 0&&(this._textSanitizer=Dt(t,"data","property")),e=this._textSanitizer(e),w&&w({kind:"commit text",node:t,value:e,options:this.options}),t.data=e}else{let t=he.createTextNode("");this._commitNode(t),this._textSanitizer===void 0&&(this._textSanitizer=Dt(t,"data","property")),e=this._textSanitizer(e),w&&w({kind:"commit text",node:t,value:e,options:this.options}),t.data=e}this._$committedValue=e}_commitTemplateResult(e){let{values:t,["_$litType$"]:n}=e,r=typeof n=="number"?this._$getTemplate(e):(n.el===void 0&&(n.el=We.createElement(Tn(n.h,n.h[0]),this.options)),n);if(this._$committedValue?._$template===r)w&&w({kind:"template updating",template:r,instance:this._$committedValue,parts:this._$committedValue._$parts,options:this.options,values:t}),this._$committedValue._update(t);else{let o=new xt(r,this),a=o._clone(this.options);w&&w({kind:"template instantiated",template:r,instance:o,parts:o._$parts,options:this.options,fragment:a,values:t}),o._update(t),w&&w({kind:"template instantiated and updated",template:r,instance:o,parts:o._$parts,options:this.options,fragment:a,values:t}),this._commitNode(a),this._$committedValue=o}}_$getTemplate(e){let t=_n.get(e.strings);return t===void 0&&_n.set(e.strings,t=new We(e)),t}_commitIterable(e){Mt(this._$committedValue)||(this._$committedValue=[],this._$clear());let t=this._$committedValue,n=0,r;for(let o of e)n===t.length?t.push(r=new i(this._insert(Ve()),this._insert(Ve()),this,this.options)):r=t[n],r._$setValue(o),n++;n<t.length&&(this._$clear(r&&K(r._$endNode).nextSibling,n),t.length=n)}_$clear(e=K(this._$startNode).nextSibling,t){for(this._$notifyConnectionChanged?.(!1,!0,t);e!==this._$endNode;){let n=K(e).nextSibling;K(e).remove(),e=n}}setConnected(e){this._$parent===void 0&&(this.__isConnected=e,this._$notifyConnectionChanged?.(e))}},we=class{type=$t;element;name;options;strings;_$committedValue=P;__directives;_$parent;_$disconnectableChildren=void 0;_sanitizer;get tagName(){return this.element.tagName}get _$isConnected(){return this._$parent._$isConnected}constructor(e,t,n,r,o){this.element=e,this.name=t,this._$parent=r,this.options=o,n.length>2||n[0]!==""||n[1]!==""?(this._$committedValue=new Array(n.length-1).fill(new String),this.strings=n):this._$committedValue=P,this._sanitizer=void 0}_$setValue(e,t=this,n,r){let o=this.strings,a=!1;if(o===void 0)e=Ee(this,e,t,0),a=!Ue(e)||e!==this._$committedValue&&e!==me,a&&(this._$committedValue=e);else{let l=e;e=o[0];let s,d;for(s=0;s<o.length-1;s++)d=Ee(this,l[n+s],t,s),d===me&&(d=this._$committedValue[s]),a||=!Ue(d)||d!==this._$committedValue[s],d===P?e=P:e!==P&&(e+=(d??"")+o[s+1]),this._$committedValue[s]=d}a&&!r&&this._commitValue(e)}_commitValue(e){e===P?K(this.element).removeAttribute(this.name):(this._sanitizer===void 0&&(this._sanitizer=_e(this.element,this.name,"attribute")),e=this._sanitizer(e??""),w&&w({kind:"commit attribute",element:this.element,name:this.name,value:e,options:this.options}),K(this.element).setAttribute(this.name,e??""))}},Rt=class extends we{type=wi;_commitValue(e){this._sanitizer===void 0&&(this._sanitizer=_e(this.element,this.name,"property")),e=this._sanitizer(e),w&&w({kind:"commit property",element:this.element,name:this.name,value:e,options:this.options}),this.element[this.name]=e===P?void 0:e}},At=class extends we{type=Si;_commitValue(e){w&&w({kind:"commit boolean attribute",element:this.element,name:this.name,value:!!(e&&e!==P),options:this.options}),K(this.element).toggleAttribute(this.name,!!e&&e!==P)}},Ot=class extends we{type=Ti;constructor(e,t,n,r,o){super(e,t,n,r,o)}_$setValue(e,t=this){if(e=Ee(this,e,t,0)??P,e===me)return;let n=this._$committedValue,r=e===P&&n!==P||e.capture!==n.capture||e.once!==n.once||e.passive!==n.passive,o=e!==P&&(n===P||r);w&&w({kind:"commit event listener",element:this.element,name:this.name,value:e,options:this.options,removeListener:r,addListener:o,oldListener:n}),r&&this.element.removeEventListener(this.name,this,n),o&&this.element.addEventListener(this.name,this,e),this._$committedValue=e}handleEvent(e){typeof this._$committedValue=="function"?this._$committedValue.call(this.options?.host??this.element,e):this._$committedValue.handleEvent(e)}},Nt=class{constructor(e,t,n){this.element=e;this._$parent=t,this.options=n}type=It;__directive;_$committedValue;_$parent;_$disconnectableChildren=void 0;options;get _$isConnected(){return this._$parent._$isConnected}_$setValue(e){w&&w({kind:"commit to element binding",element:this.element,value:e,options:this.options}),Ee(this,e)}};var Di=ve.litHtmlPolyfillSupport;Di?.(We,He);(ve.litHtmlVersions??=[]).push("3.3.1");var at=(i,e,t)=>{let n=0,r=t?.renderBefore??e,o=r._$litPart$;if(w&&w({kind:"begin render",id:n,value:i,container:e,options:t,part:o}),o===void 0){let a=t?.renderBefore??null;r._$litPart$=o=new He(e.insertBefore(Ve(),a),a,void 0,t??{})}return o._$setValue(i),w&&w({kind:"end render",id:n,value:i,container:e,options:t,part:o}),o};at.setSanitizer=fi,at.createSanitizer=Dt;var xi=(i,e)=>i;var Ut=globalThis;var re=class extends q{static _$litElement$=!0;renderOptions={host:this};__childPart=void 0;createRenderRoot(){let e=super.createRenderRoot();return this.renderOptions.renderBefore??=e.firstChild,e}update(e){let t=this.render();this.hasUpdated||(this.renderOptions.isConnected=this.isConnected),super.update(e),this.__childPart=at(t,this.renderRoot,this.renderOptions)}connectedCallback(){super.connectedCallback(),this.__childPart?.setConnected(!0)}disconnectedCallback(){super.disconnectedCallback(),this.__childPart?.setConnected(!1)}render(){return me}};re[xi("finalized",re)]=!0;Ut.litElementHydrateSupport?.({LitElement:re});var Ri=Ut.litElementPolyfillSupport;Ri?.({LitElement:re});(Ut.litElementVersions??=[]).push("4.2.1");var Cn=i=>(e,t)=>{t!==void 0?t.addInitializer(()=>{customElements.define(i,e)}):customElements.define(i,e)};var Ai=(i,e,t)=>{let n=e.hasOwnProperty(t);return e.constructor.createProperty(t,i),n?Object.getOwnPropertyDescriptor(e,t):void 0},Oi={attribute:!0,type:String,converter:$e,reflect:!1,hasChanged:tt},Ni=(i=Oi,e,t)=>{let{kind:n,metadata:r}=t,o=globalThis.litPropertyMetadata.get(r);if(o===void 0&&globalThis.litPropertyMetadata.set(r,o=new Map),n==="setter"&&(i=Object.create(i),i.wrapped=!0),o.set(t.name,i),n==="accessor"){let{name:a}=t;return{set(l){let s=e.get.call(this);e.set.call(this,l),this.requestUpdate(a,s,i)},init(l){return l!==void 0&&this._$changeProperty(a,void 0,i,l),l}}}else if(n==="setter"){let{name:a}=t;return function(l){let s=this[a];e.call(this,l),this.requestUpdate(a,s,i)}}throw new Error(`Unsupported decorator location: ${n}`)};function Ke(i){return(e,t)=>typeof t=="object"?Ni(i,e,t):Ai(i,e,t)}var Wt=(i,e,t)=>(t.configurable=!0,t.enumerable=!0,Reflect.decorate&&typeof e!="object"&&Object.defineProperty(i,e,t),t);function Pn(i,e){return((t,n,r)=>{let o=a=>a.renderRoot?.querySelector(i)??null;if(e){let{get:a,set:l}=typeof n=="object"?t:r??(()=>{let s=Symbol();return{get(){return this[s]},set(d){this[s]=d}}})();return Wt(t,n,{get(){let s=a.call(this);return s===void 0&&(s=o(this),(s!==null||this.hasUpdated)&&l.call(this,s)),s}})}else return Wt(t,n,{get(){return o(this)}})})}function Dn(i,e){var t=Object.keys(i);if(Object.getOwnPropertySymbols){var n=Object.getOwnPropertySymbols(i);e&&(n=n.filter(function(r){return Object.getOwnPropertyDescriptor(i,r).enumerable})),t.push.apply(t,n)}return t}function Y(i){for(var e=1;e<arguments.length;e++){var t=arguments[e]!=null?arguments[e]:{};e%2?Dn(Object(t),!0).forEach(function(n){ki(i,n,t[n])}):Object.getOwnPropertyDescriptors?Object.defineProperties(i,Object.getOwnPropertyDescriptors(t)):Dn(Object(t)).forEach(function(n){Object.defineProperty(i,n,Object.getOwnPropertyDescriptor(t,n))})}return i}function ut(i){"@babel/helpers - typeof";return typeof Symbol=="function"&&typeof Symbol.iterator=="symbol"?ut=function(e){return typeof e}:ut=function(e){return e&&typeof Symbol=="function"&&e.constructor===Symbol&&e!==Symbol.prototype?"symbol":typeof e},ut(i)}function ki(i,e,t){return e in i?Object.defineProperty(i,e,{value:t,enumerable:!0,configurable:!0,writable:!0}):i[e]=t,i}function J(){return J=Object.assign||function(i){for(var e=1;e<arguments.length;e++){var t=arguments[e];for(var n in t)Object.prototype.hasOwnProperty.call(t,n)&&(i[n]=t[n])}return i},J.apply(this,arguments)}function Mi(i,e){if(i==null)return{};var t={},n=Object.keys(i),r,o;for(o=0;o<n.length;o++)r=n[o],!(e.indexOf(r)>=0)&&(t[r]=i[r]);return t}function $i(i,e){if(i==null)return{};var t=Mi(i,e),n,r;if(Object.getOwnPropertySymbols){var o=Object.getOwnPropertySymbols(i);for(r=0;r<o.length;r++)n=o[r],!(e.indexOf(n)>=0)&&Object.prototype.propertyIsEnumerable.call(i,n)&&(t[n]=i[n])}return t}var Ii="1.15.6";function Q(i){if(typeof window<"u"&&window.navigator)return!!navigator.userAgent.match(i)}var Z=Q(/(?:Trident.*rv[ :]?11\.|msie|iemobile|Windows Phone)/i),Qe=Q(/Edge/i),xn=Q(/firefox/i),je=Q(/safari/i)&&!Q(/chrome/i)&&!Q(/android/i),en=Q(/iP(ad|od|hone)/i),In=Q(/chrome/i)&&Q(/android/i),Ln={capture:!1,passive:!1};function _(i,e,t){i.addEventListener(e,t,!Z&&Ln)}function v(i,e,t){i.removeEventListener(e,t,!Z&&Ln)}function gt(i,e){if(e){if(e[0]===">"&&(e=e.substring(1)),i)try{if(i.matches)return i.matches(e);if(i.msMatchesSelector)return i.msMatchesSelector(e);if(i.webkitMatchesSelector)return i.webkitMatchesSelector(e)}catch{return!1}return!1}}function Vn(i){return i.host&&i!==document&&i.host.nodeType?i.host:i.parentNode}function z(i,e,t,n){if(i){t=t||document;do{if(e!=null&&(e[0]===">"?i.parentNode===t&&gt(i,e):gt(i,e))||n&&i===t)return i;if(i===t)break}while(i=Vn(i))}return null}var Rn=/\s+/g;function L(i,e,t){if(i&&e)if(i.classList)i.classList[t?"add":"remove"](e);else{var n=(" "+i.className+" ").replace(Rn," ").replace(" "+e+" "," ");i.className=(n+(t?" "+e:"")).replace(Rn," ")}}function m(i,e,t){var n=i&&i.style;if(n){if(t===void 0)return document.defaultView&&document.defaultView.getComputedStyle?t=document.defaultView.getComputedStyle(i,""):i.currentStyle&&(t=i.currentStyle),e===void 0?t:t[e];!(e in n)&&e.indexOf("webkit")===-1&&(e="-webkit-"+e),n[e]=t+(typeof t=="string"?"":"px")}}function De(i,e){var t="";if(typeof i=="string")t=i;else do{var n=m(i,"transform");n&&n!=="none"&&(t=n+" "+t)}while(!e&&(i=i.parentNode));var r=window.DOMMatrix||window.WebKitCSSMatrix||window.CSSMatrix||window.MSCSSMatrix;return r&&new r(t)}function Un(i,e,t){if(i){var n=i.getElementsByTagName(e),r=0,o=n.length;if(t)for(;r<o;r++)t(n[r],r);return n}return[]}function j(){var i=document.scrollingElement;return i||document.documentElement}function x(i,e,t,n,r){if(!(!i.getBoundingClientRect&&i!==window)){var o,a,l,s,d,p,u;if(i!==window&&i.parentNode&&i!==j()?(o=i.getBoundingClientRect(),a=o.top,l=o.left,s=o.bottom,d=o.right,p=o.height,u=o.width):(a=0,l=0,s=window.innerHeight,d=window.innerWidth,p=window.innerHeight,u=window.innerWidth),(e||t)&&i!==window&&(r=r||i.parentNode,!Z))do if(r&&r.getBoundingClientRect&&(m(r,"transform")!=="none"||t&&m(r,"position")!=="static")){var f=r.getBoundingClientRect();a-=f.top+parseInt(m(r,"border-top-width")),l-=f.left+parseInt(m(r,"border-left-width")),s=a+o.height,d=l+o.width;break}while(r=r.parentNode);if(n&&i!==window){var h=De(r||i),b=h&&h.a,E=h&&h.d;h&&(a/=E,l/=b,u/=b,p/=E,s=a+p,d=l+u)}return{top:a,left:l,bottom:s,right:d,width:u,height:p}}}function An(i,e,t){for(var n=se(i,!0),r=x(i)[e];n;){var o=x(n)[t],a=void 0;if(t==="top"||t==="left"?a=r>=o:a=r<=o,!a)return n;if(n===j())break;n=se(n,!1)}return!1}function xe(i,e,t,n){for(var r=0,o=0,a=i.children;o<a.length;){if(a[o].style.display!=="none"&&a[o]!==g.ghost&&(n||a[o]!==g.dragged)&&z(a[o],t.draggable,i,!1)){if(r===e)return a[o];r++}o++}return null}function tn(i,e){for(var t=i.lastElementChild;t&&(t===g.ghost||m(t,"display")==="none"||e&&!gt(t,e));)t=t.previousElementSibling;return t||null}function U(i,e){var t=0;if(!i||!i.parentNode)return-1;for(;i=i.previousElementSibling;)i.nodeName.toUpperCase()!=="TEMPLATE"&&i!==g.clone&&(!e||gt(i,e))&&t++;return t}function On(i){var e=0,t=0,n=j();if(i)do{var r=De(i),o=r.a,a=r.d;e+=i.scrollLeft*o,t+=i.scrollTop*a}while(i!==n&&(i=i.parentNode));return[e,t]}function Li(i,e){for(var t in i)if(i.hasOwnProperty(t)){for(var n in e)if(e.hasOwnProperty(n)&&e[n]===i[t][n])return Number(t)}return-1}function se(i,e){if(!i||!i.getBoundingClientRect)return j();var t=i,n=!1;do if(t.clientWidth<t.scrollWidth||t.clientHeight<t.scrollHeight){var r=m(t);if(t.clientWidth<t.scrollWidth&&(r.overflowX=="auto"||r.overflowX=="scroll")||t.clientHeight<t.scrollHeight&&(r.overflowY=="auto"||r.overflowY=="scroll")){if(!t.getBoundingClientRect||t===document.body)return j();if(n||e)return t;n=!0}}while(t=t.parentNode);return j()}function Vi(i,e){if(i&&e)for(var t in e)e.hasOwnProperty(t)&&(i[t]=e[t]);return i}function Ht(i,e){return Math.round(i.top)===Math.round(e.top)&&Math.round(i.left)===Math.round(e.left)&&Math.round(i.height)===Math.round(e.height)&&Math.round(i.width)===Math.round(e.width)}var Ye;function Wn(i,e){return function(){if(!Ye){var t=arguments,n=this;t.length===1?i.call(n,t[0]):i.apply(n,t),Ye=setTimeout(function(){Ye=void 0},e)}}}function Ui(){clearTimeout(Ye),Ye=void 0}function Hn(i,e,t){i.scrollLeft+=e,i.scrollTop+=t}function Kn(i){var e=window.Polymer,t=window.jQuery||window.Zepto;return e&&e.dom?e.dom(i).cloneNode(!0):t?t(i).clone(!0)[0]:i.cloneNode(!0)}function Fn(i,e,t){var n={};return Array.from(i.children).forEach(function(r){var o,a,l,s;if(!(!z(r,e.draggable,i,!1)||r.animated||r===t)){var d=x(r);n.left=Math.min((o=n.left)!==null&&o!==void 0?o:1/0,d.left),n.top=Math.min((a=n.top)!==null&&a!==void 0?a:1/0,d.top),n.right=Math.max((l=n.right)!==null&&l!==void 0?l:-1/0,d.right),n.bottom=Math.max((s=n.bottom)!==null&&s!==void 0?s:-1/0,d.bottom)}}),n.width=n.right-n.left,n.height=n.bottom-n.top,n.x=n.left,n.y=n.top,n}var M="Sortable"+new Date().getTime();function Wi(){var i=[],e;return{captureAnimationState:function(){if(i=[],!!this.options.animation){var n=[].slice.call(this.el.children);n.forEach(function(r){if(!(m(r,"display")==="none"||r===g.ghost)){i.push({target:r,rect:x(r)});var o=Y({},i[i.length-1].rect);if(r.thisAnimationDuration){var a=De(r,!0);a&&(o.top-=a.f,o.left-=a.e)}r.fromRect=o}})}},addAnimationState:function(n){i.push(n)},removeAnimationState:function(n){i.splice(Li(i,{target:n}),1)},animateAll:function(n){var r=this;if(!this.options.animation){clearTimeout(e),typeof n=="function"&&n();return}var o=!1,a=0;i.forEach(function(l){var s=0,d=l.target,p=d.fromRect,u=x(d),f=d.prevFromRect,h=d.prevToRect,b=l.rect,E=De(d,!0);E&&(u.top-=E.f,u.left-=E.e),d.toRect=u,d.thisAnimationDuration&&Ht(f,u)&&!Ht(p,u)&&(b.top-u.top)/(b.left-u.left)===(p.top-u.top)/(p.left-u.left)&&(s=Ki(b,f,h,r.options)),Ht(u,p)||(d.prevFromRect=p,d.prevToRect=u,s||(s=r.options.animation),r.animate(d,b,u,s)),s&&(o=!0,a=Math.max(a,s),clearTimeout(d.animationResetTimer),d.animationResetTimer=setTimeout(function(){d.animationTime=0,d.prevFromRect=null,d.fromRect=null,d.prevToRect=null,d.thisAnimationDuration=null},s),d.thisAnimationDuration=s)}),clearTimeout(e),o?e=setTimeout(function(){typeof n=="function"&&n()},a):typeof n=="function"&&n(),i=[]},animate:function(n,r,o,a){if(a){m(n,"transition",""),m(n,"transform","");var l=De(this.el),s=l&&l.a,d=l&&l.d,p=(r.left-o.left)/(s||1),u=(r.top-o.top)/(d||1);n.animatingX=!!p,n.animatingY=!!u,m(n,"transform","translate3d("+p+"px,"+u+"px,0)"),this.forRepaintDummy=Hi(n),m(n,"transition","transform "+a+"ms"+(this.options.easing?" "+this.options.easing:"")),m(n,"transform","translate3d(0,0,0)"),typeof n.animated=="number"&&clearTimeout(n.animated),n.animated=setTimeout(function(){m(n,"transition",""),m(n,"transform",""),n.animated=!1,n.animatingX=!1,n.animatingY=!1},a)}}}}function Hi(i){return i.offsetWidth}function Ki(i,e,t,n){return Math.sqrt(Math.pow(e.top-i.top,2)+Math.pow(e.left-i.left,2))/Math.sqrt(Math.pow(e.top-t.top,2)+Math.pow(e.left-t.left,2))*n.animation}var Se=[],Kt={initializeByDefault:!0},Je={mount:function(e){for(var t in Kt)Kt.hasOwnProperty(t)&&!(t in e)&&(e[t]=Kt[t]);Se.forEach(function(n){if(n.pluginName===e.pluginName)throw"Sortable: Cannot mount plugin ".concat(e.pluginName," more than once")}),Se.push(e)},pluginEvent:function(e,t,n){var r=this;this.eventCanceled=!1,n.cancel=function(){r.eventCanceled=!0};var o=e+"Global";Se.forEach(function(a){t[a.pluginName]&&(t[a.pluginName][o]&&t[a.pluginName][o](Y({sortable:t},n)),t.options[a.pluginName]&&t[a.pluginName][e]&&t[a.pluginName][e](Y({sortable:t},n)))})},initializePlugins:function(e,t,n,r){Se.forEach(function(l){var s=l.pluginName;if(!(!e.options[s]&&!l.initializeByDefault)){var d=new l(e,t,e.options);d.sortable=e,d.options=e.options,e[s]=d,J(n,d.defaults)}});for(var o in e.options)if(e.options.hasOwnProperty(o)){var a=this.modifyOption(e,o,e.options[o]);typeof a<"u"&&(e.options[o]=a)}},getEventProperties:function(e,t){var n={};return Se.forEach(function(r){typeof r.eventProperties=="function"&&J(n,r.eventProperties.call(t[r.pluginName],e))}),n},modifyOption:function(e,t,n){var r;return Se.forEach(function(o){e[o.pluginName]&&o.optionListeners&&typeof o.optionListeners[t]=="function"&&(r=o.optionListeners[t].call(e[o.pluginName],n))}),r}};function Fi(i){var e=i.sortable,t=i.rootEl,n=i.name,r=i.targetEl,o=i.cloneEl,a=i.toEl,l=i.fromEl,s=i.oldIndex,d=i.newIndex,p=i.oldDraggableIndex,u=i.newDraggableIndex,f=i.originalEvent,h=i.putSortable,b=i.extraEventProperties;if(e=e||t&&t[M],!!e){var E,W=e.options,X="on"+n.charAt(0).toUpperCase()+n.substr(1);window.CustomEvent&&!Z&&!Qe?E=new CustomEvent(n,{bubbles:!0,cancelable:!0}):(E=document.createEvent("Event"),E.initEvent(n,!0,!0)),E.to=a||t,E.from=l||t,E.item=r||t,E.clone=o,E.oldIndex=s,E.newIndex=d,E.oldDraggableIndex=p,E.newDraggableIndex=u,E.originalEvent=f,E.pullMode=h?h.lastPutMode:void 0;var O=Y(Y({},b),Je.getEventProperties(n,e));for(var H in O)E[H]=O[H];t&&t.dispatchEvent(E),W[X]&&W[X].call(e,E)}}var zi=["evt"],k=function(e,t){var n=arguments.length>2&&arguments[2]!==void 0?arguments[2]:{},r=n.evt,o=$i(n,zi);Je.pluginEvent.bind(g)(e,t,Y({dragEl:c,parentEl:C,ghostEl:y,rootEl:S,nextEl:be,lastDownEl:pt,cloneEl:T,cloneHidden:ae,dragStarted:Fe,putSortable:R,activeSortable:g.active,originalEvent:r,oldIndex:Pe,oldDraggableIndex:Xe,newIndex:V,newDraggableIndex:oe,hideGhostForTarget:Yn,unhideGhostForTarget:Xn,cloneNowHidden:function(){ae=!0},cloneNowShown:function(){ae=!1},dispatchSortableEvent:function(l){N({sortable:t,name:l,originalEvent:r})}},o))};function N(i){Fi(Y({putSortable:R,cloneEl:T,targetEl:c,rootEl:S,oldIndex:Pe,oldDraggableIndex:Xe,newIndex:V,newDraggableIndex:oe},i))}var c,C,y,S,be,pt,T,ae,Pe,V,Xe,oe,st,R,Ce=!1,yt=!1,bt=[],ge,F,Ft,zt,Nn,kn,Fe,Te,Ge,qe=!1,lt=!1,ft,A,Bt=[],qt=!1,vt=[],Et=typeof document<"u",dt=en,Mn=Qe||Z?"cssFloat":"float",Bi=Et&&!In&&!en&&"draggable"in document.createElement("div"),zn=(function(){if(Et){if(Z)return!1;var i=document.createElement("x");return i.style.cssText="pointer-events:auto",i.style.pointerEvents==="auto"}})(),Bn=function(e,t){var n=m(e),r=parseInt(n.width)-parseInt(n.paddingLeft)-parseInt(n.paddingRight)-parseInt(n.borderLeftWidth)-parseInt(n.borderRightWidth),o=xe(e,0,t),a=xe(e,1,t),l=o&&m(o),s=a&&m(a),d=l&&parseInt(l.marginLeft)+parseInt(l.marginRight)+x(o).width,p=s&&parseInt(s.marginLeft)+parseInt(s.marginRight)+x(a).width;if(n.display==="flex")return n.flexDirection==="column"||n.flexDirection==="column-reverse"?"vertical":"horizontal";if(n.display==="grid")return n.gridTemplateColumns.split(" ").length<=1?"vertical":"horizontal";if(o&&l.float&&l.float!=="none"){var u=l.float==="left"?"left":"right";return a&&(s.clear==="both"||s.clear===u)?"vertical":"horizontal"}return o&&(l.display==="block"||l.display==="flex"||l.display==="table"||l.display==="grid"||d>=r&&n[Mn]==="none"||a&&n[Mn]==="none"&&d+p>r)?"vertical":"horizontal"},ji=function(e,t,n){var r=n?e.left:e.top,o=n?e.right:e.bottom,a=n?e.width:e.height,l=n?t.left:t.top,s=n?t.right:t.bottom,d=n?t.width:t.height;return r===l||o===s||r+a/2===l+d/2},Yi=function(e,t){var n;return bt.some(function(r){var o=r[M].options.emptyInsertThreshold;if(!(!o||tn(r))){var a=x(r),l=e>=a.left-o&&e<=a.right+o,s=t>=a.top-o&&t<=a.bottom+o;if(l&&s)return n=r}}),n},jn=function(e){function t(o,a){return function(l,s,d,p){var u=l.options.group.name&&s.options.group.name&&l.options.group.name===s.options.group.name;if(o==null&&(a||u))return!0;if(o==null||o===!1)return!1;if(a&&o==="clone")return o;if(typeof o=="function")return t(o(l,s,d,p),a)(l,s,d,p);var f=(a?l:s).options.group.name;return o===!0||typeof o=="string"&&o===f||o.join&&o.indexOf(f)>-1}}var n={},r=e.group;(!r||ut(r)!="object")&&(r={name:r}),n.name=r.name,n.checkPull=t(r.pull,!0),n.checkPut=t(r.put),n.revertClone=r.revertClone,e.group=n},Yn=function(){!zn&&y&&m(y,"display","none")},Xn=function(){!zn&&y&&m(y,"display","")};Et&&!In&&document.addEventListener("click",function(i){if(yt)return i.preventDefault(),i.stopPropagation&&i.stopPropagation(),i.stopImmediatePropagation&&i.stopImmediatePropagation(),yt=!1,!1},!0);var ye=function(e){if(c){e=e.touches?e.touches[0]:e;var t=Yi(e.clientX,e.clientY);if(t){var n={};for(var r in e)e.hasOwnProperty(r)&&(n[r]=e[r]);n.target=n.rootEl=t,n.preventDefault=void 0,n.stopPropagation=void 0,t[M]._onDragOver(n)}}},Xi=function(e){c&&c.parentNode[M]._isOutsideThisEl(e.target)};function g(i,e){if(!(i&&i.nodeType&&i.nodeType===1))throw"Sortable: `el` must be an HTMLElement, not ".concat({}.toString.call(i));this.el=i,this.options=e=J({},e),i[M]=this;var t={group:null,sort:!0,disabled:!1,store:null,handle:null,draggable:/^[uo]l$/i.test(i.nodeName)?">li":">*",swapThreshold:1,invertSwap:!1,invertedSwapThreshold:null,removeCloneOnHide:!0,direction:function(){return Bn(i,this.options)},ghostClass:"sortable-ghost",chosenClass:"sortable-chosen",dragClass:"sortable-drag",ignore:"a, img",filter:null,preventOnFilter:!0,animation:0,easing:null,setData:function(a,l){a.setData("Text",l.textContent)},dropBubble:!1,dragoverBubble:!1,dataIdAttr:"data-id",delay:0,delayOnTouchOnly:!1,touchStartThreshold:(Number.parseInt?Number:window).parseInt(window.devicePixelRatio,10)||1,forceFallback:!1,fallbackClass:"sortable-fallback",fallbackOnBody:!1,fallbackTolerance:0,fallbackOffset:{x:0,y:0},supportPointer:g.supportPointer!==!1&&"PointerEvent"in window&&(!je||en),emptyInsertThreshold:5};Je.initializePlugins(this,i,t);for(var n in t)!(n in e)&&(e[n]=t[n]);jn(e);for(var r in this)r.charAt(0)==="_"&&typeof this[r]=="function"&&(this[r]=this[r].bind(this));this.nativeDraggable=e.forceFallback?!1:Bi,this.nativeDraggable&&(this.options.touchStartThreshold=1),e.supportPointer?_(i,"pointerdown",this._onTapStart):(_(i,"mousedown",this._onTapStart),_(i,"touchstart",this._onTapStart)),this.nativeDraggable&&(_(i,"dragover",this),_(i,"dragenter",this)),bt.push(this.el),e.store&&e.store.get&&this.sort(e.store.get(this)||[]),J(this,Wi())}g.prototype={constructor:g,_isOutsideThisEl:function(e){!this.el.contains(e)&&e!==this.el&&(Te=null)},_getDirection:function(e,t){return typeof this.options.direction=="function"?this.options.direction.call(this,e,t,c):this.options.direction},_onTapStart:function(e){if(e.cancelable){var t=this,n=this.el,r=this.options,o=r.preventOnFilter,a=e.type,l=e.touches&&e.touches[0]||e.pointerType&&e.pointerType==="touch"&&e,s=(l||e).target,d=e.target.shadowRoot&&(e.path&&e.path[0]||e.composedPath&&e.composedPath()[0])||s,p=r.filter;if(nr(n),!c&&!(/mousedown|pointerdown/.test(a)&&e.button!==0||r.disabled)&&!d.isContentEditable&&!(!this.nativeDraggable&&je&&s&&s.tagName.toUpperCase()==="SELECT")&&(s=z(s,r.draggable,n,!1),!(s&&s.animated)&&pt!==s)){if(Pe=U(s),Xe=U(s,r.draggable),typeof p=="function"){if(p.call(this,e,s,this)){N({sortable:t,rootEl:d,name:"filter",targetEl:s,toEl:n,fromEl:n}),k("filter",t,{evt:e}),o&&e.preventDefault();return}}else if(p&&(p=p.split(",").some(function(u){if(u=z(d,u.trim(),n,!1),u)return N({sortable:t,rootEl:u,name:"filter",targetEl:s,fromEl:n,toEl:n}),k("filter",t,{evt:e}),!0}),p)){o&&e.preventDefault();return}r.handle&&!z(d,r.handle,n,!1)||this._prepareDragStart(e,l,s)}}},_prepareDragStart:function(e,t,n){var r=this,o=r.el,a=r.options,l=o.ownerDocument,s;if(n&&!c&&n.parentNode===o){var d=x(n);if(S=o,c=n,C=c.parentNode,be=c.nextSibling,pt=n,st=a.group,g.dragged=c,ge={target:c,clientX:(t||e).clientX,clientY:(t||e).clientY},Nn=ge.clientX-d.left,kn=ge.clientY-d.top,this._lastX=(t||e).clientX,this._lastY=(t||e).clientY,c.style["will-change"]="all",s=function(){if(k("delayEnded",r,{evt:e}),g.eventCanceled){r._onDrop();return}r._disableDelayedDragEvents(),!xn&&r.nativeDraggable&&(c.draggable=!0),r._triggerDragStart(e,t),N({sortable:r,name:"choose",originalEvent:e}),L(c,a.chosenClass,!0)},a.ignore.split(",").forEach(function(p){Un(c,p.trim(),jt)}),_(l,"dragover",ye),_(l,"mousemove",ye),_(l,"touchmove",ye),a.supportPointer?(_(l,"pointerup",r._onDrop),!this.nativeDraggable&&_(l,"pointercancel",r._onDrop)):(_(l,"mouseup",r._onDrop),_(l,"touchend",r._onDrop),_(l,"touchcancel",r._onDrop)),xn&&this.nativeDraggable&&(this.options.touchStartThreshold=4,c.draggable=!0),k("delayStart",this,{evt:e}),a.delay&&(!a.delayOnTouchOnly||t)&&(!this.nativeDraggable||!(Qe||Z))){if(g.eventCanceled){this._onDrop();return}a.supportPointer?(_(l,"pointerup",r._disableDelayedDrag),_(l,"pointercancel",r._disableDelayedDrag)):(_(l,"mouseup",r._disableDelayedDrag),_(l,"touchend",r._disableDelayedDrag),_(l,"touchcancel",r._disableDelayedDrag)),_(l,"mousemove",r._delayedDragTouchMoveHandler),_(l,"touchmove",r._delayedDragTouchMoveHandler),a.supportPointer&&_(l,"pointermove",r._delayedDragTouchMoveHandler),r._dragStartTimer=setTimeout(s,a.delay)}else s()}},_delayedDragTouchMoveHandler:function(e){var t=e.touches?e.touches[0]:e;Math.max(Math.abs(t.clientX-this._lastX),Math.abs(t.clientY-this._lastY))>=Math.floor(this.options.touchStartThreshold/(this.nativeDraggable&&window.devicePixelRatio||1))&&this._disableDelayedDrag()},_disableDelayedDrag:function(){c&&jt(c),clearTimeout(this._dragStartTimer),this._disableDelayedDragEvents()},_disableDelayedDragEvents:function(){var e=this.el.ownerDocument;v(e,"mouseup",this._disableDelayedDrag),v(e,"touchend",this._disableDelayedDrag),v(e,"touchcancel",this._disableDelayedDrag),v(e,"pointerup",this._disableDelayedDrag),v(e,"pointercancel",this._disableDelayedDrag),v(e,"mousemove",this._delayedDragTouchMoveHandler),v(e,"touchmove",this._delayedDragTouchMoveHandler),v(e,"pointermove",this._delayedDragTouchMoveHandler)},_triggerDragStart:function(e,t){t=t||e.pointerType=="touch"&&e,!this.nativeDraggable||t?this.options.supportPointer?_(document,"pointermove",this._onTouchMove):t?_(document,"touchmove",this._onTouchMove):_(document,"mousemove",this._onTouchMove):(_(c,"dragend",this),_(S,"dragstart",this._onDragStart));try{document.selection?ht(function(){document.selection.empty()}):window.getSelection().removeAllRanges()}catch{}},_dragStarted:function(e,t){if(Ce=!1,S&&c){k("dragStarted",this,{evt:t}),this.nativeDraggable&&_(document,"dragover",Xi);var n=this.options;!e&&L(c,n.dragClass,!1),L(c,n.ghostClass,!0),g.active=this,e&&this._appendGhost(),N({sortable:this,name:"start",originalEvent:t})}else this._nulling()},_emulateDragOver:function(){if(F){this._lastX=F.clientX,this._lastY=F.clientY,Yn();for(var e=document.elementFromPoint(F.clientX,F.clientY),t=e;e&&e.shadowRoot&&(e=e.shadowRoot.elementFromPoint(F.clientX,F.clientY),e!==t);)t=e;if(c.parentNode[M]._isOutsideThisEl(e),t)do{if(t[M]){var n=void 0;if(n=t[M]._onDragOver({clientX:F.clientX,clientY:F.clientY,target:e,rootEl:t}),n&&!this.options.dragoverBubble)break}e=t}while(t=Vn(t));Xn()}},_onTouchMove:function(e){if(ge){var t=this.options,n=t.fallbackTolerance,r=t.fallbackOffset,o=e.touches?e.touches[0]:e,a=y&&De(y,!0),l=y&&a&&a.a,s=y&&a&&a.d,d=dt&&A&&On(A),p=(o.clientX-ge.clientX+r.x)/(l||1)+(d?d[0]-Bt[0]:0)/(l||1),u=(o.clientY-ge.clientY+r.y)/(s||1)+(d?d[1]-Bt[1]:0)/(s||1);if(!g.active&&!Ce){if(n&&Math.max(Math.abs(o.clientX-this._lastX),Math.abs(o.clientY-this._lastY))<n)return;this._onDragStart(e,!0)}if(y){a?(a.e+=p-(Ft||0),a.f+=u-(zt||0)):a={a:1,b:0,c:0,d:1,e:p,f:u};var f="matrix(".concat(a.a,",").concat(a.b,",").concat(a.c,",").concat(a.d,",").concat(a.e,",").concat(a.f,")");m(y,"webkitTransform",f),m(y,"mozTransform",f),m(y,"msTransform",f),m(y,"transform",f),Ft=p,zt=u,F=o}e.cancelable&&e.preventDefault()}},_appendGhost:function(){if(!y){var e=this.options.fallbackOnBody?document.body:S,t=x(c,!0,dt,!0,e),n=this.options;if(dt){for(A=e;m(A,"position")==="static"&&m(A,"transform")==="none"&&A!==document;)A=A.parentNode;A!==document.body&&A!==document.documentElement?(A===document&&(A=j()),t.top+=A.scrollTop,t.left+=A.scrollLeft):A=j(),Bt=On(A)}y=c.cloneNode(!0),L(y,n.ghostClass,!1),L(y,n.fallbackClass,!0),L(y,n.dragClass,!0),m(y,"transition",""),m(y,"transform",""),m(y,"box-sizing","border-box"),m(y,"margin",0),m(y,"top",t.top),m(y,"left",t.left),m(y,"width",t.width),m(y,"height",t.height),m(y,"opacity","0.8"),m(y,"position",dt?"absolute":"fixed"),m(y,"zIndex","100000"),m(y,"pointerEvents","none"),g.ghost=y,e.appendChild(y),m(y,"transform-origin",Nn/parseInt(y.style.width)*100+"% "+kn/parseInt(y.style.height)*100+"%")}},_onDragStart:function(e,t){var n=this,r=e.dataTransfer,o=n.options;if(k("dragStart",this,{evt:e}),g.eventCanceled){this._onDrop();return}k("setupClone",this),g.eventCanceled||(T=Kn(c),T.removeAttribute("id"),T.draggable=!1,T.style["will-change"]="",this._hideClone(),L(T,this.options.chosenClass,!1),g.clone=T),n.cloneId=ht(function(){k("clone",n),!g.eventCanceled&&(n.options.removeCloneOnHide||S.insertBefore(T,c),n._hideClone(),N({sortable:n,name:"clone"}))}),!t&&L(c,o.dragClass,!0),t?(yt=!0,n._loopId=setInterval(n._emulateDragOver,50)):(v(document,"mouseup",n._onDrop),v(document,"touchend",n._onDrop),v(document,"touchcancel",n._onDrop),r&&(r.effectAllowed="move",o.setData&&o.setData.call(n,r,c)),_(document,"drop",n),m(c,"transform","translateZ(0)")),Ce=!0,n._dragStartId=ht(n._dragStarted.bind(n,t,e)),_(document,"selectstart",n),Fe=!0,window.getSelection().removeAllRanges(),je&&m(document.body,"user-select","none")},_onDragOver:function(e){var t=this.el,n=e.target,r,o,a,l=this.options,s=l.group,d=g.active,p=st===s,u=l.sort,f=R||d,h,b=this,E=!1;if(qt)return;function W(ke,qn){k(ke,b,Y({evt:e,isOwner:p,axis:h?"vertical":"horizontal",revert:a,dragRect:r,targetRect:o,canSort:u,fromSortable:f,target:n,completed:O,onMove:function(an,Qn){return ct(S,t,c,r,an,x(an),e,Qn)},changed:H},qn))}function X(){W("dragOverAnimationCapture"),b.captureAnimationState(),b!==f&&f.captureAnimationState()}function O(ke){return W("dragOverCompleted",{insertion:ke}),ke&&(p?d._hideClone():d._showClone(b),b!==f&&(L(c,R?R.options.ghostClass:d.options.ghostClass,!1),L(c,l.ghostClass,!0)),R!==b&&b!==g.active?R=b:b===g.active&&R&&(R=null),f===b&&(b._ignoreWhileAnimating=n),b.animateAll(function(){W("dragOverAnimationComplete"),b._ignoreWhileAnimating=null}),b!==f&&(f.animateAll(),f._ignoreWhileAnimating=null)),(n===c&&!c.animated||n===t&&!n.animated)&&(Te=null),!l.dragoverBubble&&!e.rootEl&&n!==document&&(c.parentNode[M]._isOutsideThisEl(e.target),!ke&&ye(e)),!l.dragoverBubble&&e.stopPropagation&&e.stopPropagation(),E=!0}function H(){V=U(c),oe=U(c,l.draggable),N({sortable:b,name:"change",toEl:t,newIndex:V,newDraggableIndex:oe,originalEvent:e})}if(e.preventDefault!==void 0&&e.cancelable&&e.preventDefault(),n=z(n,l.draggable,t,!0),W("dragOver"),g.eventCanceled)return E;if(c.contains(e.target)||n.animated&&n.animatingX&&n.animatingY||b._ignoreWhileAnimating===n)return O(!1);if(yt=!1,d&&!l.disabled&&(p?u||(a=C!==S):R===this||(this.lastPutMode=st.checkPull(this,d,c,e))&&s.checkPut(this,d,c,e))){if(h=this._getDirection(e,n)==="vertical",r=x(c),W("dragOverValid"),g.eventCanceled)return E;if(a)return C=S,X(),this._hideClone(),W("revert"),g.eventCanceled||(be?S.insertBefore(c,be):S.appendChild(c)),O(!0);var $=tn(t,l.draggable);if(!$||Ji(e,h,this)&&!$.animated){if($===c)return O(!1);if($&&t===e.target&&(n=$),n&&(o=x(n)),ct(S,t,c,r,n,o,e,!!n)!==!1)return X(),$&&$.nextSibling?t.insertBefore(c,$.nextSibling):t.appendChild(c),C=t,H(),O(!0)}else if($&&Qi(e,h,this)){var le=xe(t,0,l,!0);if(le===c)return O(!1);if(n=le,o=x(n),ct(S,t,c,r,n,o,e,!1)!==!1)return X(),t.insertBefore(c,le),C=t,H(),O(!0)}else if(n.parentNode===t){o=x(n);var B=0,de,Re=c.parentNode!==t,I=!ji(c.animated&&c.toRect||r,n.animated&&n.toRect||o,h),Ae=h?"top":"left",te=An(n,"top","top")||An(c,"top","top"),Oe=te?te.scrollTop:void 0;Te!==n&&(de=o[Ae],qe=!1,lt=!I&&l.invertSwap||Re),B=Zi(e,n,o,h,I?1:l.swapThreshold,l.invertedSwapThreshold==null?l.swapThreshold:l.invertedSwapThreshold,lt,Te===n);var G;if(B!==0){var ce=U(c);do ce-=B,G=C.children[ce];while(G&&(m(G,"display")==="none"||G===y))}if(B===0||G===n)return O(!1);Te=n,Ge=B;var Ne=n.nextElementSibling,ne=!1;ne=B===1;var Ze=ct(S,t,c,r,n,o,e,ne);if(Ze!==!1)return(Ze===1||Ze===-1)&&(ne=Ze===1),qt=!0,setTimeout(qi,30),X(),ne&&!Ne?t.appendChild(c):n.parentNode.insertBefore(c,ne?Ne:n),te&&Hn(te,0,Oe-te.scrollTop),C=c.parentNode,de!==void 0&&!lt&&(ft=Math.abs(de-x(n)[Ae])),H(),O(!0)}if(t.contains(c))return O(!1)}return!1},_ignoreWhileAnimating:null,_offMoveEvents:function(){v(document,"mousemove",this._onTouchMove),v(document,"touchmove",this._onTouchMove),v(document,"pointermove",this._onTouchMove),v(document,"dragover",ye),v(document,"mousemove",ye),v(document,"touchmove",ye)},_offUpEvents:function(){var e=this.el.ownerDocument;v(e,"mouseup",this._onDrop),v(e,"touchend",this._onDrop),v(e,"pointerup",this._onDrop),v(e,"pointercancel",this._onDrop),v(e,"touchcancel",this._onDrop),v(document,"selectstart",this)},_onDrop:function(e){var t=this.el,n=this.options;if(V=U(c),oe=U(c,n.draggable),k("drop",this,{evt:e}),C=c&&c.parentNode,V=U(c),oe=U(c,n.draggable),g.eventCanceled){this._nulling();return}Ce=!1,lt=!1,qe=!1,clearInterval(this._loopId),clearTimeout(this._dragStartTimer),Qt(this.cloneId),Qt(this._dragStartId),this.nativeDraggable&&(v(document,"drop",this),v(t,"dragstart",this._onDragStart)),this._offMoveEvents(),this._offUpEvents(),je&&m(document.body,"user-select",""),m(c,"transform",""),e&&(Fe&&(e.cancelable&&e.preventDefault(),!n.dropBubble&&e.stopPropagation()),y&&y.parentNode&&y.parentNode.removeChild(y),(S===C||R&&R.lastPutMode!=="clone")&&T&&T.parentNode&&T.parentNode.removeChild(T),c&&(this.nativeDraggable&&v(c,"dragend",this),jt(c),c.style["will-change"]="",Fe&&!Ce&&L(c,R?R.options.ghostClass:this.options.ghostClass,!1),L(c,this.options.chosenClass,!1),N({sortable:this,name:"unchoose",toEl:C,newIndex:null,newDraggableIndex:null,originalEvent:e}),S!==C?(V>=0&&(N({rootEl:C,name:"add",toEl:C,fromEl:S,originalEvent:e}),N({sortable:this,name:"remove",toEl:C,originalEvent:e}),N({rootEl:C,name:"sort",toEl:C,fromEl:S,originalEvent:e}),N({sortable:this,name:"sort",toEl:C,originalEvent:e})),R&&R.save()):V!==Pe&&V>=0&&(N({sortable:this,name:"update",toEl:C,originalEvent:e}),N({sortable:this,name:"sort",toEl:C,originalEvent:e})),g.active&&((V==null||V===-1)&&(V=Pe,oe=Xe),N({sortable:this,name:"end",toEl:C,originalEvent:e}),this.save()))),this._nulling()},_nulling:function(){k("nulling",this),S=c=C=y=be=T=pt=ae=ge=F=Fe=V=oe=Pe=Xe=Te=Ge=R=st=g.dragged=g.ghost=g.clone=g.active=null,vt.forEach(function(e){e.checked=!0}),vt.length=Ft=zt=0},handleEvent:function(e){switch(e.type){case"drop":case"dragend":this._onDrop(e);break;case"dragenter":case"dragover":c&&(this._onDragOver(e),Gi(e));break;case"selectstart":e.preventDefault();break}},toArray:function(){for(var e=[],t,n=this.el.children,r=0,o=n.length,a=this.options;r<o;r++)t=n[r],z(t,a.draggable,this.el,!1)&&e.push(t.getAttribute(a.dataIdAttr)||tr(t));return e},sort:function(e,t){var n={},r=this.el;this.toArray().forEach(function(o,a){var l=r.children[a];z(l,this.options.draggable,r,!1)&&(n[o]=l)},this),t&&this.captureAnimationState(),e.forEach(function(o){n[o]&&(r.removeChild(n[o]),r.appendChild(n[o]))}),t&&this.animateAll()},save:function(){var e=this.options.store;e&&e.set&&e.set(this)},closest:function(e,t){return z(e,t||this.options.draggable,this.el,!1)},option:function(e,t){var n=this.options;if(t===void 0)return n[e];var r=Je.modifyOption(this,e,t);typeof r<"u"?n[e]=r:n[e]=t,e==="group"&&jn(n)},destroy:function(){k("destroy",this);var e=this.el;e[M]=null,v(e,"mousedown",this._onTapStart),v(e,"touchstart",this._onTapStart),v(e,"pointerdown",this._onTapStart),this.nativeDraggable&&(v(e,"dragover",this),v(e,"dragenter",this)),Array.prototype.forEach.call(e.querySelectorAll("[draggable]"),function(t){t.removeAttribute("draggable")}),this._onDrop(),this._disableDelayedDragEvents(),bt.splice(bt.indexOf(this.el),1),this.el=e=null},_hideClone:function(){if(!ae){if(k("hideClone",this),g.eventCanceled)return;m(T,"display","none"),this.options.removeCloneOnHide&&T.parentNode&&T.parentNode.removeChild(T),ae=!0}},_showClone:function(e){if(e.lastPutMode!=="clone"){this._hideClone();return}if(ae){if(k("showClone",this),g.eventCanceled)return;c.parentNode==S&&!this.options.group.revertClone?S.insertBefore(T,c):be?S.insertBefore(T,be):S.appendChild(T),this.options.group.revertClone&&this.animate(c,T),m(T,"display",""),ae=!1}}};function Gi(i){i.dataTransfer&&(i.dataTransfer.dropEffect="move"),i.cancelable&&i.preventDefault()}function ct(i,e,t,n,r,o,a,l){var s,d=i[M],p=d.options.onMove,u;return window.CustomEvent&&!Z&&!Qe?s=new CustomEvent("move",{bubbles:!0,cancelable:!0}):(s=document.createEvent("Event"),s.initEvent("move",!0,!0)),s.to=e,s.from=i,s.dragged=t,s.draggedRect=n,s.related=r||e,s.relatedRect=o||x(e),s.willInsertAfter=l,s.originalEvent=a,i.dispatchEvent(s),p&&(u=p.call(d,s,a)),u}function jt(i){i.draggable=!1}function qi(){qt=!1}function Qi(i,e,t){var n=x(xe(t.el,0,t.options,!0)),r=Fn(t.el,t.options,y),o=10;return e?i.clientX<r.left-o||i.clientY<n.top&&i.clientX<n.right:i.clientY<r.top-o||i.clientY<n.bottom&&i.clientX<n.left}function Ji(i,e,t){var n=x(tn(t.el,t.options.draggable)),r=Fn(t.el,t.options,y),o=10;return e?i.clientX>r.right+o||i.clientY>n.bottom&&i.clientX>n.left:i.clientY>r.bottom+o||i.clientX>n.right&&i.clientY>n.top}function Zi(i,e,t,n,r,o,a,l){var s=n?i.clientY:i.clientX,d=n?t.height:t.width,p=n?t.top:t.left,u=n?t.bottom:t.right,f=!1;if(!a){if(l&&ft<d*r){if(!qe&&(Ge===1?s>p+d*o/2:s<u-d*o/2)&&(qe=!0),qe)f=!0;else if(Ge===1?s<p+ft:s>u-ft)return-Ge}else if(s>p+d*(1-r)/2&&s<u-d*(1-r)/2)return er(e)}return f=f||a,f&&(s<p+d*o/2||s>u-d*o/2)?s>p+d/2?1:-1:0}function er(i){return U(c)<U(i)?1:-1}function tr(i){for(var e=i.tagName+i.className+i.src+i.href+i.textContent,t=e.length,n=0;t--;)n+=e.charCodeAt(t);return n.toString(36)}function nr(i){vt.length=0;for(var e=i.getElementsByTagName("input"),t=e.length;t--;){var n=e[t];n.checked&&vt.push(n)}}function ht(i){return setTimeout(i,0)}function Qt(i){return clearTimeout(i)}Et&&_(document,"touchmove",function(i){(g.active||Ce)&&i.cancelable&&i.preventDefault()});g.utils={on:_,off:v,css:m,find:Un,is:function(e,t){return!!z(e,t,e,!1)},extend:Vi,throttle:Wn,closest:z,toggleClass:L,clone:Kn,index:U,nextTick:ht,cancelNextTick:Qt,detectDirection:Bn,getChild:xe,expando:M};g.get=function(i){return i[M]};g.mount=function(){for(var i=arguments.length,e=new Array(i),t=0;t<i;t++)e[t]=arguments[t];e[0].constructor===Array&&(e=e[0]),e.forEach(function(n){if(!n.prototype||!n.prototype.constructor)throw"Sortable: Mounted plugin must be a constructor function, not ".concat({}.toString.call(n));n.utils&&(g.utils=Y(Y({},g.utils),n.utils)),Je.mount(n)})};g.create=function(i,e){return new g(i,e)};g.version=Ii;var D=[],ze,Jt,Zt=!1,Yt,Xt,_t,Be;function ir(){function i(){this.defaults={scroll:!0,forceAutoScrollFallback:!1,scrollSensitivity:30,scrollSpeed:10,bubbleScroll:!0};for(var e in this)e.charAt(0)==="_"&&typeof this[e]=="function"&&(this[e]=this[e].bind(this))}return i.prototype={dragStarted:function(t){var n=t.originalEvent;this.sortable.nativeDraggable?_(document,"dragover",this._handleAutoScroll):this.options.supportPointer?_(document,"pointermove",this._handleFallbackAutoScroll):n.touches?_(document,"touchmove",this._handleFallbackAutoScroll):_(document,"mousemove",this._handleFallbackAutoScroll)},dragOverCompleted:function(t){var n=t.originalEvent;!this.options.dragOverBubble&&!n.rootEl&&this._handleAutoScroll(n)},drop:function(){this.sortable.nativeDraggable?v(document,"dragover",this._handleAutoScroll):(v(document,"pointermove",this._handleFallbackAutoScroll),v(document,"touchmove",this._handleFallbackAutoScroll),v(document,"mousemove",this._handleFallbackAutoScroll)),$n(),mt(),Ui()},nulling:function(){_t=Jt=ze=Zt=Be=Yt=Xt=null,D.length=0},_handleFallbackAutoScroll:function(t){this._handleAutoScroll(t,!0)},_handleAutoScroll:function(t,n){var r=this,o=(t.touches?t.touches[0]:t).clientX,a=(t.touches?t.touches[0]:t).clientY,l=document.elementFromPoint(o,a);if(_t=t,n||this.options.forceAutoScrollFallback||Qe||Z||je){Gt(t,this.options,l,n);var s=se(l,!0);Zt&&(!Be||o!==Yt||a!==Xt)&&(Be&&$n(),Be=setInterval(function(){var d=se(document.elementFromPoint(o,a),!0);d!==s&&(s=d,mt()),Gt(t,r.options,d,n)},10),Yt=o,Xt=a)}else{if(!this.options.bubbleScroll||se(l,!0)===j()){mt();return}Gt(t,this.options,se(l,!1),!1)}}},J(i,{pluginName:"scroll",initializeByDefault:!0})}function mt(){D.forEach(function(i){clearInterval(i.pid)}),D=[]}function $n(){clearInterval(Be)}var Gt=Wn(function(i,e,t,n){if(e.scroll){var r=(i.touches?i.touches[0]:i).clientX,o=(i.touches?i.touches[0]:i).clientY,a=e.scrollSensitivity,l=e.scrollSpeed,s=j(),d=!1,p;Jt!==t&&(Jt=t,mt(),ze=e.scroll,p=e.scrollFn,ze===!0&&(ze=se(t,!0)));var u=0,f=ze;do{var h=f,b=x(h),E=b.top,W=b.bottom,X=b.left,O=b.right,H=b.width,$=b.height,le=void 0,B=void 0,de=h.scrollWidth,Re=h.scrollHeight,I=m(h),Ae=h.scrollLeft,te=h.scrollTop;h===s?(le=H<de&&(I.overflowX==="auto"||I.overflowX==="scroll"||I.overflowX==="visible"),B=$<Re&&(I.overflowY==="auto"||I.overflowY==="scroll"||I.overflowY==="visible")):(le=H<de&&(I.overflowX==="auto"||I.overflowX==="scroll"),B=$<Re&&(I.overflowY==="auto"||I.overflowY==="scroll"));var Oe=le&&(Math.abs(O-r)<=a&&Ae+H<de)-(Math.abs(X-r)<=a&&!!Ae),G=B&&(Math.abs(W-o)<=a&&te+$<Re)-(Math.abs(E-o)<=a&&!!te);if(!D[u])for(var ce=0;ce<=u;ce++)D[ce]||(D[ce]={});(D[u].vx!=Oe||D[u].vy!=G||D[u].el!==h)&&(D[u].el=h,D[u].vx=Oe,D[u].vy=G,clearInterval(D[u].pid),(Oe!=0||G!=0)&&(d=!0,D[u].pid=setInterval(function(){n&&this.layer===0&&g.active._onTouchMove(_t);var Ne=D[this.layer].vy?D[this.layer].vy*l:0,ne=D[this.layer].vx?D[this.layer].vx*l:0;typeof p=="function"&&p.call(g.dragged.parentNode[M],ne,Ne,i,_t,D[this.layer].el)!=="continue"||Hn(D[this.layer].el,ne,Ne)}.bind({layer:u}),24))),u++}while(e.bubbleScroll&&f!==s&&(f=se(f,!1)));Zt=d}},30),Gn=function(e){var t=e.originalEvent,n=e.putSortable,r=e.dragEl,o=e.activeSortable,a=e.dispatchSortableEvent,l=e.hideGhostForTarget,s=e.unhideGhostForTarget;if(t){var d=n||o;l();var p=t.changedTouches&&t.changedTouches.length?t.changedTouches[0]:t,u=document.elementFromPoint(p.clientX,p.clientY);s(),d&&!d.el.contains(u)&&(a("spill"),this.onSpill({dragEl:r,putSortable:n}))}};function nn(){}nn.prototype={startIndex:null,dragStart:function(e){var t=e.oldDraggableIndex;this.startIndex=t},onSpill:function(e){var t=e.dragEl,n=e.putSortable;this.sortable.captureAnimationState(),n&&n.captureAnimationState();var r=xe(this.sortable.el,this.startIndex,this.options);r?this.sortable.el.insertBefore(t,r):this.sortable.el.appendChild(t),this.sortable.animateAll(),n&&n.animateAll()},drop:Gn};J(nn,{pluginName:"revertOnSpill"});function rn(){}rn.prototype={onSpill:function(e){var t=e.dragEl,n=e.putSortable,r=n||this.sortable;r.captureAnimationState(),t.parentNode&&t.parentNode.removeChild(t),r.animateAll()},drop:Gn};J(rn,{pluginName:"removeOnSpill"});g.mount(new ir);g.mount(rn,nn);var on=g;var ee=class extends re{constructor(){super(...arguments);this.title="";this.value="";this.items=[];this.sortItems=""}connectedCallback(){super.connectedCallback(),this.hasUpdated&&this.setupSortable()}disconnectedCallback(){super.disconnectedCallback(),this.sortable?.destroy(),this.sortable=void 0}firstUpdated(){this.setupSortable()}setupSortable(){if(this.sortItems){this.sortWrappedList();return}this.sortable=new on(this.sortContainer,{animation:150,ghostClass:"opacity-25",onEnd:t=>{this.value=`Moved from ${t.oldIndex} to ${t.newIndex}`,this.dispatchEvent(new CustomEvent("change",{detail:`Moved from ${t.oldIndex} to ${t.newIndex}`}))}})}sortWrappedList(){let t=this.firstElementChild;if(!t)return;let n=this.sortItems;this.sortable=new on(t,{animation:150,ghostClass:"opacity-25",draggable:n,onEnd:r=>{if(r.oldIndex===r.newIndex)return;let o=r.item.nextElementSibling;for(;o&&!(o.matches(n)&&o.dataset.id);)o=o.nextElementSibling;this.dispatchEvent(new CustomEvent("change",{detail:{id:r.item.dataset.id,before:o?.dataset.id??""}}))}})}createRenderRoot(){return this}render(){return this.sortItems?P:(console.log(this),Vt`
       <div class="sortable-wrapper">
         <div class="sortable-title">${this.title}: <strong>${this.value}</strong></div>
         <div>Open your console to see event results</div>
         <div id="sortable-container" class="sortable-container">
-          ${this.items?.length>0&&this.items.map(e=>$e` <div class="sortable-item">${e.name}</div> `)}
+          ${this.items?.length>0&&this.items.map(t=>Vt` <div class="sortable-item">${t.name}</div> `)}
         </div>
       </div>
-    `}};vt([dn("#sortable-container")],rt.prototype,"sortContainer",2),vt([kt({type:String})],rt.prototype,"title",2),vt([kt({type:String})],rt.prototype,"value",2),vt([kt({type:Array})],rt.prototype,"items",2),rt=vt([hn("sortable-example")],rt);export{rt as SortableExample};
+    `)}};ue([Pn("#sortable-container")],ee.prototype,"sortContainer",2),ue([Ke({type:String})],ee.prototype,"title",2),ue([Ke({type:String})],ee.prototype,"value",2),ue([Ke({type:Array})],ee.prototype,"items",2),ue([Ke({type:String,attribute:"sort-items"})],ee.prototype,"sortItems",2),ee=ue([Cn("sortable-example")],ee);export{ee as SortableExample};
 /*! Bundled license information:
 
-@lit/reactive-element/css-tag.js:
+@lit/reactive-element/src/css-tag.ts:
   (**
    * @license
    * Copyright 2019 Google LLC
    * SPDX-License-Identifier: BSD-3-Clause
    *)
 
-@lit/reactive-element/reactive-element.js:
-lit-html/lit-html.js:
-lit-element/lit-element.js:
-@lit/reactive-element/decorators/custom-element.js:
-@lit/reactive-element/decorators/property.js:
-@lit/reactive-element/decorators/state.js:
-@lit/reactive-element/decorators/event-options.js:
-@lit/reactive-element/decorators/base.js:
-@lit/reactive-element/decorators/query.js:
-@lit/reactive-element/decorators/query-all.js:
-@lit/reactive-element/decorators/query-async.js:
-@lit/reactive-element/decorators/query-assigned-nodes.js:
+@lit/reactive-element/src/reactive-element.ts:
+lit-html/src/lit-html.ts:
+lit-element/src/lit-element.ts:
+@lit/reactive-element/src/decorators/custom-element.ts:
+@lit/reactive-element/src/decorators/property.ts:
+@lit/reactive-element/src/decorators/base.ts:
+@lit/reactive-element/src/decorators/query.ts:
   (**
    * @license
    * Copyright 2017 Google LLC
    * SPDX-License-Identifier: BSD-3-Clause
    *)
 
-lit-html/is-server.js:
-  (**
-   * @license
-   * Copyright 2022 Google LLC
-   * SPDX-License-Identifier: BSD-3-Clause
-   *)
-
-@lit/reactive-element/decorators/query-assigned-elements.js:
-  (**
-   * @license
-   * Copyright 2021 Google LLC
-   * SPDX-License-Identifier: BSD-3-Clause
-   *)
-
 sortablejs/modular/sortable.esm.js:
   (**!
    * Sortable 1.15.6
@@ -4,8 +4,11 @@ import (
 	"context"
 	"database/sql"
 	"net/http"
+	"slices"
+	"strings"
 
 	"northstar/app/features/auth/gen/authdb"
+	"northstar/config"
 
 	"github.com/gorilla/sessions"
 )
@@ -14,25 +17,78 @@ type contextKey string
 
 const UserContextKey = contextKey("user")
 
+// authCheckedContextKey marks requests WithAuth has looked at.
+const authCheckedContextKey = contextKey("auth-checked")
+
+// SessionVersionKey holds the users.session_version a session was created
+// at. Bumping the column logs the user out everywhere.
+const SessionVersionKey = "session_version"
+
+// Roles a user can have. New users get RoleUser.
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+)
+
+// RequireAuth sends visitors who are not logged in to the login page. When
+// config.Global.RequireVerifiedEmail is set, users who have not verified
+// their email address yet are sent to verify it instead.
 func RequireAuth(store sessions.Store, db *sql.DB) func(http.Handler) http.Handler {
+	return requireAuth(config.Global.RequireVerifiedEmail)
+}
+
+// RequireAuthAllowUnverified is RequireAuth for the pages users need before
+// verifying their email address, such as the verification page itself.
+func RequireAuthAllowUnverified(store sessions.Store, db *sql.DB) func(http.Handler) http.Handler {
+	return requireAuth(false)
+}
+
+func requireAuth(verified bool) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			user, ok := GetUserFromContext(r.Context())
+			if !ok {
+				http.Redirect(w, r, "/login", http.StatusSeeOther)
+				return
+			}
+			if verified && !user.EmailVerified {
+				http.Redirect(w, r, "/verify-email", http.StatusSeeOther)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RequireRole lets through users who have one of roles. Visitors who are not
+// logged in are sent to the login page, and other users are forbidden.
+func RequireRole(roles ...string) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			if GetUserIDFromContext(r.Context()) == "" {
 				http.Redirect(w, r, "/login", http.StatusSeeOther)
 				return
 			}
+			if !HasRole(r.Context(), roles...) {
+				http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+				return
+			}
 			next.ServeHTTP(w, r)
 		})
 	}
 }
 
+// HasRole reports whether the logged in user has one of roles.
+func HasRole(ctx context.Context, roles ...string) bool {
+	user, ok := GetUserFromContext(ctx)
+	return ok && slices.Contains(roles, user.Role)
+}
+
 func RedirectIfAuthenticated(store sessions.Store) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			session, _ := store.Get(r, "auth-session")
-
-			userID, ok := session.Values["user_id"]
-			if ok && userID != nil {
+			// WithAuth has already checked the session is still valid
+			if GetUserIDFromContext(r.Context()) != "" {
 				http.Redirect(w, r, "/", http.StatusSeeOther)
 				return
 			}
@@ -42,28 +98,59 @@ func RedirectIfAuthenticated(store sessions.Store) func(http.Handler) http.Handl
 	}
 }
 
-func WithAuth(store sessions.Store, db *sql.DB) func(http.Handler) http.Handler {
+// WithAuth puts the logged in user, if any, in the request context. Users
+// come from users, so most requests do not touch the database. Static assets
+// never need the user and skip the lookup.
+func WithAuth(store sessions.Store, users *UserCache) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			queries := authdb.New(db)
+			if isStaticPath(r.URL.Path) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), authCheckedContextKey, true)
 			session, _ := store.Get(r, "auth-session")
 
 			userID, ok := session.Values["user_id"]
 			if ok && userID != nil {
 				userIDStr := userID.(string)
-				user, err := queries.GetUser(r.Context(), userIDStr)
-				if err == nil {
-					ctx := context.WithValue(r.Context(), UserContextKey, user)
-					next.ServeHTTP(w, r.WithContext(ctx))
-					return
+				user, err := users.Get(ctx, userIDStr)
+				if err == nil && sessionCurrent(session, user) {
+					ctx = context.WithValue(ctx, UserContextKey, user)
 				}
 			}
 
-			next.ServeHTTP(w, r)
+			next.ServeHTTP(w, r.WithContext(ctx))
 		})
 	}
 }
 
+// staticPrefixes are the paths features serve their static assets under.
+// Keep it in step with the static routes in each feature's SetupRoutes, or
+// the assets are served after a user lookup they do not need.
+var staticPrefixes = []string{
+	"/auth/static/",
+	"/common/static/",
+	"/counter/static/",
+	"/index/static/",
+	"/monitor/static/",
+	"/reverse/static/",
+	"/sortable/static/",
+}
+
+// isStaticPath reports whether path is one of the features' static assets.
+func isStaticPath(path string) bool {
+	return slices.ContainsFunc(staticPrefixes, func(prefix string) bool {
+		return strings.HasPrefix(path, prefix)
+	})
+}
+
+func GetUserFromContext(ctx context.Context) (authdb.User, bool) {
+	user, ok := ctx.Value(UserContextKey).(authdb.User)
+	return user, ok
+}
+
 func GetUserIDFromContext(ctx context.Context) string {
 	user, ok := ctx.Value(UserContextKey).(authdb.User)
 	if !ok {
@@ -77,7 +164,17 @@ func IsAuthenticated(r *http.Request, store sessions.Store, db *sql.DB) bool {
 	return user != nil
 }
 
+// GetAuthenticatedUser returns the logged in user, or nil. Behind WithAuth it
+// returns the user WithAuth found instead of looking them up again.
 func GetAuthenticatedUser(r *http.Request, store sessions.Store, db *sql.DB) (*authdb.User, error) {
+	if checked, _ := r.Context().Value(authCheckedContextKey).(bool); checked {
+		user, ok := GetUserFromContext(r.Context())
+		if !ok {
+			return nil, nil
+		}
+		return &user, nil
+	}
+
 	queries := authdb.New(db)
 	session, _ := store.Get(r, "auth-session")
 
@@ -91,10 +188,20 @@ func GetAuthenticatedUser(r *http.Request, store sessions.Store, db *sql.DB) (*a
 	if err != nil {
 		return nil, err
 	}
+	if !sessionCurrent(session, user) {
+		return nil, nil
+	}
 
 	return &user, nil
 }
 
+// sessionCurrent reports whether session was created since the user's
+// sessions were last revoked, and the user may still use it.
+func sessionCurrent(session *sessions.Session, user authdb.User) bool {
+	version, _ := session.Values[SessionVersionKey].(int64)
+	return version == user.SessionVersion && !user.Disabled
+}
+
 func GetUserAuthStatus(r *http.Request, store sessions.Store, db *sql.DB) bool {
 	return IsAuthenticated(r, store, db)
 }
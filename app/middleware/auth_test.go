@@ -0,0 +1,89 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"northstar/app/features/auth/gen/authdb"
+	"northstar/db"
+	"northstar/sessionstore"
+)
+
+func TestIsStaticPath(t *testing.T) {
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"/index/static/index.css", true},
+		{"/auth/static/web-components/passkey.js", true},
+		{"/reverse/static/reverse.js", true},
+		{"/index/static", false},
+		{"/lists/static/edit", false},
+		{"/index/todos/static/x", false},
+		{"/static/index.css", false},
+		{"/", false},
+	}
+	for _, tt := range tests {
+		if got := isStaticPath(tt.path); got != tt.want {
+			t.Errorf("isStaticPath(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
+
+// BenchmarkWithAuth compares looking up a signed in user through a UserCache
+// holding them with going to the database on every request.
+func BenchmarkWithAuth(b *testing.B) {
+	b.Chdir(b.TempDir())
+	database, err := db.InitDatabase()
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.Cleanup(func() { database.Close() })
+
+	user, err := authdb.New(database).CreateUser(b.Context(), authdb.CreateUserParams{
+		ID:       "user-1",
+		Username: "user",
+		Email:    "user@example.com",
+	})
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	store := sessionstore.New(database, []byte("test"))
+	r := httptest.NewRequestWithContext(b.Context(), http.MethodGet, "/", nil)
+	session, err := store.New(r, "auth-session")
+	if err != nil {
+		b.Fatal(err)
+	}
+	session.Values["user_id"] = user.ID
+	session.Values[SessionVersionKey] = user.SessionVersion
+	w := httptest.NewRecorder()
+	if err := store.Save(r, w, session); err != nil {
+		b.Fatal(err)
+	}
+	cookie := w.Result().Cookies()[0]
+
+	for _, bb := range []struct {
+		name string
+		ttl  time.Duration
+	}{
+		{"cached", time.Hour},
+		// a zero TTL never keeps a user, so every request queries them
+		{"database", 0},
+	} {
+		b.Run(bb.name, func(b *testing.B) {
+			handler := WithAuth(store, NewUserCache(database, bb.ttl))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if GetUserIDFromContext(r.Context()) != user.ID {
+					b.Fatal("user not signed in")
+				}
+			}))
+			for b.Loop() {
+				r := httptest.NewRequestWithContext(b.Context(), http.MethodGet, "/", nil)
+				r.AddCookie(cookie)
+				handler.ServeHTTP(httptest.NewRecorder(), r)
+			}
+		})
+	}
+}
@@ -0,0 +1,154 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+
+	"github.com/starfederation/datastar-go/datastar"
+)
+
+const (
+	// CSRFSignal is the Datastar signal, and the form field, carrying the
+	// token. layouts.Base sets it on every page.
+	CSRFSignal = "csrf"
+	// CSRFHeader carries the token on requests made outside Datastar.
+	CSRFHeader = "X-CSRF-Token"
+
+	csrfCookieName = "csrf"
+	csrfContextKey = contextKey("csrf")
+	maxCSRFBody    = 1 << 20
+
+	MsgCSRFFailed = "Your session has expired, please reload the page and try again"
+)
+
+// CSRF rejects POST, PUT, PATCH and DELETE requests that do not carry the
+// token for the browser's csrf cookie. The token is an HMAC of the cookie, so
+// another site can neither read nor forge it. Datastar requests send it in
+// the csrf signal, forms in the csrf field and other scripts in the
+// X-CSRF-Token header.
+func CSRF(secret string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var expected string
+			if cookie, err := r.Cookie(csrfCookieName); err == nil && cookie.Value != "" {
+				expected = csrfToken(secret, cookie.Value)
+			} else if safeMethod(r.Method) {
+				value := rand.Text()
+				http.SetCookie(w, &http.Cookie{
+					Name:     csrfCookieName,
+					Value:    value,
+					Path:     "/",
+					MaxAge:   86400 * 365,
+					HttpOnly: true,
+					Secure:   r.TLS != nil,
+					SameSite: http.SameSiteLaxMode,
+				})
+				expected = csrfToken(secret, value)
+			}
+
+			if !safeMethod(r.Method) {
+				token, err := requestCSRFToken(w, r)
+				if err != nil {
+					slog.Error("Error reading CSRF token", "error", err)
+				}
+				if expected == "" || !hmac.Equal([]byte(token), []byte(expected)) {
+					slog.Warn("CSRF check failed", "method", r.Method, "path", r.URL.Path)
+					csrfFailed(w, r)
+					return
+				}
+			}
+
+			ctx := context.WithValue(r.Context(), csrfContextKey, expected)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// CSRFToken returns the token requests from the current page must carry.
+func CSRFToken(ctx context.Context) string {
+	token, _ := ctx.Value(csrfContextKey).(string)
+	return token
+}
+
+func csrfToken(secret, value string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("csrf:" + value))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func safeMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+		return true
+	}
+	return false
+}
+
+// requestCSRFToken finds the token in the header, the form or the JSON body.
+// A JSON body is put back for the handler to read. Multipart forms are left
+// for the handler to parse within its own limits, so they send the header.
+func requestCSRFToken(w http.ResponseWriter, r *http.Request) (string, error) {
+	if token := r.Header.Get(CSRFHeader); token != "" {
+		return token, nil
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	switch mediaType {
+	case "application/x-www-form-urlencoded":
+		return r.PostFormValue(CSRFSignal), nil
+	case "multipart/form-data":
+		return "", nil
+	}
+
+	if r.Body == nil {
+		return "", nil
+	}
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxCSRFBody))
+	if err != nil {
+		return "", err
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	var signals struct {
+		CSRF string `json:"csrf"`
+	}
+	if len(body) > 0 {
+		// a body that is not JSON has no token, and fails the check
+		_ = json.Unmarshal(body, &signals)
+	}
+	return signals.CSRF, nil
+}
+
+// csrfFailed tells the user to reload the page. Datastar only applies patches
+// from successful responses, so those get a 200 with the error in
+// #csrf-error; fetch callers get a JSON error and everything else a 403.
+func csrfFailed(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Datastar-Request") == "true" {
+		sse := datastar.NewSSE(w, r)
+		if err := sse.PatchElements(`<p id="csrf-error" role="alert">` + MsgCSRFFailed + `</p>`); err != nil {
+			slog.Error("Failed to patch elements", "error", err)
+		}
+		return
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType == "application/json" {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusForbidden)
+		if err := json.NewEncoder(w).Encode(map[string]string{"error": MsgCSRFFailed}); err != nil {
+			slog.Error("Failed to write JSON", "error", err)
+		}
+		return
+	}
+
+	http.Error(w, MsgCSRFFailed, http.StatusForbidden)
+}
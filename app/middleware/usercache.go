@@ -0,0 +1,92 @@
+package middleware
+
+import (
+	"context"
+	"database/sql"
+	"maps"
+	"sync"
+	"time"
+
+	"northstar/app/features/auth/gen/authdb"
+)
+
+// UserCache keeps the users WithAuth loads for a short while, so a page and
+// the requests it makes right after share one query. Code that changes a user
+// must call Invalidate. Each server has its own cache, so other servers see
+// the change once the TTL runs out.
+type UserCache struct {
+	queries *authdb.Queries
+	ttl     time.Duration
+
+	mu    sync.Mutex
+	users map[string]cachedUser
+	// epoch counts invalidations, so a lookup that raced with one is not
+	// cached.
+	epoch uint64
+}
+
+type cachedUser struct {
+	user    authdb.User
+	expires time.Time
+}
+
+func NewUserCache(db *sql.DB, ttl time.Duration) *UserCache {
+	return &UserCache{
+		queries: authdb.New(db),
+		ttl:     ttl,
+		users:   make(map[string]cachedUser),
+	}
+}
+
+// Get returns the user with id, loading it when it is not cached or has
+// expired.
+func (c *UserCache) Get(ctx context.Context, id string) (authdb.User, error) {
+	now := time.Now()
+
+	c.mu.Lock()
+	cached, ok := c.users[id]
+	epoch := c.epoch
+	c.mu.Unlock()
+	if ok && now.Before(cached.expires) {
+		return cached.user, nil
+	}
+
+	user, err := c.queries.GetUser(ctx, id)
+	if err != nil {
+		return user, err
+	}
+
+	c.mu.Lock()
+	if c.epoch == epoch {
+		c.users[id] = cachedUser{user: user, expires: now.Add(c.ttl)}
+	}
+	c.mu.Unlock()
+	return user, nil
+}
+
+// Invalidate drops the user with id, so the next request loads them again.
+func (c *UserCache) Invalidate(id string) {
+	c.mu.Lock()
+	delete(c.users, id)
+	c.epoch++
+	c.mu.Unlock()
+}
+
+// RunCleanup drops expired users every interval until ctx is done.
+func (c *UserCache) RunCleanup(ctx context.Context, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case now := <-ticker.C:
+			c.mu.Lock()
+			maps.DeleteFunc(c.users, func(_ string, cached cachedUser) bool {
+				return !now.Before(cached.expires)
+			})
+			c.mu.Unlock()
+		}
+	}
+}
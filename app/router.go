@@ -7,14 +7,18 @@ import (
 	"fmt"
 	"net/http"
 	"northstar/config"
+	"northstar/mail"
 	"sync"
+	"time"
 
 	"northstar/app/middleware"
 
+	"northstar/app/features/admin"
 	"northstar/app/features/auth"
 	"northstar/app/features/common"
 	"northstar/app/features/counter"
 	"northstar/app/features/index"
+	"northstar/app/features/index/services"
 	"northstar/app/features/monitor"
 	"northstar/app/features/reverse"
 	"northstar/app/features/sortable"
@@ -23,21 +27,35 @@ import (
 	"github.com/go-chi/chi/v5"
 	"github.com/gorilla/sessions"
 	"github.com/starfederation/datastar-go/datastar"
+	"golang.org/x/sync/errgroup"
 )
 
-func SetupRoutes(ctx context.Context, router chi.Router, db *sql.DB, sessionStore sessions.Store, ns *embeddednats.Server) (err error) {
+// SetupRoutes mounts every feature on router. Background work the features
+// need runs in eg until ctx is done.
+func SetupRoutes(ctx context.Context, eg *errgroup.Group, router chi.Router, db *sql.DB, sessionStore sessions.Store, ns *embeddednats.Server, todoService *services.TodoService, listService *services.ListService) (err error) {
 	// apply optional auth middleware to all routes
-	router.Use(middleware.WithAuth(sessionStore, db))
+	users := middleware.NewUserCache(db, 30*time.Second)
+	eg.Go(func() error {
+		return users.RunCleanup(ctx, time.Minute)
+	})
+	router.Use(middleware.WithAuth(sessionStore, users))
+	// every POST, PUT, PATCH and DELETE needs the page's CSRF token
+	router.Use(middleware.CSRF(config.Global.SessionSecret))
+
+	mailer, err := mail.New()
+	if err != nil {
+		return fmt.Errorf("error setting up mailer: %w", err)
+	}
 
 	// setup auth routes
-	if err := auth.SetupRoutes(router, db, sessionStore); err != nil {
+	if err := auth.SetupRoutes(router, db, sessionStore, users, mailer, ns, todoService); err != nil {
 		return fmt.Errorf("error setting up auth routes: %w", err)
 	}
 
 	// setup unprotected routes
 	if err := errors.Join(
 		common.SetupRoutes(router),
-		index.SetupRoutes(router, sessionStore, ns),
+		index.SetupRoutes(router, sessionStore, db, todoService, listService),
 		counter.SetupRoutes(router, sessionStore),
 		monitor.SetupRoutes(router),
 		sortable.SetupRoutes(router),
@@ -46,16 +64,17 @@ func SetupRoutes(ctx context.Context, router chi.Router, db *sql.DB, sessionStor
 		return fmt.Errorf("error setting up unprotected routes: %w", err)
 	}
 
-	// TODO make some of the routes protected
-	// // setup protected routes with auth middleware
-	// var protectedRouteErr error
-	// router.Group(func(r chi.Router) {
-	// 	r.Use(auth.RequireAuth(sessionStore, db))
-	// 	protectedRouteErr = errors.Join()
-	// })
-	// if protectedRouteErr != nil {
-	// 	return fmt.Errorf("error setting up protected routes: %w", protectedRouteErr)
-	// }
+	// setup protected routes with auth middleware
+	var protectedRouteErr error
+	router.Group(func(r chi.Router) {
+		r.Use(middleware.RequireAuth(sessionStore, db))
+		protectedRouteErr = errors.Join(
+			admin.SetupRoutes(r, db, users),
+		)
+	})
+	if protectedRouteErr != nil {
+		return fmt.Errorf("error setting up protected routes: %w", protectedRouteErr)
+	}
 
 	// setup reload routes
 	if config.Global.Environment == config.Dev {
@@ -7,10 +7,12 @@ import (
 	"net"
 	"net/http"
 	app "northstar/app"
+	"northstar/app/features/index/services"
 	"northstar/config"
 	"northstar/db"
 	"northstar/logger"
 	"northstar/nats"
+	"northstar/sessionstore"
 	"os"
 	"os/signal"
 	"syscall"
@@ -18,7 +20,6 @@ import (
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
-	"github.com/gorilla/sessions"
 	"golang.org/x/sync/errgroup"
 )
 
@@ -37,6 +38,9 @@ func main() {
 
 func run(ctx context.Context) error {
 	slog.Info("Configuration loaded", "host", config.Global.Host, "port", config.Global.Port, "log_level", config.Global.LogLevel, "environment", config.Global.Environment)
+	if err := config.Global.Validate(); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
 
 	// Initialize Database
 	database, err := db.InitDatabase()
@@ -61,24 +65,35 @@ func run(ctx context.Context) error {
 
 	eg, egctx := errgroup.WithContext(ctx)
 
-	sessionKey := config.Global.SessionSecret
-	if sessionKey == "" {
-		sessionKey = "dev-session-key-change-in-production-very-long-key"
-	}
-	store := sessions.NewCookieStore([]byte(sessionKey))
+	store := sessionstore.New(database, []byte(config.Global.SessionSecret))
 	store.MaxAge(86400 * 30) // 30 days
 	store.Options.Path = "/"
 	store.Options.HttpOnly = true
 	store.Options.Secure = false // Set to true in production with HTTPS
 	store.Options.SameSite = http.SameSiteLaxMode
 
+	eg.Go(func() error {
+		return store.RunCleanup(egctx, time.Hour)
+	})
+
+	// the todo app's services are shared with auth, which deletes a user's
+	// todos along with their account
+	listService := services.NewListService(database)
+	todoService, err := services.NewTodoService(ns, store, database, listService)
+	if err != nil {
+		return fmt.Errorf("error setting up todo service: %w", err)
+	}
+	eg.Go(func() error {
+		return todoService.RunScheduler(egctx)
+	})
+
 	router := chi.NewMux()
 	router.Use(
 		middleware.Logger,
 		middleware.Recoverer,
 	)
 
-	if err := app.SetupRoutes(egctx, router, database, store, ns); err != nil {
+	if err := app.SetupRoutes(egctx, eg, router, database, store, ns, todoService, listService); err != nil {
 		return fmt.Errorf("error setting up routes: %w", err)
 	}
 
@@ -2,6 +2,7 @@ package config
 
 import (
 	"os"
+	"strings"
 	"sync"
 
 	"github.com/joho/godotenv"
@@ -14,12 +15,62 @@ const (
 	Prod Environment = "prod"
 )
 
+type TodoStorage string
+
+const (
+	TodoStorageKV     TodoStorage = "kv"
+	TodoStorageSQLite TodoStorage = "sqlite"
+)
+
+type Mailer string
+
+const (
+	// MailerLog writes outgoing mail to the log.
+	MailerLog Mailer = "log"
+	// MailerFile writes outgoing mail to files in MailDir.
+	MailerFile Mailer = "file"
+)
+
+// defaultSessionSecret and defaultEncryptionKey are what SessionSecret and
+// EncryptionKey fall back to. They are no secret, so production builds refuse
+// to start with them.
+const (
+	defaultSessionSecret = "dev-session-key-change-in-production-very-long-key"
+	defaultEncryptionKey = "dev-encryption-key-change-in-production"
+)
+
 type Config struct {
 	Environment   Environment
 	Host          string
 	Port          string
 	LogLevel      string
 	SessionSecret string
+	TodoStorage   TodoStorage
+	// BaseURL is where the app is reachable, used for links in emails and as
+	// the passkey origin. Dev builds default it to http://localhost:<Port>;
+	// elsewhere it must be set, since links are never built from requests.
+	BaseURL  string
+	Mailer   Mailer
+	MailDir  string
+	MailFrom string
+	// RequireVerifiedEmail keeps users out of pages behind RequireAuth until
+	// they have verified their email address.
+	RequireVerifiedEmail bool
+	// EncryptionKey encrypts secrets kept in the database, such as TOTP
+	// secrets. Changing it makes the existing ones unreadable. Production
+	// builds require it to be set.
+	EncryptionKey string
+	// OIDCIssuer turns on signing in with an OpenID Connect provider, such as
+	// https://accounts.google.com. The provider must allow BaseURL +
+	// "/login/oidc/callback" as a redirect URL.
+	OIDCIssuer       string
+	OIDCClientID     string
+	OIDCClientSecret string
+	// OIDCProviderName is shown on the "Sign in with" button.
+	OIDCProviderName string
+	// AdminEmails are the lowercase email addresses of the users made admins
+	// when they first verify the address, or sign up already verified.
+	AdminEmails []string
 }
 
 var (
@@ -44,9 +95,32 @@ func loadBase() *Config {
 	}
 
 	return &Config{
-		Host:          getEnv("HOST", "0.0.0.0"),
-		Port:          getEnv("PORT", "8080"),
-		LogLevel:      getEnv("LOG_LEVEL", "INFO"),
-		SessionSecret: getEnv("SESSION_SECRET", ""),
+		Host:                 getEnv("HOST", "0.0.0.0"),
+		Port:                 getEnv("PORT", "8080"),
+		LogLevel:             getEnv("LOG_LEVEL", "INFO"),
+		SessionSecret:        getEnv("SESSION_SECRET", defaultSessionSecret),
+		TodoStorage:          TodoStorage(getEnv("TODO_STORAGE", string(TodoStorageKV))),
+		BaseURL:              strings.TrimSuffix(getEnv("BASE_URL", ""), "/"),
+		Mailer:               Mailer(getEnv("MAILER", string(MailerLog))),
+		MailDir:              getEnv("MAIL_DIR", "data/mail"),
+		MailFrom:             getEnv("MAIL_FROM", "Northstar <noreply@localhost>"),
+		RequireVerifiedEmail: getEnv("REQUIRE_VERIFIED_EMAIL", "false") == "true",
+		EncryptionKey:        getEnv("ENCRYPTION_KEY", defaultEncryptionKey),
+		OIDCIssuer:           getEnv("OIDC_ISSUER", ""),
+		OIDCClientID:         getEnv("OIDC_CLIENT_ID", ""),
+		OIDCClientSecret:     getEnv("OIDC_CLIENT_SECRET", ""),
+		OIDCProviderName:     getEnv("OIDC_PROVIDER_NAME", "SSO"),
+		AdminEmails:          splitList(strings.ToLower(getEnv("ADMIN_EMAILS", ""))),
+	}
+}
+
+// splitList splits a comma separated list, dropping empty items.
+func splitList(s string) []string {
+	var items []string
+	for item := range strings.SplitSeq(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
 	}
+	return items
 }
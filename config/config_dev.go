@@ -6,5 +6,13 @@ package config
 func Load() *Config {
 	cfg := loadBase()
 	cfg.Environment = Dev
+	if cfg.BaseURL == "" {
+		cfg.BaseURL = "http://localhost:" + cfg.Port
+	}
 	return cfg
 }
+
+// Validate accepts the development defaults.
+func (c *Config) Validate() error {
+	return nil
+}
@@ -3,8 +3,21 @@
 
 package config
 
+import "errors"
+
 func Load() *Config {
 	cfg := loadBase()
 	cfg.Environment = Prod
 	return cfg
 }
+
+// Validate reports settings that are only good enough for development.
+func (c *Config) Validate() error {
+	if c.SessionSecret == "" || c.SessionSecret == defaultSessionSecret {
+		return errors.New("SESSION_SECRET must be set")
+	}
+	if c.EncryptionKey == "" || c.EncryptionKey == defaultEncryptionKey {
+		return errors.New("ENCRYPTION_KEY must be set")
+	}
+	return nil
+}
@@ -15,7 +15,7 @@ func InitDatabase() (*sql.DB, error) {
 		return nil, fmt.Errorf("failed to create data directory: %w", err)
 	}
 
-	database, err := sql.Open("sqlite", "data/northstar.db")
+	database, err := sql.Open("sqlite", "data/northstar.db?_pragma=busy_timeout(5000)")
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
@@ -0,0 +1,93 @@
+package mail
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"northstar/config"
+
+	"github.com/delaneyj/toolbelt"
+)
+
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer delivers email. Implementations must be safe for concurrent use.
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// New returns the mailer picked by config.Global.Mailer. Links in emails
+// point at config.Global.BaseURL, so it must be set.
+func New() (Mailer, error) {
+	if config.Global.BaseURL == "" {
+		return nil, errors.New("BASE_URL must be set, links in emails are built from it")
+	}
+
+	switch config.Global.Mailer {
+	case config.MailerLog:
+		return &LogMailer{From: config.Global.MailFrom}, nil
+	case config.MailerFile:
+		return NewFileMailer(config.Global.MailDir, config.Global.MailFrom)
+	default:
+		return nil, fmt.Errorf("unknown mailer %q", config.Global.Mailer)
+	}
+}
+
+// LogMailer writes every message to the log instead of sending it, which is
+// enough to follow links in emails during development.
+type LogMailer struct {
+	From string
+}
+
+func (m *LogMailer) Send(ctx context.Context, msg Message) error {
+	slog.InfoContext(ctx, "email sent", "from", m.From, "to", msg.To, "subject", msg.Subject, "body", msg.Body)
+	return nil
+}
+
+// FileMailer writes every message to its own .eml file in Dir, so emails can
+// be opened in a mail client.
+type FileMailer struct {
+	Dir  string
+	From string
+}
+
+func NewFileMailer(dir, from string) (*FileMailer, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create mail directory: %w", err)
+	}
+	return &FileMailer{Dir: dir, From: from}, nil
+}
+
+func (m *FileMailer) Send(ctx context.Context, msg Message) error {
+	now := time.Now()
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", headerValue(m.From))
+	fmt.Fprintf(&b, "To: %s\r\n", headerValue(msg.To))
+	fmt.Fprintf(&b, "Subject: %s\r\n", headerValue(msg.Subject))
+	fmt.Fprintf(&b, "Date: %s\r\n", now.Format(time.RFC1123Z))
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	b.WriteString(strings.ReplaceAll(msg.Body, "\n", "\r\n"))
+
+	name := fmt.Sprintf("%s-%s.eml", now.Format("20060102-150405"), toolbelt.NextEncodedID())
+	path := filepath.Join(m.Dir, name)
+	if err := os.WriteFile(path, []byte(b.String()), 0644); err != nil {
+		return fmt.Errorf("failed to write mail: %w", err)
+	}
+	slog.InfoContext(ctx, "email written", "to", msg.To, "subject", msg.Subject, "path", path)
+	return nil
+}
+
+// headerValue drops line breaks, which would otherwise start new headers.
+func headerValue(s string) string {
+	return strings.NewReplacer("\r", "", "\n", "").Replace(s)
+}
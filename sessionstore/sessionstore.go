@@ -0,0 +1,181 @@
+package sessionstore
+
+import (
+	"context"
+	"database/sql"
+	"encoding/base32"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"northstar/app/features/auth/gen/authdb"
+	"northstar/app/features/common/utils"
+
+	"github.com/gorilla/securecookie"
+	"github.com/gorilla/sessions"
+)
+
+// touchInterval is how stale last_seen_at may get before a request refreshes
+// it, so not every request writes to the database.
+const touchInterval = time.Minute
+
+// Store keeps session values in the sessions table and only a signed session
+// ID in the cookie, so sessions can be listed and revoked server side.
+type Store struct {
+	Codecs  []securecookie.Codec
+	Options *sessions.Options
+
+	queries *authdb.Queries
+	encoder securecookie.GobEncoder
+}
+
+var _ sessions.Store = (*Store)(nil)
+
+// New returns a store signing session IDs with keyPairs, as in
+// securecookie.CodecsFromPairs.
+func New(db *sql.DB, keyPairs ...[]byte) *Store {
+	return &Store{
+		Codecs: securecookie.CodecsFromPairs(keyPairs...),
+		Options: &sessions.Options{
+			Path:   "/",
+			MaxAge: 86400 * 30,
+		},
+		queries: authdb.New(db),
+	}
+}
+
+// MaxAge sets the maximum age of new sessions and their cookies, in seconds.
+func (s *Store) MaxAge(age int) {
+	s.Options.MaxAge = age
+	for _, codec := range s.Codecs {
+		if sc, ok := codec.(*securecookie.SecureCookie); ok {
+			sc.MaxAge(age)
+		}
+	}
+}
+
+// Get returns the session named name, loading it at most once per request.
+func (s *Store) Get(r *http.Request, name string) (*sessions.Session, error) {
+	return sessions.GetRegistry(r).Get(s, name)
+}
+
+// New loads the session named name from the database. A missing, expired or
+// revoked session yields a new, empty one.
+func (s *Store) New(r *http.Request, name string) (*sessions.Session, error) {
+	session := sessions.NewSession(s, name)
+	opts := *s.Options
+	session.Options = &opts
+	session.IsNew = true
+
+	cookie, err := r.Cookie(name)
+	if err != nil {
+		return session, nil
+	}
+	// Cookies that do not decode, such as those left by the old cookie
+	// store, are replaced on the next save rather than failing the request.
+	var id string
+	if err := securecookie.DecodeMulti(name, cookie.Value, &id, s.Codecs...); err != nil {
+		return session, nil
+	}
+
+	row, err := s.queries.GetSession(r.Context(), authdb.GetSessionParams{
+		ID:        id,
+		Name:      name,
+		ExpiresAt: time.Now(),
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return session, nil
+	}
+	if err != nil {
+		return session, fmt.Errorf("loading session: %w", err)
+	}
+	if err := s.encoder.Deserialize(row.Data, &session.Values); err != nil {
+		return session, fmt.Errorf("decoding session: %w", err)
+	}
+	session.ID = row.ID
+	session.IsNew = false
+
+	if time.Since(row.LastSeenAt) > touchInterval {
+		if err := s.queries.TouchSession(r.Context(), authdb.TouchSessionParams{
+			LastSeenAt: time.Now(),
+			UserAgent:  r.UserAgent(),
+			IpAddress:  utils.ClientIP(r),
+			ID:         row.ID,
+		}); err != nil {
+			slog.Error("failed to touch session", "error", err)
+		}
+	}
+	return session, nil
+}
+
+// Save writes session to the database and sets its cookie. A negative MaxAge
+// deletes the session.
+func (s *Store) Save(r *http.Request, w http.ResponseWriter, session *sessions.Session) error {
+	if session.Options.MaxAge < 0 {
+		if session.ID != "" {
+			if err := s.queries.DeleteSession(r.Context(), session.ID); err != nil {
+				return fmt.Errorf("deleting session: %w", err)
+			}
+		}
+		http.SetCookie(w, sessions.NewCookie(session.Name(), "", session.Options))
+		return nil
+	}
+
+	if session.ID == "" {
+		session.ID = strings.TrimRight(base32.StdEncoding.EncodeToString(securecookie.GenerateRandomKey(32)), "=")
+	}
+	data, err := s.encoder.Serialize(session.Values)
+	if err != nil {
+		return fmt.Errorf("encoding session: %w", err)
+	}
+	var userID sql.NullString
+	if id, ok := session.Values["user_id"].(string); ok && id != "" {
+		userID = sql.NullString{String: id, Valid: true}
+	}
+
+	now := time.Now()
+	if err := s.queries.SaveSession(r.Context(), authdb.SaveSessionParams{
+		ID:         session.ID,
+		Name:       session.Name(),
+		UserID:     userID,
+		Data:       data,
+		UserAgent:  r.UserAgent(),
+		IpAddress:  utils.ClientIP(r),
+		LastSeenAt: now,
+		ExpiresAt:  now.Add(time.Duration(session.Options.MaxAge) * time.Second),
+	}); err != nil {
+		return fmt.Errorf("saving session: %w", err)
+	}
+
+	encoded, err := securecookie.EncodeMulti(session.Name(), session.ID, s.Codecs...)
+	if err != nil {
+		return fmt.Errorf("encoding session cookie: %w", err)
+	}
+	http.SetCookie(w, sessions.NewCookie(session.Name(), encoded, session.Options))
+	return nil
+}
+
+// RunCleanup deletes expired sessions every interval until ctx is done.
+func (s *Store) RunCleanup(ctx context.Context, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			n, err := s.queries.DeleteExpiredSessions(ctx, time.Now())
+			if err != nil {
+				slog.Error("failed to delete expired sessions", "error", err)
+				continue
+			}
+			if n > 0 {
+				slog.Debug("deleted expired sessions", "count", n)
+			}
+		}
+	}
+}